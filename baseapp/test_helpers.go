@@ -1,6 +1,8 @@
 package baseapp
 
 import (
+	"context"
+
 	abci "github.com/tendermint/tendermint/abci/types"
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 
@@ -36,7 +38,17 @@ func (app *BaseApp) SimCheck(txEncoder sdk.TxEncoder, sdkTx sdk.Tx) (sdk.GasInfo
 
 // Simulate executes a tx in simulate mode to get result and gas info.
 func (app *BaseApp) Simulate(txBytes []byte) (sdk.GasInfo, *sdk.Result, error) {
-	ctx := app.getContextForTx(runTxModeSimulate, txBytes)
+	return app.simulate(app.getContextForTx(runTxModeSimulate, txBytes), txBytes)
+}
+
+// SimulateValidateOnly executes a tx in validate-only simulate mode, see
+// tx.WithValidateOnlySimulate, to only check whether it passes validation.
+func (app *BaseApp) SimulateValidateOnly(txBytes []byte) (sdk.GasInfo, *sdk.Result, error) {
+	ctx := sdk.UnwrapSDKContext(app.getContextForTx(runTxModeSimulate, txBytes))
+	return app.simulate(sdk.WrapSDKContext(tx.WithValidateOnlySimulate(ctx)), txBytes)
+}
+
+func (app *BaseApp) simulate(ctx context.Context, txBytes []byte) (sdk.GasInfo, *sdk.Result, error) {
 	res, err := app.txHandler.SimulateTx(ctx, tx.Request{TxBytes: txBytes})
 	gasInfo := sdk.GasInfo{
 		GasWanted: res.GasWanted,
@@ -38,11 +38,13 @@ func ValuesOf(values ...interface{}) []protoreflect.Value {
 	for i := 0; i < n; i++ {
 		// we catch the case of proto messages here and call ProtoReflect.
 		// this allows us to use imported messages, such as timestamppb.Timestamp
-		// in iterators.
+		// in iterators. Generated enum values are converted to their numbers.
 		value := values[i]
 		switch value.(type) {
 		case protoreflect.ProtoMessage:
 			value = value.(protoreflect.ProtoMessage).ProtoReflect()
+		case protoreflect.Enum:
+			value = value.(protoreflect.Enum).Number()
 		}
 		res[i] = protoreflect.ValueOf(value)
 	}
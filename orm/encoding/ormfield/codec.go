@@ -61,6 +61,24 @@ func GetCodec(field protoreflect.FieldDescriptor, nonTerminal bool) (Codec, erro
 		return nil, ormerrors.UnsupportedKeyField.Wrapf("repeated field %s", field.FullName())
 	}
 
+	return getCodec(field, nonTerminal)
+}
+
+// GetListElementCodec returns the Codec for the elements of the provided
+// repeated field if one is defined. nonTerminal has the same meaning as in
+// GetCodec.
+func GetListElementCodec(field protoreflect.FieldDescriptor, nonTerminal bool) (Codec, error) {
+	if field == nil {
+		return nil, ormerrors.UnsupportedKeyField.Wrap("nil field")
+	}
+	if !field.IsList() || field.IsMap() {
+		return nil, ormerrors.UnsupportedKeyField.Wrapf("%s is not a repeated field", field.FullName())
+	}
+
+	return getCodec(field, nonTerminal)
+}
+
+func getCodec(field protoreflect.FieldDescriptor, nonTerminal bool) (Codec, error) {
 	if field.ContainingOneof() != nil {
 		return nil, ormerrors.UnsupportedKeyField.Wrapf("oneof field %s", field.FullName())
 	}
@@ -2,12 +2,20 @@ package ormfield_test
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"math"
+	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/cosmos/cosmos-sdk/orm/encoding/ormfield"
 
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"gotest.tools/v3/assert"
 	"pgregory.net/rapid"
 
@@ -45,6 +53,26 @@ func testCodecNT(t *testing.T, fname protoreflect.Name, generator *rapid.Generat
 	})
 }
 
+func TestDescendingCodec(t *testing.T) {
+	for _, spec := range testutil.TestFieldSpecs {
+		spec := spec
+		t.Run(string(spec.FieldName), func(t *testing.T) {
+			ntCdc, err := testutil.MakeTestCodec(spec.FieldName, true)
+			assert.NilError(t, err)
+			cdc := ormfield.DescendingCodec{Codec: ntCdc}
+			rapid.Check(t, func(t *rapid.T) {
+				x := protoreflect.ValueOf(spec.Gen.Draw(t, string(spec.FieldName)))
+				bz1 := checkEncodeDecodeSize(t, x, cdc)
+				if cdc.IsOrdered() {
+					y := protoreflect.ValueOf(spec.Gen.Draw(t, fmt.Sprintf("%s 2", spec.FieldName)))
+					bz2 := checkEncodeDecodeSize(t, y, cdc)
+					assert.Equal(t, ntCdc.Compare(y, x), bytes.Compare(bz1, bz2))
+				}
+			})
+		})
+	}
+}
+
 func checkEncodeDecodeSize(t *rapid.T, x protoreflect.Value, cdc ormfield.Codec) []byte {
 	buf := &bytes.Buffer{}
 	err := cdc.Encode(x, buf)
@@ -179,3 +207,215 @@ func TestCompactUInt64(t *testing.T) {
 		assert.Equal(t, y, y2)
 	})
 }
+
+func TestNormalizedStringCodec(t *testing.T) {
+	strCdc, err := testutil.MakeTestCodec("str", true)
+	assert.NilError(t, err)
+	cdc := ormfield.NormalizedStringCodec{Codec: strCdc, Normalize: strings.ToLower}
+
+	var buf1, buf2 bytes.Buffer
+	assert.NilError(t, cdc.Encode(protoreflect.ValueOfString("Alice"), &buf1))
+	assert.NilError(t, cdc.Encode(protoreflect.ValueOfString("alice"), &buf2))
+	assert.DeepEqual(t, buf1.Bytes(), buf2.Bytes())
+	assert.Equal(t, 0, cdc.Compare(protoreflect.ValueOfString("Alice"), protoreflect.ValueOfString("ALICE")))
+	assert.Assert(t, cdc.Compare(protoreflect.ValueOfString("B"), protoreflect.ValueOfString("a")) > 0)
+
+	size, err := cdc.ComputeBufferSize(protoreflect.ValueOfString("Alice"))
+	assert.NilError(t, err)
+	assert.Assert(t, size >= buf1.Len())
+
+	// decoding returns the normalized value
+	decoded, err := cdc.Decode(bytes.NewReader(buf1.Bytes()))
+	assert.NilError(t, err)
+	assert.Equal(t, "alice", decoded.String())
+}
+
+func TestOrderedEnumCodec(t *testing.T) {
+	// enum fields keep the unordered varint encoding by default
+	defaultCdc, err := testutil.MakeTestCodec("e", false)
+	assert.NilError(t, err)
+	assert.Equal(t, ormfield.EnumCodec{}, defaultCdc)
+	assert.Assert(t, !defaultCdc.IsOrdered())
+	var varint bytes.Buffer
+	assert.NilError(t, defaultCdc.Encode(protoreflect.ValueOfEnum(-3), &varint))
+	assert.DeepEqual(t, []byte{0x05}, varint.Bytes())
+
+	cdc := ormfield.OrderedEnumCodec{}
+	assert.Assert(t, cdc.IsOrdered())
+
+	encode := func(number protoreflect.EnumNumber) []byte {
+		var buf bytes.Buffer
+		assert.NilError(t, cdc.Encode(protoreflect.ValueOfEnum(number), &buf))
+		decoded, err := cdc.Decode(bytes.NewReader(buf.Bytes()))
+		assert.NilError(t, err)
+		assert.Equal(t, number, decoded.Enum())
+		return buf.Bytes()
+	}
+
+	// encodings follow the numeric order, with gaps and negative values
+	numbers := []protoreflect.EnumNumber{math.MinInt32, -3, 0, 1, 2, 5, math.MaxInt32}
+	for i := 1; i < len(numbers); i++ {
+		assert.Assert(t, bytes.Compare(encode(numbers[i-1]), encode(numbers[i])) < 0)
+		assert.Equal(t, -1, cdc.Compare(protoreflect.ValueOfEnum(numbers[i-1]), protoreflect.ValueOfEnum(numbers[i])))
+	}
+
+	// aliased values have the same encoding
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("alias.proto"),
+		Package: proto.String("alias"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("Aliased"),
+			Options: &descriptorpb.EnumOptions{AllowAlias: proto.Bool(true)},
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("ALIASED_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("ALIASED_ONE"), Number: proto.Int32(1)},
+				{Name: proto.String("ALIASED_UNO"), Number: proto.Int32(1)},
+			},
+		}},
+	}, nil)
+	assert.NilError(t, err)
+	values := file.Enums().Get(0).Values()
+	one, uno := values.ByName("ALIASED_ONE").Number(), values.ByName("ALIASED_UNO").Number()
+	assert.DeepEqual(t, encode(one), encode(uno))
+	assert.Equal(t, 0, cdc.Compare(protoreflect.ValueOfEnum(one), protoreflect.ValueOfEnum(uno)))
+}
+
+func TestHashedBytesCodec(t *testing.T) {
+	bzCdc, err := testutil.MakeTestCodec("bz", true)
+	assert.NilError(t, err)
+	cdc := ormfield.HashedBytesCodec{Codec: bzCdc, Hash: func(bz []byte) []byte {
+		hash := sha256.Sum256(bz)
+		return hash[:]
+	}}
+	assert.Assert(t, !cdc.IsOrdered())
+
+	// values longer than 255 bytes can be encoded
+	value := protoreflect.ValueOfBytes(bytes.Repeat([]byte{1}, 1000))
+	var buf bytes.Buffer
+	assert.NilError(t, cdc.Encode(value, &buf))
+	size, err := cdc.ComputeBufferSize(value)
+	assert.NilError(t, err)
+	assert.Equal(t, size, buf.Len())
+	assert.Equal(t, 0, cdc.Compare(value, protoreflect.ValueOfBytes(bytes.Repeat([]byte{1}, 1000))))
+	assert.Assert(t, cdc.Compare(value, protoreflect.ValueOfBytes([]byte{1})) != 0)
+
+	// decoding returns the hash
+	decoded, err := cdc.Decode(bytes.NewReader(buf.Bytes()))
+	assert.NilError(t, err)
+	hash := sha256.Sum256(value.Bytes())
+	assert.DeepEqual(t, hash[:], decoded.Bytes())
+}
+
+func TestNullsLastCodec(t *testing.T) {
+	for _, spec := range testutil.TestFieldSpecs {
+		spec := spec
+		t.Run(string(spec.FieldName), func(t *testing.T) {
+			ntCdc, err := testutil.MakeTestCodec(spec.FieldName, true)
+			assert.NilError(t, err)
+			cdc := ormfield.NullsLastCodec{Codec: ntCdc}
+			null := protoreflect.Value{}
+			rapid.Check(t, func(t *rapid.T) {
+				nullBz := checkEncodeDecodeSize(t, null, cdc)
+				assert.DeepEqual(t, []byte{1}, nullBz)
+				x := protoreflect.ValueOf(spec.Gen.Draw(t, string(spec.FieldName)))
+				bz := checkEncodeDecodeSize(t, x, cdc)
+				// null values sort last, in both directions
+				assert.Equal(t, -1, cdc.Compare(x, null))
+				assert.Equal(t, -1, bytes.Compare(bz, nullBz))
+				if cdc.IsOrdered() {
+					y := protoreflect.ValueOf(spec.Gen.Draw(t, fmt.Sprintf("%s 2", spec.FieldName)))
+					bz2 := checkEncodeDecodeSize(t, y, cdc)
+					assert.Equal(t, ntCdc.Compare(x, y), bytes.Compare(bz, bz2))
+				}
+
+				descCdc := ormfield.DescendingCodec{Codec: cdc}
+				descBz := checkEncodeDecodeSize(t, x, descCdc)
+				descNullBz := checkEncodeDecodeSize(t, null, descCdc)
+				assert.Equal(t, 1, bytes.Compare(descBz, descNullBz))
+			})
+		})
+	}
+
+	// unset message fields are null
+	assert.Assert(t, ormfield.IsNull(protoreflect.ValueOfMessage((*timestamppb.Timestamp)(nil).ProtoReflect())))
+	assert.Assert(t, !ormfield.IsNull(protoreflect.ValueOfMessage(timestamppb.Now().ProtoReflect())))
+}
+
+func TestDecimalCodec(t *testing.T) {
+	cdc := ormfield.DecimalCodec{}
+	assert.Assert(t, cdc.IsOrdered())
+
+	encode := func(x string) []byte {
+		var buf bytes.Buffer
+		assert.NilError(t, cdc.Encode(protoreflect.ValueOfString(x), &buf))
+		size, err := cdc.ComputeBufferSize(protoreflect.ValueOfString(x))
+		assert.NilError(t, err)
+		assert.Equal(t, size, buf.Len())
+		return buf.Bytes()
+	}
+	decode := func(bz []byte) string {
+		r := bytes.NewReader(bz)
+		decoded, err := cdc.Decode(r)
+		assert.NilError(t, err)
+		assert.Equal(t, 0, r.Len())
+		return decoded.String()
+	}
+
+	// encodings follow the numeric order, with negatives and differing scales
+	decimals := []string{
+		"-100", "-10.25", "-10", "-9.99", "-1.5", "-1", "-0.015", "-0.01",
+		"0", "0.001", "0.01", "0.015", "0.1", "1", "1.5", "2", "9", "10", "10.25", "100", "1000000",
+	}
+	for i := 1; i < len(decimals); i++ {
+		assert.Assert(t, bytes.Compare(encode(decimals[i-1]), encode(decimals[i])) < 0, "%s < %s", decimals[i-1], decimals[i])
+		assert.Equal(t, -1, cdc.Compare(protoreflect.ValueOfString(decimals[i-1]), protoreflect.ValueOfString(decimals[i])))
+	}
+	for _, x := range decimals {
+		assert.Equal(t, x, decode(encode(x)))
+	}
+
+	// decoding returns the canonical decimal
+	for x, canonical := range map[string]string{
+		"10.250000000000000000": "10.25",
+		"007":                   "7",
+		"-0.500":                "-0.5",
+		"-0.000":                "0",
+		"":                      "0",
+		"120.0":                 "120",
+	} {
+		assert.Equal(t, canonical, decode(encode(x)))
+	}
+
+	// no encoding is a prefix of another one
+	descCdc := ormfield.DescendingCodec{Codec: cdc}
+	var buf1, buf2 bytes.Buffer
+	assert.NilError(t, descCdc.Encode(protoreflect.ValueOfString("1"), &buf1))
+	assert.NilError(t, descCdc.Encode(protoreflect.ValueOfString("1.5"), &buf2))
+	assert.Assert(t, bytes.Compare(buf1.Bytes(), buf2.Bytes()) > 0)
+
+	for _, x := range []string{"-", "1.", ".5", "1.2.3", "1e5", "+1", "abc", " 1"} {
+		err := cdc.Encode(protoreflect.ValueOfString(x), &bytes.Buffer{})
+		assert.ErrorIs(t, err, ormerrors.InvalidDecimal, x)
+	}
+
+	rapid.Check(t, func(t *rapid.T) {
+		genDecimal := rapid.Custom(func(t *rapid.T) string {
+			x := rapid.StringMatching(`-?[0-9]{1,6}`).Draw(t, "int").(string)
+			if rapid.Bool().Draw(t, "fractional").(bool) {
+				x += "." + rapid.StringMatching(`[0-9]{1,6}`).Draw(t, "frac").(string)
+			}
+			return x
+		})
+		x := genDecimal.Draw(t, "x").(string)
+		y := genDecimal.Draw(t, "y").(string)
+		ratX, ok := new(big.Rat).SetString(x)
+		assert.Assert(t, ok)
+		ratY, ok := new(big.Rat).SetString(y)
+		assert.Assert(t, ok)
+
+		bz := checkEncodeDecodeSize(t, protoreflect.ValueOfString(x), cdc)
+		bz2 := checkEncodeDecodeSize(t, protoreflect.ValueOfString(y), cdc)
+		assert.Equal(t, ratX.Cmp(ratY), bytes.Compare(bz, bz2))
+	})
+}
@@ -0,0 +1,218 @@
+package ormfield
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+const (
+	decimalNegative = 0x00
+	decimalZero     = 0x01
+	decimalPositive = 0x02
+
+	decimalTerminator = 0x00
+)
+
+// DecimalCodec encodes string fields holding decimal numbers, such as the
+// string representation of sdk.Dec, so that the byte order of encoded values
+// is the numeric order of the decimals, regardless of their sign and scale,
+// instead of the lexicographic order of the strings. Values are an optional
+// minus sign followed by digits and an optional fractional part, like "-1.5"
+// or "10.250000", and the empty string, the default value of string fields,
+// is encoded as zero. The encoding is a sign byte followed, for non-zero
+// values, by the decimal exponent and the significant digits of the absolute
+// value, all complemented for negative values. The encoding is lossy:
+// decoded values are the canonical decimals, without insignificant zeros,
+// for instance "10.25" for "10.250000". No encoded value is a prefix of
+// another one, so DecimalCodec can be used for non-terminal fields and
+// wrapped in a DescendingCodec.
+type DecimalCodec struct{}
+
+// decimal is a parsed decimal number whose value, for non-zero numbers, is
+// 0.digits * 10^exp with digits having no leading or trailing zeros.
+type decimal struct {
+	negative bool
+	digits   string
+	exp      int32
+}
+
+func parseDecimal(s string) (decimal, error) {
+	if s == "" {
+		return decimal{}, nil
+	}
+
+	var d decimal
+	str := s
+	if strings.HasPrefix(str, "-") {
+		d.negative = true
+		str = str[1:]
+	}
+
+	intPart, fracPart := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		intPart, fracPart = str[:i], str[i+1:]
+		if fracPart == "" {
+			return decimal{}, ormerrors.InvalidDecimal.Wrapf("%q has no digits after the decimal point", s)
+		}
+	}
+	if intPart == "" {
+		return decimal{}, ormerrors.InvalidDecimal.Wrapf("%q has no integer digits", s)
+	}
+	for _, c := range intPart + fracPart {
+		if c < '0' || c > '9' {
+			return decimal{}, ormerrors.InvalidDecimal.Wrapf("%q contains the non-digit character %q", s, c)
+		}
+	}
+
+	intPart = strings.TrimLeft(intPart, "0")
+	exp := len(intPart)
+	digits := intPart + fracPart
+	if intPart == "" {
+		// the first significant digit is in the fractional part
+		trimmed := strings.TrimLeft(fracPart, "0")
+		exp = len(trimmed) - len(fracPart)
+		digits = trimmed
+	}
+	digits = strings.TrimRight(digits, "0")
+	if digits == "" {
+		return decimal{}, nil
+	}
+
+	d.digits = digits
+	d.exp = int32(exp)
+	return d, nil
+}
+
+func (d decimal) String() string {
+	if d.digits == "" {
+		return "0"
+	}
+
+	var s string
+	n := int(d.exp)
+	switch {
+	case n <= 0:
+		s = "0." + strings.Repeat("0", -n) + d.digits
+	case n >= len(d.digits):
+		s = d.digits + strings.Repeat("0", n-len(d.digits))
+	default:
+		s = d.digits[:n] + "." + d.digits[n:]
+	}
+
+	if d.negative {
+		return "-" + s
+	}
+	return s
+}
+
+func (d decimal) encode() []byte {
+	if d.digits == "" {
+		return []byte{decimalZero}
+	}
+
+	bz := make([]byte, 5, 6+len(d.digits))
+	binary.BigEndian.PutUint32(bz[1:], uint32(d.exp)^0x80000000)
+	bz = append(bz, d.digits...)
+	bz = append(bz, decimalTerminator)
+	if d.negative {
+		// complementing the bytes of the absolute value reverses their order
+		for i := 1; i < len(bz); i++ {
+			bz[i] = ^bz[i]
+		}
+		bz[0] = decimalNegative
+	} else {
+		bz[0] = decimalPositive
+	}
+
+	return bz
+}
+
+func (d DecimalCodec) Decode(r Reader) (protoreflect.Value, error) {
+	sign, err := r.ReadByte()
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+
+	var mask byte
+	switch sign {
+	case decimalZero:
+		return protoreflect.ValueOfString("0"), nil
+	case decimalNegative:
+		mask = 0xFF
+	case decimalPositive:
+	default:
+		return protoreflect.Value{}, ormerrors.UnexpectedDecodePrefix.Wrapf("invalid decimal sign byte %x", sign)
+	}
+
+	var expBz [4]byte
+	if _, err = io.ReadFull(r, expBz[:]); err != nil {
+		return protoreflect.Value{}, err
+	}
+	for i := range expBz {
+		expBz[i] ^= mask
+	}
+
+	var digits []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		b ^= mask
+		if b == decimalTerminator {
+			break
+		}
+		digits = append(digits, b)
+	}
+
+	return protoreflect.ValueOfString(decimal{
+		negative: mask != 0,
+		digits:   string(digits),
+		exp:      int32(binary.BigEndian.Uint32(expBz[:]) ^ 0x80000000),
+	}.String()), nil
+}
+
+func (d DecimalCodec) Encode(value protoreflect.Value, w io.Writer) error {
+	dec, err := parseDecimal(value.String())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(dec.encode())
+	return err
+}
+
+// Compare compares the decimals numerically, falling back to comparing the
+// strings if one of them isn't a valid decimal.
+func (d DecimalCodec) Compare(v1, v2 protoreflect.Value) int {
+	dec1, err1 := parseDecimal(v1.String())
+	dec2, err2 := parseDecimal(v2.String())
+	if err1 != nil || err2 != nil {
+		return strings.Compare(v1.String(), v2.String())
+	}
+
+	return bytes.Compare(dec1.encode(), dec2.encode())
+}
+
+func (d DecimalCodec) IsOrdered() bool {
+	return true
+}
+
+func (d DecimalCodec) FixedBufferSize() int {
+	return -1
+}
+
+func (d DecimalCodec) ComputeBufferSize(value protoreflect.Value) (int, error) {
+	dec, err := parseDecimal(value.String())
+	if err != nil {
+		return 0, err
+	}
+
+	return len(dec.encode()), nil
+}
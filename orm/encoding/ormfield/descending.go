@@ -0,0 +1,59 @@
+package ormfield
+
+import (
+	"bytes"
+	"io"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DescendingCodec wraps a Codec and inverts the bits of its encoded values so
+// that the byte order of encoded values is the reverse of the order of the
+// wrapped codec. The wrapped codec must be a non-terminal codec, because
+// inverting the encoding only reverses the order of values when no encoded
+// value is a prefix of another one.
+type DescendingCodec struct {
+	Codec
+}
+
+func (d DescendingCodec) Compare(v1, v2 protoreflect.Value) int {
+	return -d.Codec.Compare(v1, v2)
+}
+
+func (d DescendingCodec) Decode(r Reader) (protoreflect.Value, error) {
+	return d.Codec.Decode(invertingReader{r})
+}
+
+func (d DescendingCodec) Encode(value protoreflect.Value, w io.Writer) error {
+	var buf bytes.Buffer
+	err := d.Codec.Encode(value, &buf)
+	if err != nil {
+		return err
+	}
+
+	bz := buf.Bytes()
+	invertBytes(bz)
+	_, err = w.Write(bz)
+	return err
+}
+
+type invertingReader struct {
+	Reader
+}
+
+func (r invertingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	invertBytes(p[:n])
+	return n, err
+}
+
+func (r invertingReader) ReadByte() (byte, error) {
+	b, err := r.Reader.ReadByte()
+	return ^b, err
+}
+
+func invertBytes(bz []byte) {
+	for i := range bz {
+		bz[i] = ^bz[i]
+	}
+}
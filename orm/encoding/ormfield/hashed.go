@@ -0,0 +1,38 @@
+package ormfield
+
+import (
+	"io"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// HashedBytesCodec wraps a bytes Codec and encodes the hashes of values
+// computed with Hash, for instance a sha256 hash, instead of the values
+// themselves, so that large values can be used in keys. Values are compared by
+// their hashes, so the encoding isn't ordered and only supports equality
+// lookups. The encoding is lossy: decoded values are the hashes, not the
+// original values.
+type HashedBytesCodec struct {
+	Codec
+	Hash func([]byte) []byte
+}
+
+func (h HashedBytesCodec) hash(value protoreflect.Value) protoreflect.Value {
+	return protoreflect.ValueOfBytes(h.Hash(value.Bytes()))
+}
+
+func (h HashedBytesCodec) Encode(value protoreflect.Value, w io.Writer) error {
+	return h.Codec.Encode(h.hash(value), w)
+}
+
+func (h HashedBytesCodec) Compare(v1, v2 protoreflect.Value) int {
+	return h.Codec.Compare(h.hash(v1), h.hash(v2))
+}
+
+func (h HashedBytesCodec) IsOrdered() bool {
+	return false
+}
+
+func (h HashedBytesCodec) ComputeBufferSize(value protoreflect.Value) (int, error) {
+	return h.Codec.ComputeBufferSize(h.hash(value))
+}
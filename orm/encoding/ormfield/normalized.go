@@ -0,0 +1,33 @@
+package ormfield
+
+import (
+	"io"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// NormalizedStringCodec wraps a string Codec and normalizes values with
+// Normalize, for instance strings.ToLower, before encoding and comparing them.
+// Values which normalize to the same string thus have the same encoding. The
+// encoding is lossy: decoded values are the normalized values, not the
+// original ones.
+type NormalizedStringCodec struct {
+	Codec
+	Normalize func(string) string
+}
+
+func (n NormalizedStringCodec) normalize(value protoreflect.Value) protoreflect.Value {
+	return protoreflect.ValueOfString(n.Normalize(value.String()))
+}
+
+func (n NormalizedStringCodec) Encode(value protoreflect.Value, w io.Writer) error {
+	return n.Codec.Encode(n.normalize(value), w)
+}
+
+func (n NormalizedStringCodec) Compare(v1, v2 protoreflect.Value) int {
+	return n.Codec.Compare(n.normalize(v1), n.normalize(v2))
+}
+
+func (n NormalizedStringCodec) ComputeBufferSize(value protoreflect.Value) (int, error) {
+	return n.Codec.ComputeBufferSize(n.normalize(value))
+}
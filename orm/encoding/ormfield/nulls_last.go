@@ -0,0 +1,93 @@
+package ormfield
+
+import (
+	"io"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+const (
+	nullsLastPresent = 0x00
+	nullsLastNull    = 0x01
+)
+
+// NullsLastCodec wraps a Codec and prefixes encoded values with a presence
+// byte, so that null values, which are encoded as the presence byte alone,
+// sort after all the non-null values, like NULLS LAST in SQL. A value is null
+// when it is invalid, i.e. the zero protoreflect.Value, or when it is an
+// invalid message, i.e. the value of an unset message field. Null values are
+// decoded as the zero protoreflect.Value. Because the presence byte differs,
+// no encoded value is a prefix of another one if the wrapped codec is a
+// non-terminal codec, so NullsLastCodec can be wrapped in a DescendingCodec,
+// which then sorts null values first.
+type NullsLastCodec struct {
+	Codec
+}
+
+// IsNull returns true if value is null for NullsLastCodec.
+func IsNull(value protoreflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+
+	msg, ok := value.Interface().(protoreflect.Message)
+	return ok && !msg.IsValid()
+}
+
+func (n NullsLastCodec) Decode(r Reader) (protoreflect.Value, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+
+	switch b {
+	case nullsLastPresent:
+		return n.Codec.Decode(r)
+	case nullsLastNull:
+		return protoreflect.Value{}, nil
+	default:
+		return protoreflect.Value{}, ormerrors.UnexpectedDecodePrefix.Wrapf("invalid presence byte %x", b)
+	}
+}
+
+func (n NullsLastCodec) Encode(value protoreflect.Value, w io.Writer) error {
+	if IsNull(value) {
+		_, err := w.Write([]byte{nullsLastNull})
+		return err
+	}
+
+	if _, err := w.Write([]byte{nullsLastPresent}); err != nil {
+		return err
+	}
+	return n.Codec.Encode(value, w)
+}
+
+func (n NullsLastCodec) Compare(v1, v2 protoreflect.Value) int {
+	null1, null2 := IsNull(v1), IsNull(v2)
+	switch {
+	case null1 && null2:
+		return 0
+	case null1:
+		return 1
+	case null2:
+		return -1
+	default:
+		return n.Codec.Compare(v1, v2)
+	}
+}
+
+func (n NullsLastCodec) FixedBufferSize() int {
+	// null values are shorter than non-null values
+	return -1
+}
+
+func (n NullsLastCodec) ComputeBufferSize(value protoreflect.Value) (int, error) {
+	if IsNull(value) {
+		return 1, nil
+	}
+
+	size, err := n.Codec.ComputeBufferSize(value)
+	return size + 1, err
+}
@@ -0,0 +1,45 @@
+package ormfield
+
+import (
+	"encoding/binary"
+	io "io"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// OrderedEnumCodec encodes enum values as their int32 numbers, using the same
+// encoding as Int32Codec, so that enum values can be used for ordered
+// iteration which follows the numeric order of the enum values, including for
+// negative values. Aliased enum values share the same number and thus have the
+// same encoding. Its encoding differs from the varint encoding of EnumCodec,
+// so it must only be used for keys which were never encoded with EnumCodec.
+type OrderedEnumCodec struct{}
+
+func (e OrderedEnumCodec) Decode(r Reader) (protoreflect.Value, error) {
+	var x uint32
+	err := binary.Read(r, binary.BigEndian, &x)
+	y := int64(x) - int32Offset
+	return protoreflect.ValueOfEnum(protoreflect.EnumNumber(y)), err
+}
+
+func (e OrderedEnumCodec) Encode(value protoreflect.Value, w io.Writer) error {
+	x := int64(value.Enum())
+	x += int32Offset
+	return binary.Write(w, binary.BigEndian, uint32(x))
+}
+
+func (e OrderedEnumCodec) Compare(v1, v2 protoreflect.Value) int {
+	return EnumCodec{}.Compare(v1, v2)
+}
+
+func (e OrderedEnumCodec) IsOrdered() bool {
+	return true
+}
+
+func (e OrderedEnumCodec) FixedBufferSize() int {
+	return 4
+}
+
+func (e OrderedEnumCodec) ComputeBufferSize(protoreflect.Value) (int, error) {
+	return e.FixedBufferSize(), nil
+}
@@ -6,9 +6,10 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-// TimestampCodec DurationCodec encodes a google.protobuf.Timestamp value as 12 bytes using
+// TimestampCodec encodes a google.protobuf.Timestamp value as 12 bytes using
 // Int64Codec for seconds followed by Int32Codec for nanos. This allows for
-// sorted iteration.
+// sorted iteration in chronological order, including for timestamps before
+// the Unix epoch since Int64Codec preserves the ordering of negative values.
 type TimestampCodec struct{}
 
 var (
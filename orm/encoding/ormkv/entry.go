@@ -136,4 +136,62 @@ func (s *SeqEntry) String() string {
 	return fmt.Sprintf("SEQ %s %d", s.TableName, s.Value)
 }
 
-var _, _, _ Entry = &PrimaryKeyEntry{}, &IndexKeyEntry{}, &SeqEntry{}
+// SumEntry represents the sum stored by a sum aggregate for a prefix of the
+// values of its group fields.
+type SumEntry struct {
+
+	// TableName is the table this entry represents.
+	TableName protoreflect.FullName
+
+	// Fields are the group fields of the sum aggregate.
+	Fields []protoreflect.Name
+
+	// Prefix represents the group field values the sum is over.
+	Prefix []protoreflect.Value
+
+	// Sum is the sum of the aggregated field over the entries with the prefix.
+	Sum int64
+}
+
+func (s *SumEntry) GetTableName() protoreflect.FullName {
+	return s.TableName
+}
+
+func (s *SumEntry) doNotImplement() {}
+
+func (s *SumEntry) String() string {
+	return fmt.Sprintf("SUM %s %s : %s -> %d", s.TableName, fmtFields(s.Fields), fmtValues(s.Prefix), s.Sum)
+}
+
+// MinMaxEntry represents an entry tracked by a min/max aggregate for a prefix
+// of the values of its group fields.
+type MinMaxEntry struct {
+
+	// TableName is the table this entry represents.
+	TableName protoreflect.FullName
+
+	// Fields are the group fields of the min/max aggregate.
+	Fields []protoreflect.Name
+
+	// Prefix represents the group field values the entry is tracked for.
+	Prefix []protoreflect.Value
+
+	// Value is the value of the aggregated field of the entry.
+	Value protoreflect.Value
+
+	// PrimaryKey represents the primary key values of the entry.
+	PrimaryKey []protoreflect.Value
+}
+
+func (m *MinMaxEntry) GetTableName() protoreflect.FullName {
+	return m.TableName
+}
+
+func (m *MinMaxEntry) doNotImplement() {}
+
+func (m *MinMaxEntry) String() string {
+	return fmt.Sprintf("MINMAX %s %s : %s : %v -> %s", m.TableName, fmtFields(m.Fields), fmtValues(m.Prefix),
+		m.Value.Interface(), fmtValues(m.PrimaryKey))
+}
+
+var _, _, _, _, _ Entry = &PrimaryKeyEntry{}, &IndexKeyEntry{}, &SeqEntry{}, &SumEntry{}, &MinMaxEntry{}
@@ -73,3 +73,43 @@ func TestIndexKeyEntry(t *testing.T) {
 	assert.Equal(t, `UNIQ testpb.ExampleTable str/i32 : abc/1 -> _`, entry.String())
 	assert.Equal(t, aFullName, entry.GetTableName())
 }
+
+func TestSumEntry(t *testing.T) {
+	entry := &ormkv.SumEntry{
+		TableName: aFullName,
+		Fields:    []protoreflect.Name{"str", "b"},
+		Prefix:    encodeutil.ValuesOf("abc"),
+		Sum:       -10,
+	}
+	assert.Equal(t, `SUM testpb.ExampleTable str/b : abc -> -10`, entry.String())
+	assert.Equal(t, aFullName, entry.GetTableName())
+
+	// total sum
+	entry = &ormkv.SumEntry{
+		TableName: aFullName,
+		Fields:    []protoreflect.Name{"str", "b"},
+		Sum:       3,
+	}
+	assert.Equal(t, `SUM testpb.ExampleTable str/b : _ -> 3`, entry.String())
+}
+
+func TestMinMaxEntry(t *testing.T) {
+	entry := &ormkv.MinMaxEntry{
+		TableName:  aFullName,
+		Fields:     []protoreflect.Name{"str", "b"},
+		Prefix:     encodeutil.ValuesOf("abc"),
+		Value:      protoreflect.ValueOfInt32(-10),
+		PrimaryKey: encodeutil.ValuesOf(uint32(1), "abc"),
+	}
+	assert.Equal(t, `MINMAX testpb.ExampleTable str/b : abc : -10 -> 1/abc`, entry.String())
+	assert.Equal(t, aFullName, entry.GetTableName())
+
+	// entry tracked for all entries
+	entry = &ormkv.MinMaxEntry{
+		TableName:  aFullName,
+		Fields:     []protoreflect.Name{"str", "b"},
+		Value:      protoreflect.ValueOfInt32(3),
+		PrimaryKey: encodeutil.ValuesOf(uint32(1), "abc"),
+	}
+	assert.Equal(t, `MINMAX testpb.ExampleTable str/b : _ : 3 -> 1/abc`, entry.String())
+}
@@ -18,7 +18,9 @@ type IndexKeyCodec struct {
 var _ IndexCodec = &IndexKeyCodec{}
 
 // NewIndexKeyCodec creates a new IndexKeyCodec with an optional prefix for the
-// provided message descriptor, index and primary key fields.
+// provided message descriptor, index and primary key fields. One of the index
+// fields can be a repeated field, in which case messages have one index entry
+// per distinct element of the field.
 func NewIndexKeyCodec(prefix []byte, messageType protoreflect.MessageType, indexFields, primaryKeyFields []protoreflect.Name) (*IndexKeyCodec, error) {
 	if len(indexFields) == 0 {
 		return nil, ormerrors.InvalidTableDefinition.Wrapf("index fields are empty")
@@ -50,7 +52,7 @@ func NewIndexKeyCodec(prefix []byte, messageType protoreflect.MessageType, index
 		k++
 	}
 
-	cdc, err := NewKeyCodec(prefix, messageType, keyFields)
+	cdc, err := newKeyCodec(prefix, messageType, keyFields, keyCodecOptions{allowList: true})
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +63,90 @@ func NewIndexKeyCodec(prefix []byte, messageType protoreflect.MessageType, index
 	}, nil
 }
 
+// Descending returns a copy of the codec which encodes keys in descending
+// order, see KeyCodec.Descending.
+func (cdc *IndexKeyCodec) Descending() (*IndexKeyCodec, error) {
+	keyCodec, err := cdc.KeyCodec.Descending()
+	if err != nil {
+		return nil, err
+	}
+
+	return &IndexKeyCodec{
+		KeyCodec:     keyCodec,
+		pkFieldOrder: cdc.pkFieldOrder,
+	}, nil
+}
+
+// NormalizeStrings returns a copy of the codec which normalizes string
+// values, see KeyCodec.NormalizeStrings.
+func (cdc *IndexKeyCodec) NormalizeStrings(normalize func(string) string) (*IndexKeyCodec, error) {
+	keyCodec, err := cdc.KeyCodec.NormalizeStrings(normalize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IndexKeyCodec{
+		KeyCodec:     keyCodec,
+		pkFieldOrder: cdc.pkFieldOrder,
+	}, nil
+}
+
+// HashBytesFields returns a copy of the codec which hashes the values of the
+// provided bytes fields, see KeyCodec.HashBytesFields.
+func (cdc *IndexKeyCodec) HashBytesFields(fields []protoreflect.Name, hash func([]byte) []byte) (*IndexKeyCodec, error) {
+	keyCodec, err := cdc.KeyCodec.HashBytesFields(fields, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IndexKeyCodec{
+		KeyCodec:     keyCodec,
+		pkFieldOrder: cdc.pkFieldOrder,
+	}, nil
+}
+
+// DecimalFields returns a copy of the codec which encodes the values of the
+// provided string fields as decimal numbers, see KeyCodec.DecimalFields.
+func (cdc *IndexKeyCodec) DecimalFields(fields []protoreflect.Name) (*IndexKeyCodec, error) {
+	keyCodec, err := cdc.KeyCodec.DecimalFields(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IndexKeyCodec{
+		KeyCodec:     keyCodec,
+		pkFieldOrder: cdc.pkFieldOrder,
+	}, nil
+}
+
+// OrderedEnums returns a copy of the codec which encodes the values of enum
+// fields in their numeric order, see KeyCodec.OrderedEnums.
+func (cdc *IndexKeyCodec) OrderedEnums() (*IndexKeyCodec, error) {
+	keyCodec, err := cdc.KeyCodec.OrderedEnums()
+	if err != nil {
+		return nil, err
+	}
+
+	return &IndexKeyCodec{
+		KeyCodec:     keyCodec,
+		pkFieldOrder: cdc.pkFieldOrder,
+	}, nil
+}
+
+// NullsLast returns a copy of the codec which sorts the unset values of the
+// provided fields last, see KeyCodec.NullsLast.
+func (cdc *IndexKeyCodec) NullsLast(fields []protoreflect.Name) (*IndexKeyCodec, error) {
+	keyCodec, err := cdc.KeyCodec.NullsLast(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IndexKeyCodec{
+		KeyCodec:     keyCodec,
+		pkFieldOrder: cdc.pkFieldOrder,
+	}, nil
+}
+
 func (cdc IndexKeyCodec) DecodeIndexKey(k, _ []byte) (indexFields, primaryKey []protoreflect.Value, err error) {
 
 	values, err := cdc.DecodeKey(bytes.NewReader(k))
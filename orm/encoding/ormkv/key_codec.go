@@ -3,6 +3,7 @@ package ormkv
 import (
 	"bytes"
 	"io"
+	"sort"
 
 	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
 
@@ -24,11 +25,114 @@ type KeyCodec struct {
 	fieldNames       []protoreflect.Name
 	fieldCodecs      []ormfield.Codec
 	messageType      protoreflect.MessageType
+	options          keyCodecOptions
+
+	// listField is the index of the repeated field of the key, or -1 if there
+	// is none.
+	listField int
+}
+
+type keyCodecOptions struct {
+	descending bool
+
+	// allowList allows a single key field to be a repeated field.
+	allowList bool
+
+	// normalizeString normalizes the values of string fields, if set.
+	normalizeString func(string) string
+
+	// hashedFields are the bytes fields whose values are hashed with hash.
+	hashedFields map[protoreflect.Name]bool
+	hash         func([]byte) []byte
+
+	// nullsLastFields are the fields with presence whose unset values are
+	// encoded as nulls sorting after all the set values.
+	nullsLastFields map[protoreflect.Name]bool
+
+	// decimalFields are the string fields whose values are decimal numbers.
+	decimalFields map[protoreflect.Name]bool
+
+	// orderedEnums encodes enum fields with ormfield.OrderedEnumCodec.
+	orderedEnums bool
 }
 
 // NewKeyCodec returns a new KeyCodec with an optional prefix for the provided
 // message descriptor and fields.
 func NewKeyCodec(prefix []byte, messageType protoreflect.MessageType, fieldNames []protoreflect.Name) (*KeyCodec, error) {
+	return newKeyCodec(prefix, messageType, fieldNames, keyCodecOptions{})
+}
+
+// Descending returns a copy of the codec which encodes keys so that their
+// byte order is the reverse of the order of their values. Forward iteration
+// over keys encoded with it thus returns values in descending order.
+func (cdc *KeyCodec) Descending() (*KeyCodec, error) {
+	options := cdc.options
+	options.descending = true
+	return newKeyCodec(cdc.prefix, cdc.messageType, cdc.fieldNames, options)
+}
+
+// NormalizeStrings returns a copy of the codec which normalizes the values of
+// string fields with normalize before encoding and comparing them, see
+// ormfield.NormalizedStringCodec. Decoded string values are the normalized
+// values.
+func (cdc *KeyCodec) NormalizeStrings(normalize func(string) string) (*KeyCodec, error) {
+	options := cdc.options
+	options.normalizeString = normalize
+	return newKeyCodec(cdc.prefix, cdc.messageType, cdc.fieldNames, options)
+}
+
+// HashBytesFields returns a copy of the codec which encodes the hashes of the
+// values of the provided bytes fields computed with hash instead of the values
+// themselves, see ormfield.HashedBytesCodec. Decoded values of these fields
+// are the hashes.
+func (cdc *KeyCodec) HashBytesFields(fields []protoreflect.Name, hash func([]byte) []byte) (*KeyCodec, error) {
+	options := cdc.options
+	options.hashedFields = map[protoreflect.Name]bool{}
+	for _, field := range fields {
+		options.hashedFields[field] = true
+	}
+	options.hash = hash
+	return newKeyCodec(cdc.prefix, cdc.messageType, cdc.fieldNames, options)
+}
+
+// NullsLast returns a copy of the codec which encodes the values of the
+// provided fields, which must have presence, so that unset values sort after
+// all the set values, see ormfield.NullsLastCodec. GetKeyValues returns the
+// zero protoreflect.Value for these fields when they are unset, and
+// SetKeyValues clears them for such values.
+func (cdc *KeyCodec) NullsLast(fields []protoreflect.Name) (*KeyCodec, error) {
+	options := cdc.options
+	options.nullsLastFields = map[protoreflect.Name]bool{}
+	for _, field := range fields {
+		options.nullsLastFields[field] = true
+	}
+	return newKeyCodec(cdc.prefix, cdc.messageType, cdc.fieldNames, options)
+}
+
+// DecimalFields returns a copy of the codec which encodes the values of the
+// provided string fields as decimal numbers, so that keys sort in the numeric
+// order of these values, see ormfield.DecimalCodec. Decoded values of these
+// fields are the canonical decimals.
+func (cdc *KeyCodec) DecimalFields(fields []protoreflect.Name) (*KeyCodec, error) {
+	options := cdc.options
+	options.decimalFields = map[protoreflect.Name]bool{}
+	for _, field := range fields {
+		options.decimalFields[field] = true
+	}
+	return newKeyCodec(cdc.prefix, cdc.messageType, cdc.fieldNames, options)
+}
+
+// OrderedEnums returns a copy of the codec which encodes the values of enum
+// fields so that keys sort in the numeric order of these values, see
+// ormfield.OrderedEnumCodec. The encoding differs from the default one, so it
+// can't be enabled for keys which were already written.
+func (cdc *KeyCodec) OrderedEnums() (*KeyCodec, error) {
+	options := cdc.options
+	options.orderedEnums = true
+	return newKeyCodec(cdc.prefix, cdc.messageType, cdc.fieldNames, options)
+}
+
+func newKeyCodec(prefix []byte, messageType protoreflect.MessageType, fieldNames []protoreflect.Name, options keyCodecOptions) (*KeyCodec, error) {
 	n := len(fieldNames)
 	fieldCodecs := make([]ormfield.Codec, n)
 	fieldDescriptors := make([]protoreflect.FieldDescriptor, n)
@@ -37,18 +141,58 @@ func NewKeyCodec(prefix []byte, messageType protoreflect.MessageType, fieldNames
 		i   int
 	}
 	fixedSize := 0
+	listField := -1
 	messageFields := messageType.Descriptor().Fields()
 
 	for i := 0; i < n; i++ {
-		nonTerminal := i != n-1
+		// descending keys need all values to be encoded as non-terminal
+		// values, see ormfield.DescendingCodec
+		nonTerminal := i != n-1 || options.descending
 		field := messageFields.ByName(fieldNames[i])
 		if field == nil {
 			return nil, ormerrors.FieldNotFound.Wrapf("field %s on %s", fieldNames[i], messageType.Descriptor().FullName())
 		}
-		cdc, err := ormfield.GetCodec(field, nonTerminal)
+		var cdc ormfield.Codec
+		var err error
+		if options.allowList && field.IsList() {
+			if listField >= 0 {
+				return nil, ormerrors.UnsupportedKeyField.Wrapf("more than one repeated field in key: %s", field.FullName())
+			}
+			listField = i
+			cdc, err = ormfield.GetListElementCodec(field, nonTerminal)
+		} else {
+			cdc, err = ormfield.GetCodec(field, nonTerminal)
+		}
 		if err != nil {
 			return nil, err
 		}
+		if options.orderedEnums && field.Kind() == protoreflect.EnumKind && !field.IsList() {
+			cdc = ormfield.OrderedEnumCodec{}
+		}
+		if options.normalizeString != nil && field.Kind() == protoreflect.StringKind {
+			cdc = ormfield.NormalizedStringCodec{Codec: cdc, Normalize: options.normalizeString}
+		}
+		if options.hashedFields[fieldNames[i]] {
+			if field.Kind() != protoreflect.BytesKind || field.IsList() {
+				return nil, ormerrors.UnsupportedKeyField.Wrapf("can't hash non-bytes field %s", field.FullName())
+			}
+			cdc = ormfield.HashedBytesCodec{Codec: cdc, Hash: options.hash}
+		}
+		if options.decimalFields[fieldNames[i]] {
+			if field.Kind() != protoreflect.StringKind || field.IsList() {
+				return nil, ormerrors.UnsupportedKeyField.Wrapf("can't encode non-string field %s as a decimal", field.FullName())
+			}
+			cdc = ormfield.DecimalCodec{}
+		}
+		if options.nullsLastFields[fieldNames[i]] {
+			if !field.HasPresence() || field.IsList() {
+				return nil, ormerrors.UnsupportedKeyField.Wrapf("field %s without presence can't be null", field.FullName())
+			}
+			cdc = ormfield.NullsLastCodec{Codec: cdc}
+		}
+		if options.descending {
+			cdc = ormfield.DescendingCodec{Codec: cdc}
+		}
 		if x := cdc.FixedBufferSize(); x > 0 {
 			fixedSize += x
 		} else {
@@ -69,6 +213,8 @@ func NewKeyCodec(prefix []byte, messageType protoreflect.MessageType, fieldNames
 		fixedSize:        fixedSize,
 		variableSizers:   variableSizers,
 		messageType:      messageType,
+		options:          options,
+		listField:        listField,
 	}, nil
 }
 
@@ -104,6 +250,10 @@ func (cdc *KeyCodec) EncodeKey(values []protoreflect.Value) ([]byte, error) {
 func (cdc *KeyCodec) GetKeyValues(message protoreflect.Message) []protoreflect.Value {
 	res := make([]protoreflect.Value, len(cdc.fieldDescriptors))
 	for i, f := range cdc.fieldDescriptors {
+		if cdc.options.nullsLastFields[f.Name()] && !message.Has(f) {
+			// null value, see NullsLast
+			continue
+		}
 		res[i] = message.Get(f)
 	}
 	return res
@@ -131,13 +281,53 @@ func (cdc *KeyCodec) DecodeKey(r *bytes.Reader) ([]protoreflect.Value, error) {
 	return values, nil
 }
 
-// EncodeKeyFromMessage combines GetKeyValues and EncodeKey.
+// EncodeKeyFromMessage combines GetKeyValues and EncodeKey. It returns an
+// error if one of the key fields is a repeated field, use
+// EncodeKeysFromMessage instead for such keys.
 func (cdc *KeyCodec) EncodeKeyFromMessage(message protoreflect.Message) ([]protoreflect.Value, []byte, error) {
+	if cdc.listField >= 0 {
+		return nil, nil, ormerrors.UnsupportedOperation.Wrapf("key with repeated field %s has one value per element",
+			cdc.fieldNames[cdc.listField])
+	}
+
 	values := cdc.GetKeyValues(message)
 	bz, err := cdc.EncodeKey(values)
 	return values, bz, err
 }
 
+// EncodeKeysFromMessage encodes the keys of the message. If one of the key
+// fields is a repeated field, one key is encoded for each distinct element of
+// the field, so no key is returned when it's empty. Otherwise a single key is
+// returned.
+func (cdc *KeyCodec) EncodeKeysFromMessage(message protoreflect.Message) ([][]byte, error) {
+	values := cdc.GetKeyValues(message)
+	if cdc.listField < 0 {
+		key, err := cdc.EncodeKey(values)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{key}, nil
+	}
+
+	list := values[cdc.listField].List()
+	keys := make([][]byte, 0, list.Len())
+	seen := map[string]bool{}
+	for i := 0; i < list.Len(); i++ {
+		values[cdc.listField] = list.Get(i)
+		key, err := cdc.EncodeKey(values)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[string(key)] {
+			continue
+		}
+		seen[string(key)] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 // IsFullyOrdered returns true if all fields are also ordered.
 func (cdc *KeyCodec) IsFullyOrdered() bool {
 	for _, p := range cdc.fieldCodecs {
@@ -148,6 +338,74 @@ func (cdc *KeyCodec) IsFullyOrdered() bool {
 	return true
 }
 
+// EncodeBytesPrefixKeys encodes the values as a prefix key, like EncodeKey,
+// except that the last value must correspond to a bytes field and is matched
+// as a byte prefix of that field rather than as its whole value. Because
+// non-terminal bytes fields are length prefixed, such a match can't be
+// expressed as a single prefix key, so the encoded prefix keys of all the
+// possible lengths of the field are returned in ascending byte order.
+// Together, they match exactly the keys whose bytes field starts with the last
+// value.
+func (cdc *KeyCodec) EncodeBytesPrefixKeys(values []protoreflect.Value) ([][]byte, error) {
+	n := len(values)
+	if n == 0 || n > len(cdc.fieldCodecs) {
+		return nil, ormerrors.IndexOutOfBounds.Wrapf("cannot encode a bytes prefix of %d values into %d fields", n, len(cdc.fieldCodecs))
+	}
+
+	field := cdc.fieldDescriptors[n-1]
+	if field.Kind() != protoreflect.BytesKind || field.IsList() {
+		return nil, ormerrors.UnsupportedKeyField.Wrapf("%s is not a bytes field", field.FullName())
+	}
+
+	partial := values[n-1].Bytes()
+	if len(partial) > 255 {
+		return nil, ormerrors.BytesFieldTooLong
+	}
+
+	base, err := cdc.EncodeKey(values[:n-1])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(partial) == 0 {
+		return [][]byte{base}, nil
+	}
+
+	fieldCodec := cdc.fieldCodecs[n-1]
+	descendingCodec, descending := fieldCodec.(ormfield.DescendingCodec)
+	if descending {
+		fieldCodec = descendingCodec.Codec
+	}
+
+	var suffixes [][]byte
+	switch fieldCodec.(type) {
+	case ormfield.BytesCodec:
+		suffixes = [][]byte{partial}
+	case ormfield.NonTerminalBytesCodec:
+		for length := len(partial); length <= 255; length++ {
+			suffixes = append(suffixes, append([]byte{byte(length)}, partial...))
+		}
+	default:
+		return nil, ormerrors.UnsupportedKeyField.Wrapf("can't encode a bytes prefix of %s", field.FullName())
+	}
+
+	keys := make([][]byte, len(suffixes))
+	for i, suffix := range suffixes {
+		if descending {
+			for j := range suffix {
+				suffix[j] = ^suffix[j]
+			}
+		}
+		key := make([]byte, 0, len(base)+len(suffix))
+		keys[i] = append(append(key, base...), suffix...)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+
+	return keys, nil
+}
+
 // CompareKeys compares the provided values which must correspond to the
 // fields in this key. Prefix keys of different lengths are supported but the
 // function will panic if either array is too long. A negative value is returned
@@ -209,6 +467,11 @@ func (cdc KeyCodec) ComputeKeyBufferSize(values []protoreflect.Value) (int, erro
 // supported.
 func (cdc *KeyCodec) SetKeyValues(message protoreflect.Message, values []protoreflect.Value) {
 	for i, f := range cdc.fieldDescriptors {
+		if !values[i].IsValid() {
+			// null value, see NullsLast
+			message.Clear(f)
+			continue
+		}
 		message.Set(f, values[i])
 	}
 }
@@ -296,6 +559,12 @@ func (cdc *KeyCodec) Prefix() []byte {
 	return cdc.prefix
 }
 
+// IsDescending returns true if keys are encoded in descending order, see
+// Descending.
+func (cdc *KeyCodec) IsDescending() bool {
+	return cdc.options.descending
+}
+
 // MessageType returns the message type of fields in this key.
 func (cdc *KeyCodec) MessageType() protoreflect.MessageType {
 	return cdc.messageType
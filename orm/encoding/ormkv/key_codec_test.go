@@ -315,3 +315,58 @@ func TestGetSet(t *testing.T) {
 	assert.Equal(t, 0, cdc.CompareKeys(values, values3))
 	assert.Assert(t, bytes.Equal(bz, bz2))
 }
+
+func TestEncodeBytesPrefixKeys(t *testing.T) {
+	nonTerminal, err := ormkv.NewKeyCodec(nil,
+		(&testpb.ExampleTable{}).ProtoReflect().Type(),
+		[]protoreflect.Name{"u32", "bz", "str"})
+	assert.NilError(t, err)
+	descending, err := nonTerminal.Descending()
+	assert.NilError(t, err)
+	terminal, err := ormkv.NewKeyCodec(nil,
+		(&testpb.ExampleTable{}).ProtoReflect().Type(),
+		[]protoreflect.Name{"u32", "bz"})
+	assert.NilError(t, err)
+
+	values := [][]byte{{}, {1}, {1, 2}, {1, 2, 3}, {1, 3}, {2}, {2, 1, 2}, bytes.Repeat([]byte{1}, 255)}
+	partials := [][]byte{{}, {1}, {1, 2}, {1, 2, 3, 4}, bytes.Repeat([]byte{1}, 255)}
+
+	for _, cdc := range []*ormkv.KeyCodec{nonTerminal, descending, terminal} {
+		for _, partial := range partials {
+			prefixKeys, err := cdc.EncodeBytesPrefixKeys(encodeutil.ValuesOf(uint32(5), partial))
+			assert.NilError(t, err)
+			for i := 1; i < len(prefixKeys); i++ {
+				assert.Assert(t, bytes.Compare(prefixKeys[i-1], prefixKeys[i]) < 0)
+			}
+
+			for _, value := range values {
+				keyValues := encodeutil.ValuesOf(uint32(5), value, "abc")[:len(cdc.GetFieldNames())]
+				key, err := cdc.EncodeKey(keyValues)
+				assert.NilError(t, err)
+				matches := 0
+				for _, prefixKey := range prefixKeys {
+					if bytes.HasPrefix(key, prefixKey) {
+						matches++
+					}
+				}
+				expected := 0
+				if bytes.HasPrefix(value, partial) {
+					expected = 1
+				}
+				assert.Equal(t, expected, matches, "value %x, partial %x", value, partial)
+
+				// other prefix values never match
+				otherKey, err := cdc.EncodeKey(append(encodeutil.ValuesOf(uint32(6)), keyValues[1:]...))
+				assert.NilError(t, err)
+				for _, prefixKey := range prefixKeys {
+					assert.Assert(t, !bytes.HasPrefix(otherKey, prefixKey))
+				}
+			}
+		}
+	}
+
+	_, err = nonTerminal.EncodeBytesPrefixKeys(encodeutil.ValuesOf(uint32(5)))
+	assert.ErrorContains(t, err, "not a bytes field")
+	_, err = nonTerminal.EncodeBytesPrefixKeys(nil)
+	assert.ErrorContains(t, err, "cannot encode")
+}
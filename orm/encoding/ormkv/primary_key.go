@@ -6,6 +6,7 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
 
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -131,6 +132,36 @@ func (p *PrimaryKeyCodec) Unmarshal(key []protoreflect.Value, value []byte, mess
 	return nil
 }
 
+// UnmarshalFields is like Unmarshal but only decodes the provided fields from
+// value, skipping over the bytes of all other fields which are left unset on
+// message. Primary key fields are always set from key.
+func (p *PrimaryKeyCodec) UnmarshalFields(key []protoreflect.Value, value []byte, message proto.Message, fields []protoreflect.FieldDescriptor) error {
+	numbers := make(map[protowire.Number]bool, len(fields))
+	for _, field := range fields {
+		numbers[field.Number()] = true
+	}
+
+	var projected []byte
+	for len(value) > 0 {
+		num, typ, n := protowire.ConsumeTag(value)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		m := protowire.ConsumeFieldValue(num, typ, value[n:])
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+
+		if numbers[num] {
+			projected = append(projected, value[:n+m]...)
+		}
+		value = value[n+m:]
+	}
+
+	return p.Unmarshal(key, projected, message)
+}
+
 func (p PrimaryKeyCodec) EncodeKVFromMessage(message protoreflect.Message) (k, v []byte, err error) {
 	ks, k, err := p.KeyCodec.EncodeKeyFromMessage(message)
 	if err != nil {
@@ -76,6 +76,100 @@ func NewUniqueKeyCodec(prefix []byte, messageType protoreflect.MessageType, inde
 	}, nil
 }
 
+// Descending returns a copy of the codec which encodes keys in descending
+// order, see KeyCodec.Descending. Values are not affected.
+func (u *UniqueKeyCodec) Descending() (*UniqueKeyCodec, error) {
+	keyCodec, err := u.keyCodec.Descending()
+	if err != nil {
+		return nil, err
+	}
+
+	return &UniqueKeyCodec{
+		pkFieldOrder: u.pkFieldOrder,
+		keyCodec:     keyCodec,
+		valueCodec:   u.valueCodec,
+	}, nil
+}
+
+// NormalizeStrings returns a copy of the codec which normalizes the string
+// values of keys, see KeyCodec.NormalizeStrings. Values are not affected.
+func (u *UniqueKeyCodec) NormalizeStrings(normalize func(string) string) (*UniqueKeyCodec, error) {
+	keyCodec, err := u.keyCodec.NormalizeStrings(normalize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UniqueKeyCodec{
+		pkFieldOrder: u.pkFieldOrder,
+		keyCodec:     keyCodec,
+		valueCodec:   u.valueCodec,
+	}, nil
+}
+
+// HashBytesFields returns a copy of the codec which hashes the values of the
+// provided bytes fields of keys, see KeyCodec.HashBytesFields. Values are not
+// affected.
+func (u *UniqueKeyCodec) HashBytesFields(fields []protoreflect.Name, hash func([]byte) []byte) (*UniqueKeyCodec, error) {
+	keyCodec, err := u.keyCodec.HashBytesFields(fields, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UniqueKeyCodec{
+		pkFieldOrder: u.pkFieldOrder,
+		keyCodec:     keyCodec,
+		valueCodec:   u.valueCodec,
+	}, nil
+}
+
+// OrderedEnums returns a copy of the codec which encodes the values of the
+// enum fields of keys in their numeric order, see KeyCodec.OrderedEnums.
+// Values are not affected.
+func (u *UniqueKeyCodec) OrderedEnums() (*UniqueKeyCodec, error) {
+	keyCodec, err := u.keyCodec.OrderedEnums()
+	if err != nil {
+		return nil, err
+	}
+
+	return &UniqueKeyCodec{
+		pkFieldOrder: u.pkFieldOrder,
+		keyCodec:     keyCodec,
+		valueCodec:   u.valueCodec,
+	}, nil
+}
+
+// DecimalFields returns a copy of the codec which encodes the values of the
+// provided string fields of keys as decimal numbers, see
+// KeyCodec.DecimalFields. Values are not affected.
+func (u *UniqueKeyCodec) DecimalFields(fields []protoreflect.Name) (*UniqueKeyCodec, error) {
+	keyCodec, err := u.keyCodec.DecimalFields(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UniqueKeyCodec{
+		pkFieldOrder: u.pkFieldOrder,
+		keyCodec:     keyCodec,
+		valueCodec:   u.valueCodec,
+	}, nil
+}
+
+// NullsLast returns a copy of the codec which sorts the unset values of the
+// provided fields of keys last, see KeyCodec.NullsLast. Values are not
+// affected.
+func (u *UniqueKeyCodec) NullsLast(fields []protoreflect.Name) (*UniqueKeyCodec, error) {
+	keyCodec, err := u.keyCodec.NullsLast(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UniqueKeyCodec{
+		pkFieldOrder: u.pkFieldOrder,
+		keyCodec:     keyCodec,
+		valueCodec:   u.valueCodec,
+	}, nil
+}
+
 func (u UniqueKeyCodec) DecodeIndexKey(k, v []byte) (indexFields, primaryKey []protoreflect.Value, err error) {
 	ks, err := u.keyCodec.DecodeKey(bytes.NewReader(k))
 
@@ -4,14 +4,18 @@ import (
 	"fmt"
 
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // Options is the internal list options struct.
 type Options struct {
 	Reverse, CountTotal         bool
+	ExclusiveEnd                bool
 	Offset, Limit, DefaultLimit uint64
 	Cursor                      []byte
 	Filter                      func(proto.Message) bool
+	Projection                  []protoreflect.FieldDescriptor
+	BytesPrefix                 bool
 }
 
 func (o Options) Validate() error {
@@ -3,6 +3,7 @@ package ormlist
 
 import (
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	queryv1beta1 "github.com/cosmos/cosmos-sdk/api/cosmos/base/query/v1beta1"
 
@@ -28,6 +29,41 @@ func Filter(filterFn func(message proto.Message) bool) Option {
 	})
 }
 
+// Projection makes iterators only decode the provided fields of each message,
+// together with the primary key fields, leaving all other fields unset. This
+// avoids decoding wide messages when only a few of their fields are needed.
+//
+// Messages returned by iterators using Projection are partial and must NOT be
+// saved back to the table, as this would clear the fields which weren't
+// decoded. Filter functions also receive the partial messages.
+func Projection(fields ...protoreflect.FieldDescriptor) Option {
+	return listinternal.FuncOption(func(options *listinternal.Options) {
+		options.Projection = fields
+	})
+}
+
+// BytesPrefix makes List match the last value of the prefix key, which must
+// correspond to a bytes field, as a byte prefix of that field instead of as
+// its whole value. Because bytes fields are length prefixed in keys which have
+// other fields after them, the entries are then listed with one range scan per
+// possible field length, i.e. up to 256 of them. It doesn't apply to
+// ListRange.
+func BytesPrefix() Option {
+	return listinternal.FuncOption(func(options *listinternal.Options) {
+		options.BytesPrefix = true
+	})
+}
+
+// ExclusiveEnd makes range iteration exclusive of the end key. It only
+// applies to ListRange, which is otherwise inclusive at both ends. If the end
+// key specifies fewer values than the index's fields, all the entries matching
+// the end key as a prefix are excluded.
+func ExclusiveEnd() Option {
+	return listinternal.FuncOption(func(options *listinternal.Options) {
+		options.ExclusiveEnd = true
+	})
+}
+
 // Cursor specifies a cursor after which to restart iteration. Cursor values
 // are returned by iterators and in pagination results.
 func Cursor(cursor CursorT) Option {
@@ -36,6 +72,31 @@ func Cursor(cursor CursorT) Option {
 	})
 }
 
+// Offset skips the first offset entries listed by the iterator, after the
+// direction of iteration and any Filter are applied. The skipped entries are
+// still read, so the cost of this option is O(offset), and offset-based
+// pagination gets slower for later pages: Cursor should be preferred for
+// paginating over large tables. Offset can't be combined with Cursor, and it
+// overrides the offset of a previous Paginate option.
+func Offset(offset uint64) Option {
+	return listinternal.FuncOption(func(options *listinternal.Options) {
+		options.Offset = offset
+	})
+}
+
+// Limit makes the iterator list at most limit entries, after the direction of
+// iteration, Cursor, any Filter and any Offset are applied, e.g. to list the
+// top limit entries of an index. A limit of 0 means no limit. As with
+// Paginate, Iterator.PageResponse is non-nil after Iterator.Next() returns
+// false, with the cursor of the last listed entry as NextKey if the limit was
+// reached before the end of the iteration. It overrides the limit of a
+// previous Paginate option.
+func Limit(limit uint64) Option {
+	return listinternal.FuncOption(func(options *listinternal.Options) {
+		options.Limit = limit
+	})
+}
+
 // Paginate paginates iterator output based on the provided page request.
 // The Iterator.PageRequest value on the returned iterator will be non-nil
 // after Iterator.Next() returns false when this option is provided.
@@ -1,7 +1,9 @@
 package ormtable
 
 import (
+	"bufio"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -31,6 +33,15 @@ func (t autoIncrementTable) InsertReturningID(ctx context.Context, message proto
 	return t.save(ctx, backend, message, saveModeInsert)
 }
 
+func (t autoIncrementTable) LastInsertedSequence(ctx context.Context) (uint64, error) {
+	backend, err := t.getBackend(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.curSeqValue(backend.IndexStoreReader())
+}
+
 func (t autoIncrementTable) Save(ctx context.Context, message proto.Message) error {
 	backend, err := t.getWriteBackend(ctx)
 	if err != nil {
@@ -61,33 +72,67 @@ func (t autoIncrementTable) Update(ctx context.Context, message proto.Message) e
 	return err
 }
 
+func (t autoIncrementTable) InsertBatch(ctx context.Context, messages ...proto.Message) error {
+	backend, err := t.getWriteBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	writer := newReadYourWritesBatchIndexCommitmentWriter(backend)
+	defer writer.Close()
+
+	for _, message := range messages {
+		_, mode, err := t.setAutoIncrementID(writer, message, saveModeInsert)
+		if err != nil {
+			return err
+		}
+
+		err = t.tableImpl.doSaveWithWriteBatch(ctx, writer, message, mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Write()
+}
+
 func (t *autoIncrementTable) save(ctx context.Context, backend Backend, message proto.Message, mode saveMode) (newId uint64, err error) {
-	messageRef := message.ProtoReflect()
-	val := messageRef.Get(t.autoIncField).Uint()
 	writer := newBatchIndexCommitmentWriter(backend)
 	defer writer.Close()
 
+	newId, mode, err = t.setAutoIncrementID(writer, message, mode)
+	if err != nil {
+		return 0, err
+	}
+
+	return newId, t.tableImpl.doSave(ctx, writer, message, mode)
+}
+
+// setAutoIncrementID sets a new ID on the message if it has none, and returns
+// the save mode to use for the message.
+func (t *autoIncrementTable) setAutoIncrementID(writer *batchIndexCommitmentWriter, message proto.Message, mode saveMode) (newId uint64, newMode saveMode, err error) {
+	messageRef := message.ProtoReflect()
+	val := messageRef.Get(t.autoIncField).Uint()
+
 	if val == 0 {
 		if mode == saveModeUpdate {
-			return 0, ormerrors.PrimaryKeyInvalidOnUpdate
+			return 0, mode, ormerrors.PrimaryKeyInvalidOnUpdate
 		}
 
-		mode = saveModeInsert
 		newId, err = t.nextSeqValue(writer.IndexStore())
 		if err != nil {
-			return 0, err
+			return 0, mode, err
 		}
 
 		messageRef.Set(t.autoIncField, protoreflect.ValueOfUint64(newId))
-	} else {
-		if mode == saveModeInsert {
-			return 0, ormerrors.AutoIncrementKeyAlreadySet
-		}
+		return newId, saveModeInsert, nil
+	}
 
-		mode = saveModeUpdate
+	if mode == saveModeInsert {
+		return 0, mode, ormerrors.AutoIncrementKeyAlreadySet
 	}
 
-	return newId, t.tableImpl.doSave(ctx, writer, message, mode)
+	return 0, saveModeUpdate, nil
 }
 
 func (t *autoIncrementTable) curSeqValue(kv kv.ReadonlyStore) (uint64, error) {
@@ -142,27 +187,34 @@ func (t autoIncrementTable) ImportJSON(ctx context.Context, reader io.Reader) er
 		return err
 	}
 
+	ctx = withImporting(ctx)
 	return t.decodeAutoIncJson(backend, reader, func(message proto.Message, maxID uint64) error {
-		messageRef := message.ProtoReflect()
-		id := messageRef.Get(t.autoIncField).Uint()
-		if id == 0 {
-			// we don't have an ID in the JSON, so we call Save to insert and
-			// generate one
-			_, err = t.save(ctx, backend, message, saveModeInsert)
-			return err
-		} else {
-			if id > maxID {
-				return fmt.Errorf("invalid ID %d, expected a value <= %d, the highest sequence number", id, maxID)
-			}
-			// we do have an ID and calling Save will fail because it expects
-			// either no ID or SAVE_MODE_UPDATE. So instead we drop one level
-			// down and insert using tableImpl which doesn't know about
-			// auto-incrementing IDs
-			return t.tableImpl.save(ctx, backend, message, saveModeInsert)
-		}
+		return t.importMessage(ctx, backend, message, maxID)
 	})
 }
 
+// importMessage inserts an imported message, given the imported sequence
+// number maxID.
+func (t autoIncrementTable) importMessage(ctx context.Context, backend Backend, message proto.Message, maxID uint64) error {
+	messageRef := message.ProtoReflect()
+	id := messageRef.Get(t.autoIncField).Uint()
+	if id == 0 {
+		// we don't have an imported ID, so we call Save to insert and
+		// generate one
+		_, err := t.save(ctx, backend, message, saveModeInsert)
+		return err
+	} else {
+		if id > maxID {
+			return fmt.Errorf("invalid ID %d, expected a value <= %d, the highest sequence number", id, maxID)
+		}
+		// we do have an ID and calling Save will fail because it expects
+		// either no ID or SAVE_MODE_UPDATE. So instead we drop one level
+		// down and insert using tableImpl which doesn't know about
+		// auto-incrementing IDs
+		return t.tableImpl.save(ctx, backend, message, saveModeInsert)
+	}
+}
+
 func (t autoIncrementTable) decodeAutoIncJson(backend Backend, reader io.Reader, onMsg func(message proto.Message, maxID uint64) error) error {
 	decoder, err := t.startDecodeJson(reader)
 	if err != nil {
@@ -171,7 +223,7 @@ func (t autoIncrementTable) decodeAutoIncJson(backend Backend, reader io.Reader,
 
 	var seq uint64
 
-	return t.doDecodeJson(decoder,
+	return t.doDecodeJson(decoder, backend,
 		func(message json.RawMessage) bool {
 			err = json.Unmarshal(message, &seq)
 			if err == nil {
@@ -237,3 +289,60 @@ func (t *autoIncrementTable) GetTable(message proto.Message) Table {
 }
 
 var _ AutoIncrementTable = &autoIncrementTable{}
+
+func (t autoIncrementTable) exportBinary(ctx context.Context, writer io.Writer) error {
+	backend, err := t.getBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	seq, err := t.curSeqValue(backend.IndexStoreReader())
+	if err != nil {
+		return err
+	}
+
+	err = writeUvarint(writer, seq)
+	if err != nil {
+		return err
+	}
+
+	return t.tableImpl.exportBinary(ctx, writer)
+}
+
+func (t autoIncrementTable) importBinary(ctx context.Context, reader *bufio.Reader) error {
+	backend, err := t.getWriteBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	seq, err := binary.ReadUvarint(reader)
+	if err == io.EOF {
+		// nothing was exported
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	writer := newBatchIndexCommitmentWriter(backend)
+	defer writer.Close()
+	err = t.setSeqValue(writer.IndexStore(), seq)
+	if err != nil {
+		return err
+	}
+	err = writer.Write()
+	if err != nil {
+		return err
+	}
+
+	err = t.importSequencesBinary(backend, reader)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	} else if err != nil {
+		return err
+	}
+
+	ctx = withImporting(ctx)
+	return t.decodeBinary(reader, func(message proto.Message) error {
+		return t.importMessage(ctx, backend, message, seq)
+	})
+}
@@ -7,12 +7,14 @@ import (
 	"strings"
 	"testing"
 
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/golden"
 
 	"github.com/cosmos/cosmos-sdk/orm/internal/testkv"
 	"github.com/cosmos/cosmos-sdk/orm/internal/testpb"
 	"github.com/cosmos/cosmos-sdk/orm/model/ormtable"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
 )
 
 func TestAutoIncrementScenario(t *testing.T) {
@@ -95,3 +97,111 @@ func TestBadJSON(t *testing.T) {
 	assert.NilError(t, err)
 	assert.ErrorContains(t, table.ImportJSON(store, f), "invalid ID")
 }
+
+func TestLastInsertedSequence(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	autoTable := table.(ormtable.AutoIncrementTable)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	seq, err := autoTable.LastInsertedSequence(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), seq)
+
+	// IDs generated in the same batch are distinct
+	ex1 := &testpb.ExampleAutoIncrementTable{X: "foo"}
+	ex2 := &testpb.ExampleAutoIncrementTable{X: "bar"}
+	assert.NilError(t, table.InsertBatch(ctx, ex1, ex2))
+	assert.Equal(t, uint64(1), ex1.Id)
+	assert.Equal(t, uint64(2), ex2.Id)
+
+	// deleting entries doesn't change the sequence
+	assert.NilError(t, table.Delete(ctx, ex2))
+	seq, err = autoTable.LastInsertedSequence(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), seq)
+
+	// updates don't change the sequence either
+	ex1.Y = 5
+	assert.NilError(t, table.Update(ctx, ex1))
+	seq, err = autoTable.LastInsertedSequence(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), seq)
+
+	newId, err := autoTable.InsertReturningID(ctx, &testpb.ExampleAutoIncrementTable{X: "baz"})
+	assert.NilError(t, err)
+	assert.Equal(t, seq+1, newId)
+}
+
+func TestSequence(t *testing.T) {
+	for _, messageType := range []protoreflect.MessageType{
+		(&testpb.ExampleTable{}).ProtoReflect().Type(),
+		(&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+	} {
+		table, err := ormtable.Build(ormtable.Options{
+			MessageType: messageType,
+			Sequences:   []string{"a", "b"},
+		})
+		assert.NilError(t, err)
+		ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+		assert.Assert(t, ormtable.GetSequence(table, "c") == nil)
+		seqA, seqB := ormtable.GetSequence(table, "a"), ormtable.GetSequence(table, "b")
+
+		next, err := seqA.PeekNextVal(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, uint64(1), next)
+		for i := uint64(1); i <= 3; i++ {
+			next, err = seqA.NextVal(ctx)
+			assert.NilError(t, err)
+			assert.Equal(t, i, next)
+		}
+
+		// sequences are independent of each other and of the table
+		next, err = seqB.NextVal(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, uint64(1), next)
+		if autoTable, ok := table.(ormtable.AutoIncrementTable); ok {
+			id, err := autoTable.InsertReturningID(ctx, &testpb.ExampleAutoIncrementTable{X: "foo"})
+			assert.NilError(t, err)
+			assert.Equal(t, uint64(1), id)
+		} else {
+			assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 1}))
+		}
+		next, err = seqA.PeekNextVal(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, uint64(4), next)
+
+		// sequences are included in JSON exports
+		buf := &bytes.Buffer{}
+		assert.NilError(t, table.ExportJSON(ctx, buf))
+		assert.Assert(t, strings.Contains(buf.String(), `{"$sequences":{"a":3,"b":1}}`), buf.String())
+		assert.NilError(t, table.ValidateJSON(bytes.NewReader(buf.Bytes())))
+		ctx2 := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+		assert.NilError(t, table.ImportJSON(ctx2, bytes.NewReader(buf.Bytes())))
+		assertTablesEqual(t, table, ctx, ctx2)
+		next, err = seqA.NextVal(ctx2)
+		assert.NilError(t, err)
+		assert.Equal(t, uint64(4), next)
+
+		// and in binary exports
+		buf = &bytes.Buffer{}
+		assert.NilError(t, ormtable.ExportTable(ctx, table, buf))
+		ctx3 := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+		assert.NilError(t, ormtable.ImportTable(ctx3, table, bytes.NewReader(buf.Bytes())))
+		assertTablesEqual(t, table, ctx, ctx3)
+		next, err = seqB.PeekNextVal(ctx3)
+		assert.NilError(t, err)
+		assert.Equal(t, uint64(2), next)
+
+		// unknown sequences are rejected
+		assert.ErrorContains(t, table.ValidateJSON(strings.NewReader(`[{"$sequences":{"c":1}}]`)), "unknown sequence")
+	}
+
+	_, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		Sequences:   []string{"a", "a"},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+}
@@ -41,6 +41,7 @@ type Backend interface {
 	WriteHooks() WriteHooks
 
 	// WithWriteHooks returns a copy of this backend with the provided write hooks.
+	// Use MultiWriteHooks to register several hooks.
 	WithWriteHooks(WriteHooks) Backend
 }
 
@@ -91,6 +92,7 @@ type backend struct {
 	indexStore      kv.Store
 	validateHooks   ValidateHooks
 	writeHooks      WriteHooks
+	onHookPanic     func(recovered interface{})
 }
 
 func (c backend) ValidateHooks() ValidateHooks {
@@ -113,6 +115,10 @@ func (c backend) WithWriteHooks(hooks WriteHooks) Backend {
 
 func (backend) private() {}
 
+func (c backend) writeHookPanicHandler() func(recovered interface{}) {
+	return c.onHookPanic
+}
+
 func (c backend) CommitmentStoreReader() kv.ReadonlyStore {
 	return c.commitmentStore
 }
@@ -147,6 +153,14 @@ type BackendOptions struct {
 	ValidateHooks ValidateHooks
 
 	WriteHooks WriteHooks
+
+	// WriteHookPanicHandler is an optional function called with the values of
+	// the panics recovered from WriteHooks, such as a function logging them
+	// with the logger of the module. It should panic again with the values of
+	// the panics which must abort the transaction, such as the out of gas
+	// panics of the SDK gas meters, which prevents the remaining hooks and
+	// pending writes from being applied. If it is nil, panics are ignored.
+	WriteHookPanicHandler func(recovered interface{})
 }
 
 // NewBackend creates a new Backend.
@@ -160,6 +174,7 @@ func NewBackend(options BackendOptions) Backend {
 		indexStore:      indexStore,
 		validateHooks:   options.ValidateHooks,
 		writeHooks:      options.WriteHooks,
+		onHookPanic:     options.WriteHookPanicHandler,
 	}
 }
 
@@ -24,6 +24,26 @@ func newBatchIndexCommitmentWriter(store Backend) *batchIndexCommitmentWriter {
 	}
 }
 
+// CommitmentStoreReader returns the reader for the commitment store, which sees
+// the pending writes for read-your-writes batches.
+func (w *batchIndexCommitmentWriter) CommitmentStoreReader() kv.ReadonlyStore {
+	if w.commitmentWriter.pending != nil {
+		return w.commitmentWriter
+	}
+
+	return w.Backend.CommitmentStoreReader()
+}
+
+// IndexStoreReader returns the reader for the index store, which sees the
+// pending writes for read-your-writes batches.
+func (w *batchIndexCommitmentWriter) IndexStoreReader() kv.ReadonlyStore {
+	if w.indexWriter.pending != nil {
+		return w.indexWriter
+	}
+
+	return w.Backend.IndexStoreReader()
+}
+
 func (w *batchIndexCommitmentWriter) CommitmentStore() kv.Store {
 	return w.commitmentWriter
 }
@@ -34,12 +54,19 @@ func (w *batchIndexCommitmentWriter) IndexStore() kv.Store {
 
 // Write flushes any pending writes.
 func (w *batchIndexCommitmentWriter) Write() error {
-	err := flushWrites(w.Backend.CommitmentStore(), w.commitmentWriter)
+	onHookPanic := ignoreWriteHookPanic
+	if b, ok := w.Backend.(interface {
+		writeHookPanicHandler() func(recovered interface{})
+	}); ok && b.writeHookPanicHandler() != nil {
+		onHookPanic = b.writeHookPanicHandler()
+	}
+
+	err := flushWrites(w.Backend.CommitmentStore(), w.commitmentWriter, onHookPanic)
 	if err != nil {
 		return err
 	}
 
-	err = flushWrites(w.Backend.IndexStore(), w.indexWriter)
+	err = flushWrites(w.Backend.IndexStore(), w.indexWriter, onHookPanic)
 	if err != nil {
 		return err
 	}
@@ -50,20 +77,20 @@ func (w *batchIndexCommitmentWriter) Write() error {
 	return err
 }
 
-func flushWrites(store kv.Store, writer *batchStoreWriter) error {
+func flushWrites(store kv.Store, writer *batchStoreWriter, onHookPanic func(recovered interface{})) error {
 	for _, buf := range writer.prevBufs {
-		err := flushBuf(store, buf)
+		err := flushBuf(store, buf, onHookPanic)
 		if err != nil {
 			return err
 		}
 	}
-	return flushBuf(store, writer.curBuf)
+	return flushBuf(store, writer.curBuf, onHookPanic)
 }
 
-func flushBuf(store kv.Store, writes []*batchWriterEntry) error {
+func flushBuf(store kv.Store, writes []*batchWriterEntry, onHookPanic func(recovered interface{})) error {
 	for _, write := range writes {
 		if write.hookCall != nil {
-			write.hookCall()
+			callWriteHooks(onHookPanic, write.hooks, write.hookCall)
 		} else if !write.delete {
 			err := store.Set(write.key, write.value)
 			if err != nil {
@@ -87,22 +114,61 @@ func (w *batchIndexCommitmentWriter) Close() {
 	w.commitmentWriter.curBuf = nil
 	w.indexWriter.prevBufs = nil
 	w.indexWriter.curBuf = nil
+	if w.commitmentWriter.pending != nil {
+		w.commitmentWriter.pending = map[string]*batchWriterEntry{}
+		w.indexWriter.pending = map[string]*batchWriterEntry{}
+	}
 }
 
 type batchWriterEntry struct {
 	key, value []byte
 	delete     bool
-	hookCall   func()
+	hooks      WriteHooks
+	hookCall   func(WriteHooks)
 }
 
 type batchStoreWriter struct {
 	kv.ReadonlyStore
 	prevBufs [][]*batchWriterEntry
 	curBuf   []*batchWriterEntry
+
+	// pending is the last pending write for each key, it is only tracked if
+	// reads need to see the pending writes.
+	pending map[string]*batchWriterEntry
 }
 
 const capacity = 16
 
+// newReadYourWritesBatchIndexCommitmentWriter returns a batchIndexCommitmentWriter
+// whose reads with Get and Has see the pending writes. This allows writing
+// several messages in a single batch while still checking constraints between
+// them.
+func newReadYourWritesBatchIndexCommitmentWriter(store Backend) *batchIndexCommitmentWriter {
+	w := newBatchIndexCommitmentWriter(store)
+	w.commitmentWriter.pending = map[string]*batchWriterEntry{}
+	w.indexWriter.pending = map[string]*batchWriterEntry{}
+	return w
+}
+
+func (b *batchStoreWriter) Get(key []byte) ([]byte, error) {
+	if entry, ok := b.pending[string(key)]; ok {
+		if entry.delete {
+			return nil, nil
+		}
+		return entry.value, nil
+	}
+
+	return b.ReadonlyStore.Get(key)
+}
+
+func (b *batchStoreWriter) Has(key []byte) (bool, error) {
+	if entry, ok := b.pending[string(key)]; ok {
+		return !entry.delete, nil
+	}
+
+	return b.ReadonlyStore.Has(key)
+}
+
 func (b *batchStoreWriter) Set(key, value []byte) error {
 	b.append(&batchWriterEntry{key: key, value: value})
 	return nil
@@ -113,8 +179,8 @@ func (b *batchStoreWriter) Delete(key []byte) error {
 	return nil
 }
 
-func (w *batchIndexCommitmentWriter) enqueueHook(f func()) {
-	w.indexWriter.append(&batchWriterEntry{hookCall: f})
+func (w *batchIndexCommitmentWriter) enqueueHook(hooks WriteHooks, call func(WriteHooks)) {
+	w.indexWriter.append(&batchWriterEntry{hooks: hooks, hookCall: call})
 }
 
 func (b *batchStoreWriter) append(entry *batchWriterEntry) {
@@ -124,6 +190,9 @@ func (b *batchStoreWriter) append(entry *batchWriterEntry) {
 	}
 
 	b.curBuf = append(b.curBuf, entry)
+	if b.pending != nil && entry.hookCall == nil {
+		b.pending[string(entry.key)] = entry
+	}
 }
 
 var _ Backend = &batchIndexCommitmentWriter{}
@@ -0,0 +1,135 @@
+package ormtable_test
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"gotest.tools/v3/assert"
+
+	"github.com/cosmos/cosmos-sdk/orm/internal/testkv"
+	"github.com/cosmos/cosmos-sdk/orm/internal/testpb"
+	"github.com/cosmos/cosmos-sdk/orm/model/ormtable"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+func TestInsertBatch(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.SimpleExample{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	assert.NilError(t, table.InsertBatch(ctx,
+		&testpb.SimpleExample{Name: "a", Unique: "x"},
+		&testpb.SimpleExample{Name: "b", Unique: "y"},
+	))
+	count, err := table.Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), count)
+	found, err := table.GetUniqueIndex("unique").Has(ctx, "y")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+
+	// constraints are checked against the store and within the batch, and
+	// nothing is written on failure
+	testCases := []struct {
+		name     string
+		messages []proto.Message
+		expErr   error
+	}{
+		{
+			"existing primary key",
+			[]proto.Message{&testpb.SimpleExample{Name: "c", Unique: "z"}, &testpb.SimpleExample{Name: "a"}},
+			ormerrors.AlreadyExists,
+		},
+		{
+			"duplicate primary key in batch",
+			[]proto.Message{&testpb.SimpleExample{Name: "c", Unique: "z"}, &testpb.SimpleExample{Name: "c", Unique: "w"}},
+			ormerrors.AlreadyExists,
+		},
+		{
+			"existing unique key",
+			[]proto.Message{&testpb.SimpleExample{Name: "c", Unique: "z"}, &testpb.SimpleExample{Name: "d", Unique: "x"}},
+			ormerrors.UniqueKeyViolation,
+		},
+		{
+			"duplicate unique key in batch",
+			[]proto.Message{&testpb.SimpleExample{Name: "c", Unique: "z"}, &testpb.SimpleExample{Name: "d", Unique: "z"}},
+			ormerrors.UniqueKeyViolation,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, table.InsertBatch(ctx, tc.messages...), tc.expErr)
+
+			count, err := table.Count(ctx)
+			assert.NilError(t, err)
+			assert.Equal(t, uint64(2), count)
+			count, err = table.GetUniqueIndex("unique").Count(ctx)
+			assert.NilError(t, err)
+			assert.Equal(t, uint64(2), count)
+		})
+	}
+}
+
+func TestInsertBatchAutoIncrement(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleAutoIncrementTable{X: "a"}))
+	msgs := []*testpb.ExampleAutoIncrementTable{{X: "b"}, {X: "c"}, {X: "d"}}
+	assert.NilError(t, table.InsertBatch(ctx, msgs[0], msgs[1], msgs[2]))
+	for i, msg := range msgs {
+		assert.Equal(t, uint64(i+2), msg.Id)
+	}
+
+	assert.ErrorIs(t, table.InsertBatch(ctx, &testpb.ExampleAutoIncrementTable{Id: 10, X: "e"}), ormerrors.AutoIncrementKeyAlreadySet)
+
+	// the sequence is shared with regular inserts
+	msg := &testpb.ExampleAutoIncrementTable{X: "e"}
+	assert.NilError(t, table.Insert(ctx, msg))
+	assert.Equal(t, uint64(5), msg.Id)
+}
+
+func benchmarkMessages(n int) []proto.Message {
+	msgs := make([]proto.Message, n)
+	for i := range msgs {
+		msgs[i] = &testpb.ExampleTable{U32: uint32(i), U64: uint64(i), Str: fmt.Sprintf("str%d", i), Bz: []byte{byte(i)}}
+	}
+	return msgs
+}
+
+func BenchmarkInsert(b *testing.B) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(b, err)
+	msgs := benchmarkMessages(100000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+		for _, msg := range msgs {
+			assert.NilError(b, table.Insert(ctx, msg))
+		}
+	}
+}
+
+func BenchmarkInsertBatch(b *testing.B) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(b, err)
+	msgs := benchmarkMessages(100000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+		assert.NilError(b, table.InsertBatch(ctx, msgs...))
+	}
+}
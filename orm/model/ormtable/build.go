@@ -1,6 +1,7 @@
 package ormtable
 
 import (
+	"crypto/sha256"
 	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/orm/internal/fieldnames"
@@ -18,9 +19,13 @@ import (
 )
 
 const (
-	primaryKeyId uint32 = 0
-	indexIdLimit uint32 = 32768
-	seqId               = indexIdLimit
+	primaryKeyId   uint32 = 0
+	indexIdLimit   uint32 = 32768
+	seqId                 = indexIdLimit
+	insertionSeqId        = indexIdLimit + 1
+	sumsId                = indexIdLimit + 2
+	minMaxId              = indexIdLimit + 3
+	sequencesId           = indexIdLimit + 4
 )
 
 // Options are options for building a Table.
@@ -61,6 +66,144 @@ type Options struct {
 	// Mutating operations will attempt to cast ReadBackend to Backend and
 	// will return an error if that fails.
 	BackendResolver BackendResolver
+
+	// IndexFilters optionally maps the fields of secondary indexes, as
+	// specified in the table descriptor, to filter functions. A filtered index
+	// only contains entries for the messages for which the filter returns true.
+	// For unique indexes, uniqueness is then only enforced among those messages.
+	IndexFilters map[string]func(proto.Message) bool
+
+	// DescendingIndexes optionally lists the fields of secondary indexes, as
+	// specified in the table descriptor, whose keys are stored in descending
+	// order. Forward iteration over a descending index returns entries from
+	// the greatest to the smallest key, and range iteration expects from to be
+	// greater than or equal to to.
+	DescendingIndexes []string
+
+	// NullsLastIndexes optionally lists the fields of secondary indexes, as
+	// specified in the table descriptor, whose keys sort the entries where an
+	// optional field is unset after the entries where it is set, like NULLS
+	// LAST in SQL, instead of encoding unset fields as their default values.
+	// It applies to the fields of these indexes which have presence, such as
+	// message fields, and which aren't primary key fields. Reverse iteration
+	// then lists unset fields first. Unset values are passed as nil values in
+	// keys used to query these indexes, and decoded as invalid
+	// protoreflect.Value values.
+	NullsLastIndexes []string
+
+	// OrderedEnumIndexes optionally lists the fields of secondary indexes, as
+	// specified in the table descriptor, whose keys encode enum fields so that
+	// iteration follows the numeric order of the enum values, including
+	// negative values, see ormfield.OrderedEnumCodec. Enum fields of other
+	// indexes are encoded as varints, which only support equality lookups.
+	// The encodings differ, so an existing index can't be made ordered
+	// without migrating its keys.
+	OrderedEnumIndexes []string
+
+	// SkipUnsetUniqueIndexes optionally lists the fields of unique indexes, as
+	// specified in the table descriptor, which don't index the entries where
+	// one of their fields is unset, so that such entries never collide with
+	// each other, like NULL values in SQL unique constraints. A field is unset
+	// when protoreflect.Message.Has returns false for it, i.e. when an
+	// optional field isn't present or when a field without presence has its
+	// default value. Primary key fields are never considered unset. The
+	// entries with unset fields can't be looked up with these indexes. Other
+	// unique indexes index all entries, so that entries with the same unset
+	// values collide.
+	SkipUnsetUniqueIndexes []string
+
+	// VersionField is an optional uint64 field, which can't be part of the
+	// primary key, used for optimistic concurrency control. Updates must set
+	// it to the version of the stored entry, otherwise they fail with
+	// ormerrors.VersionConflict, and it is incremented on successful updates.
+	VersionField string
+
+	// ExpiryField is an optional google.protobuf.Timestamp field holding the
+	// time at which entries expire, so that they can be deleted with
+	// PurgeExpired. The table must define an ascending index whose first field
+	// is ExpiryField, listed in NullsLastIndexes, so that PurgeExpired only
+	// reads expired entries. Entries where it is unset never expire.
+	ExpiryField string
+
+	// InsertionOrderField is an optional uint64 field, which can't be part of
+	// the primary key, holding the position at which entries were inserted.
+	// Inserts set it to the next value of a sequence persisted in the index
+	// store, and updates keep the stored position, so that listing an
+	// ascending index whose first field is InsertionOrderField iterates over
+	// entries in insertion order, or in reverse with ormlist.Reverse. Positions
+	// are never reused, deleted entries leave gaps. Inserts of entries with a
+	// non-zero position fail with ormerrors.InsertionPositionAlreadySet,
+	// except for imports, where entries keep their position and advance the
+	// sequence past it, so that imports reproduce the exported order.
+	InsertionOrderField string
+
+	// IndexNormalizers is an optional map of secondary index fields to
+	// functions, such as strings.ToLower, normalizing the values of the string
+	// fields of these indexes before they are encoded. Keys used to query
+	// these indexes are normalized the same way, so that for instance unique
+	// indexes can be made case-insensitive. Normalization is lossy: the
+	// original values can't be recovered from index keys, which is why
+	// normalized string fields can't be part of the primary key.
+	IndexNormalizers map[string]func(string) string
+
+	// IndexHashedFields is an optional map of secondary index fields to bytes
+	// fields of these indexes whose values are stored in index keys as their
+	// hashes computed with KeyHash, so that index keys stay small and of fixed
+	// length for large values. Keys used to query these indexes are hashed the
+	// same way, but such indexes only support equality lookups on hashed
+	// fields, not range iteration, and index keys contain the hashes rather
+	// than the original values. Primary key fields can't be hashed.
+	IndexHashedFields map[string][]string
+
+	// IndexDecimalFields is an optional map of secondary index fields to
+	// string fields of these indexes holding decimal numbers, such as the
+	// string representation of sdk.Dec, which are encoded in index keys so
+	// that iteration follows their numeric order rather than the lexicographic
+	// order of the strings, see ormfield.DecimalCodec. Inserts and updates with
+	// values which aren't decimals fail with ormerrors.InvalidDecimal. Index
+	// keys contain the canonical decimals rather than the original strings, so
+	// primary key fields can't be decimal fields.
+	IndexDecimalFields map[string][]string
+
+	// IndexMaxKeyLen is an optional map of secondary index fields to the
+	// maximum length in bytes of the keys of these indexes, so that messages
+	// with huge values in indexed fields can't bloat the index store. Inserts
+	// and updates writing a longer key fail with ormerrors.KeyTooLong. A
+	// maximum length of 0 means no limit.
+	IndexMaxKeyLen map[string]int
+
+	// SumAggregates is an optional map of group fields, as comma-separated
+	// field names, to integer fields whose sum over the entries of the table
+	// is maintained for each prefix of the values of the group fields, so that
+	// Sum can return the sum over the entries with a given prefix without
+	// iterating over them. Sums are updated incrementally on every insert,
+	// update and delete, trading write cost for fast reads. The summed fields
+	// must be signed integers or uint32s, and the group fields valid key
+	// fields, which don't need to be indexed.
+	SumAggregates map[string]string
+
+	// MinMaxAggregates is an optional map of group fields, as comma-separated
+	// field names, to integer fields whose minimum and maximum over the
+	// entries of the table are tracked for each prefix of the values of the
+	// group fields, so that MinMax can return them for the entries with a
+	// given prefix without iterating over them. Each entry costs one index
+	// store key per prefix length, updated on every insert, update and
+	// delete. The tracked fields must be signed integers or uint32s which
+	// aren't group fields, and the group fields valid key fields, which don't
+	// need to be indexed.
+	MinMaxAggregates map[string]string
+
+	// Sequences optionally lists the names of uint64 sequences stored with
+	// the table, see GetSequence, which can be used to generate identifiers
+	// independently of the primary key, including for tables without an
+	// auto-incrementing primary key. They are included in JSON and binary
+	// exports.
+	Sequences []string
+
+	// KeyHash is the hash function used for IndexHashedFields. It defaults to
+	// sha256 and must be collision-resistant, since entries whose hashed
+	// values collide are indistinguishable in the index.
+	KeyHash func([]byte) []byte
 }
 
 // TypeResolver is an interface that can be used for the protoreflect.UnmarshalOptions.Resolver option.
@@ -87,6 +230,8 @@ func Build(options Options) (Table, error) {
 		indexesByFields:       map[fieldnames.FieldNames]concreteIndex{},
 		uniqueIndexesByFields: map[fieldnames.FieldNames]UniqueIndex{},
 		entryCodecsById:       map[uint32]ormkv.EntryCodec{},
+		sumAggregates:         map[fieldnames.FieldNames]*sumAggregate{},
+		minMaxAggregates:      map[fieldnames.FieldNames]*minMaxAggregate{},
 		indexesById:           map[uint32]Index{},
 		typeResolver:          options.TypeResolver,
 		customJSONValidator:   options.JSONValidator,
@@ -129,7 +274,9 @@ func Build(options Options) (Table, error) {
 		table.tablePrefix = prefix
 		table.tableId = singletonDesc.Id
 
-		return &singleton{table}, nil
+		singletonTable := &singleton{table}
+		pkIndex.insert = singletonTable.Insert
+		return singletonTable, nil
 	default:
 		return nil, ormerrors.InvalidTableDefinition.Wrapf("missing table descriptor for %s", messageDescriptor.FullName())
 	}
@@ -173,6 +320,84 @@ func Build(options Options) (Table, error) {
 	table.indexesById[primaryKeyId] = pkIndex
 	table.indexes = append(table.indexes, pkIndex)
 
+	if options.VersionField != "" {
+		versionField := messageDescriptor.Fields().ByName(protoreflect.Name(options.VersionField))
+		if versionField == nil {
+			return nil, ormerrors.FieldNotFound.Wrapf("version field %s on %s", options.VersionField, messageDescriptor.FullName())
+		}
+
+		if (versionField.Kind() != protoreflect.Uint64Kind && versionField.Kind() != protoreflect.Fixed64Kind) || versionField.IsList() {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("version field %s must be a uint64", versionField.FullName())
+		}
+
+		if isPrimaryKeyField(versionField.Name(), pkFieldNames) {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("version field %s can't be part of the primary key", versionField.FullName())
+		}
+
+		table.versionField = versionField
+	}
+
+	indexFilters := map[fieldnames.FieldNames]func(proto.Message) bool{}
+	for fields, filter := range options.IndexFilters {
+		indexFilters[fieldnames.CommaSeparatedFieldNames(fields)] = filter
+	}
+
+	indexNormalizers := map[fieldnames.FieldNames]func(string) string{}
+	for fields, normalize := range options.IndexNormalizers {
+		indexNormalizers[fieldnames.CommaSeparatedFieldNames(fields)] = normalize
+	}
+
+	indexHashedFields := map[fieldnames.FieldNames][]protoreflect.Name{}
+	for fields, hashedFields := range options.IndexHashedFields {
+		names := make([]protoreflect.Name, len(hashedFields))
+		for i, field := range hashedFields {
+			names[i] = protoreflect.Name(field)
+		}
+		indexHashedFields[fieldnames.CommaSeparatedFieldNames(fields)] = names
+	}
+
+	indexDecimalFields := map[fieldnames.FieldNames][]protoreflect.Name{}
+	for fields, decimalFields := range options.IndexDecimalFields {
+		names := make([]protoreflect.Name, len(decimalFields))
+		for i, field := range decimalFields {
+			names[i] = protoreflect.Name(field)
+		}
+		indexDecimalFields[fieldnames.CommaSeparatedFieldNames(fields)] = names
+	}
+
+	indexMaxKeyLens := map[fieldnames.FieldNames]int{}
+	for fields, maxKeyLen := range options.IndexMaxKeyLen {
+		if maxKeyLen < 0 {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("negative max key length %d for index with fields %s", maxKeyLen, fields)
+		}
+		indexMaxKeyLens[fieldnames.CommaSeparatedFieldNames(fields)] = maxKeyLen
+	}
+
+	keyHash := options.KeyHash
+	if keyHash == nil {
+		keyHash = sha256Hash
+	}
+
+	descendingIndexes := map[fieldnames.FieldNames]bool{}
+	for _, fields := range options.DescendingIndexes {
+		descendingIndexes[fieldnames.CommaSeparatedFieldNames(fields)] = true
+	}
+
+	nullsLastIndexes := map[fieldnames.FieldNames]bool{}
+	for _, fields := range options.NullsLastIndexes {
+		nullsLastIndexes[fieldnames.CommaSeparatedFieldNames(fields)] = true
+	}
+
+	orderedEnumIndexes := map[fieldnames.FieldNames]bool{}
+	for _, fields := range options.OrderedEnumIndexes {
+		orderedEnumIndexes[fieldnames.CommaSeparatedFieldNames(fields)] = true
+	}
+
+	skipUnsetIndexes := map[fieldnames.FieldNames]bool{}
+	for _, fields := range options.SkipUnsetUniqueIndexes {
+		skipUnsetIndexes[fieldnames.CommaSeparatedFieldNames(fields)] = true
+	}
+
 	for _, idxDesc := range tableDesc.Index {
 		id := idxDesc.Id
 		if id == 0 || id >= indexIdLimit {
@@ -190,6 +415,29 @@ func Build(options Options) (Table, error) {
 		// altNames contains all the alternative "names" of this index
 		altNames := map[fieldnames.FieldNames]bool{idxFields: true}
 
+		hashedFields, hashed := indexHashedFields[idxFields]
+		if hashed {
+			if err := checkHashedFields(idxFields, hashedFields, pkFieldNames); err != nil {
+				return nil, err
+			}
+		}
+
+		decimalFields, decimal := indexDecimalFields[idxFields]
+		if decimal {
+			if err := checkDecimalFields(idxFields, decimalFields, pkFieldNames); err != nil {
+				return nil, err
+			}
+		}
+
+		var nullableFields []protoreflect.Name
+		if nullsLastIndexes[idxFields] {
+			var err error
+			nullableFields, err = getNullableFields(messageDescriptor, idxFields, pkFieldNames)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		if idxDesc.Unique && isNonTrivialUniqueKey(idxFields.Names(), pkFieldNames) {
 			uniqCdc, err := ormkv.NewUniqueKeyCodec(
 				idxPrefix,
@@ -200,6 +448,42 @@ func Build(options Options) (Table, error) {
 			if err != nil {
 				return nil, err
 			}
+			if normalize, ok := indexNormalizers[idxFields]; ok {
+				uniqCdc, err = uniqCdc.NormalizeStrings(normalize)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if hashed {
+				uniqCdc, err = uniqCdc.HashBytesFields(hashedFields, keyHash)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if decimal {
+				uniqCdc, err = uniqCdc.DecimalFields(decimalFields)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if orderedEnumIndexes[idxFields] {
+				uniqCdc, err = uniqCdc.OrderedEnums()
+				if err != nil {
+					return nil, err
+				}
+			}
+			if nullableFields != nil {
+				uniqCdc, err = uniqCdc.NullsLast(nullableFields)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if descendingIndexes[idxFields] {
+				uniqCdc, err = uniqCdc.Descending()
+				if err != nil {
+					return nil, err
+				}
+			}
 			uniqIdx := &uniqueKeyIndex{
 				UniqueKeyCodec: uniqCdc,
 				fields:         idxFields,
@@ -218,6 +502,42 @@ func Build(options Options) (Table, error) {
 			if err != nil {
 				return nil, err
 			}
+			if normalize, ok := indexNormalizers[idxFields]; ok {
+				idxCdc, err = idxCdc.NormalizeStrings(normalize)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if hashed {
+				idxCdc, err = idxCdc.HashBytesFields(hashedFields, keyHash)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if decimal {
+				idxCdc, err = idxCdc.DecimalFields(decimalFields)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if orderedEnumIndexes[idxFields] {
+				idxCdc, err = idxCdc.OrderedEnums()
+				if err != nil {
+					return nil, err
+				}
+			}
+			if nullableFields != nil {
+				idxCdc, err = idxCdc.NullsLast(nullableFields)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if descendingIndexes[idxFields] {
+				idxCdc, err = idxCdc.Descending()
+				if err != nil {
+					return nil, err
+				}
+			}
 			index = &indexKeyIndex{
 				IndexKeyCodec:  idxCdc,
 				fields:         idxFields,
@@ -269,7 +589,185 @@ func Build(options Options) (Table, error) {
 		table.entryCodecsById[id] = index
 		table.indexesById[id] = index
 		table.indexes = append(table.indexes, index)
-		table.indexers = append(table.indexers, index.(indexer))
+		var idxIndexer indexer = index.(indexer)
+		if maxKeyLen, ok := indexMaxKeyLens[idxFields]; ok {
+			if maxKeyLen != 0 {
+				idxIndexer = keyLenLimitedIndexer{indexer: idxIndexer, fields: idxFields, maxKeyLen: maxKeyLen}
+			}
+			delete(indexMaxKeyLens, idxFields)
+		}
+		filter, filtered := indexFilters[idxFields]
+		if skipUnsetIndexes[idxFields] {
+			if !idxDesc.Unique {
+				return nil, ormerrors.InvalidTableDefinition.Wrapf("can't skip unset fields of non-unique index with fields %s", idxFields)
+			}
+			filter = skipUnsetFilter(messageDescriptor, idxFields.Names(), pkFieldNames, filter)
+			filtered = true
+			delete(skipUnsetIndexes, idxFields)
+		}
+		if filtered {
+			idxIndexer = filteredIndexer{indexer: idxIndexer, filter: filter}
+			delete(indexFilters, idxFields)
+		}
+		delete(descendingIndexes, idxFields)
+		delete(nullsLastIndexes, idxFields)
+		delete(orderedEnumIndexes, idxFields)
+		delete(indexHashedFields, idxFields)
+		delete(indexDecimalFields, idxFields)
+		if _, ok := indexNormalizers[idxFields]; ok {
+			// primary key values are decoded from index keys, so they can't be
+			// normalized
+			for _, field := range index.GetFieldNames() {
+				if !isPrimaryKeyField(field, pkFieldNames) {
+					continue
+				}
+				if messageDescriptor.Fields().ByName(field).Kind() == protoreflect.StringKind {
+					return nil, ormerrors.InvalidTableDefinition.Wrapf("normalized index with fields %s can't contain the string primary key field %s", idxFields, field)
+				}
+			}
+			delete(indexNormalizers, idxFields)
+		}
+		table.indexers = append(table.indexers, idxIndexer)
+	}
+
+	for fields := range indexFilters {
+		return nil, ormerrors.CantFindIndex.Wrapf("can't filter index with fields %s on table %s", fields, messageDescriptor.FullName())
+	}
+
+	for fields := range indexNormalizers {
+		return nil, ormerrors.CantFindIndex.Wrapf("can't normalize index with fields %s on table %s", fields, messageDescriptor.FullName())
+	}
+
+	for fields := range indexHashedFields {
+		return nil, ormerrors.CantFindIndex.Wrapf("can't hash fields of index with fields %s on table %s", fields, messageDescriptor.FullName())
+	}
+
+	for fields := range indexDecimalFields {
+		return nil, ormerrors.CantFindIndex.Wrapf("can't encode decimal fields of index with fields %s on table %s", fields, messageDescriptor.FullName())
+	}
+
+	for fields := range indexMaxKeyLens {
+		return nil, ormerrors.CantFindIndex.Wrapf("can't limit key length of index with fields %s on table %s", fields, messageDescriptor.FullName())
+	}
+
+	for fields := range skipUnsetIndexes {
+		return nil, ormerrors.CantFindIndex.Wrapf("can't skip unset fields of index with fields %s on table %s", fields, messageDescriptor.FullName())
+	}
+
+	for fields := range descendingIndexes {
+		return nil, ormerrors.CantFindIndex.Wrapf("can't make index with fields %s descending on table %s", fields, messageDescriptor.FullName())
+	}
+
+	for fields := range nullsLastIndexes {
+		return nil, ormerrors.CantFindIndex.Wrapf("can't sort nulls last in index with fields %s on table %s", fields, messageDescriptor.FullName())
+	}
+
+	for fields := range orderedEnumIndexes {
+		return nil, ormerrors.CantFindIndex.Wrapf("can't order enums of index with fields %s on table %s", fields, messageDescriptor.FullName())
+	}
+
+	if options.ExpiryField != "" {
+		expiryField := messageDescriptor.Fields().ByName(protoreflect.Name(options.ExpiryField))
+		if expiryField == nil {
+			return nil, ormerrors.FieldNotFound.Wrapf("expiry field %s on %s", options.ExpiryField, messageDescriptor.FullName())
+		}
+
+		if expiryField.Kind() != protoreflect.MessageKind || expiryField.IsList() || expiryField.Message().FullName() != timestampFullName {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("expiry field %s must be a %s", expiryField.FullName(), timestampFullName)
+		}
+
+		expiryIndex, ok := table.indexesByFields[fieldnames.CommaSeparatedFieldNames(options.ExpiryField)]
+		if !ok {
+			return nil, ormerrors.CantFindIndex.Wrapf("no index on expiry field %s", expiryField.FullName())
+		}
+
+		for _, fields := range options.DescendingIndexes {
+			if table.indexesByFields[fieldnames.CommaSeparatedFieldNames(fields)] == expiryIndex {
+				return nil, ormerrors.InvalidTableDefinition.Wrapf("index on expiry field %s can't be descending", expiryField.FullName())
+			}
+		}
+
+		nullsLast := false
+		for _, fields := range options.NullsLastIndexes {
+			if table.indexesByFields[fieldnames.CommaSeparatedFieldNames(fields)] == expiryIndex {
+				nullsLast = true
+			}
+		}
+		if !nullsLast {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("index on expiry field %s must sort nulls last", expiryField.FullName())
+		}
+
+		table.expiryField = expiryField
+		table.expiryIndex = expiryIndex
+	}
+
+	for fields, field := range options.SumAggregates {
+		groupFields := fieldnames.CommaSeparatedFieldNames(fields)
+		agg, err := newSumAggregate(prefix, options.MessageType, groupFields, field)
+		if err != nil {
+			return nil, err
+		}
+		table.sumAggregates[groupFields] = agg
+		table.indexers = append(table.indexers, agg)
+	}
+	if len(table.sumAggregates) != 0 {
+		table.entryCodecsById[sumsId] = sumAggregatesCodec(table.sumAggregates)
+	}
+
+	for fields, field := range options.MinMaxAggregates {
+		groupFields := fieldnames.CommaSeparatedFieldNames(fields)
+		agg, err := newMinMaxAggregate(prefix, options.MessageType, groupFields, field, pkIndex.GetFieldNames())
+		if err != nil {
+			return nil, err
+		}
+		table.minMaxAggregates[groupFields] = agg
+		table.indexers = append(table.indexers, agg)
+	}
+	if len(table.minMaxAggregates) != 0 {
+		table.entryCodecsById[minMaxId] = minMaxAggregatesCodec(table.minMaxAggregates)
+	}
+
+	for _, name := range options.Sequences {
+		if table.getSequence(name) != nil {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("duplicate sequence %s", name)
+		}
+
+		seq, err := newSequence(prefix, options.MessageType, name, pkIndex)
+		if err != nil {
+			return nil, err
+		}
+		table.sequences = append(table.sequences, seq)
+	}
+	if len(table.sequences) != 0 {
+		table.entryCodecsById[sequencesId] = sequencesCodec(table.sequences)
+	}
+
+	if options.InsertionOrderField != "" {
+		insertionOrderField := messageDescriptor.Fields().ByName(protoreflect.Name(options.InsertionOrderField))
+		if insertionOrderField == nil {
+			return nil, ormerrors.FieldNotFound.Wrapf("insertion order field %s on %s", options.InsertionOrderField, messageDescriptor.FullName())
+		}
+
+		if (insertionOrderField.Kind() != protoreflect.Uint64Kind && insertionOrderField.Kind() != protoreflect.Fixed64Kind) || insertionOrderField.IsList() {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("insertion order field %s must be a uint64", insertionOrderField.FullName())
+		}
+
+		if isPrimaryKeyField(insertionOrderField.Name(), pkFieldNames) {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("insertion order field %s can't be part of the primary key", insertionOrderField.FullName())
+		}
+
+		if insertionOrderField == table.versionField {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("insertion order field %s can't be the version field", insertionOrderField.FullName())
+		}
+
+		if !hasAscendingIndexOn(table, insertionOrderField.Name(), options.DescendingIndexes) {
+			return nil, ormerrors.CantFindIndex.Wrapf("no ascending index on insertion order field %s", insertionOrderField.FullName())
+		}
+
+		insertionSeqPrefix := encodeutil.AppendVarUInt32(prefix, insertionSeqId)
+		table.insertionOrderField = insertionOrderField
+		table.insertionSeqCodec = ormkv.NewSeqCodec(options.MessageType, insertionSeqPrefix)
+		table.entryCodecsById[insertionSeqId] = table.insertionSeqCodec
 	}
 
 	if tableDesc.PrimaryKey.AutoIncrement {
@@ -281,12 +779,110 @@ func Build(options Options) (Table, error) {
 		seqPrefix := encodeutil.AppendVarUInt32(prefix, seqId)
 		seqCodec := ormkv.NewSeqCodec(options.MessageType, seqPrefix)
 		table.entryCodecsById[seqId] = seqCodec
-		return &autoIncrementTable{
+		autoIncTable := &autoIncrementTable{
 			tableImpl:    table,
 			autoIncField: autoIncField,
 			seqCodec:     seqCodec,
-		}, nil
+		}
+		pkIndex.insert = autoIncTable.Insert
+		return autoIncTable, nil
 	}
 
+	pkIndex.insert = table.Insert
 	return table, nil
 }
+
+// checkHashedFields checks that the hashed fields of the index with the
+// given fields are fields of the index and not primary key fields, since
+// primary key values are decoded from index keys.
+func checkHashedFields(idxFields fieldnames.FieldNames, hashedFields, pkFieldNames []protoreflect.Name) error {
+	idxFieldNames := map[protoreflect.Name]bool{}
+	for _, name := range idxFields.Names() {
+		idxFieldNames[name] = true
+	}
+
+	for _, field := range hashedFields {
+		if !idxFieldNames[field] {
+			return ormerrors.FieldNotFound.Wrapf("hashed field %s isn't a field of index with fields %s", field, idxFields)
+		}
+		if isPrimaryKeyField(field, pkFieldNames) {
+			return ormerrors.InvalidTableDefinition.Wrapf("index with fields %s can't hash the primary key field %s", idxFields, field)
+		}
+	}
+	return nil
+}
+
+// checkDecimalFields checks that the decimal fields of the index with the
+// given fields are fields of the index and not primary key fields, since
+// primary key values are decoded from index keys.
+func checkDecimalFields(idxFields fieldnames.FieldNames, decimalFields, pkFieldNames []protoreflect.Name) error {
+	idxFieldNames := map[protoreflect.Name]bool{}
+	for _, name := range idxFields.Names() {
+		idxFieldNames[name] = true
+	}
+
+	for _, field := range decimalFields {
+		if !idxFieldNames[field] {
+			return ormerrors.FieldNotFound.Wrapf("decimal field %s isn't a field of index with fields %s", field, idxFields)
+		}
+		if isPrimaryKeyField(field, pkFieldNames) {
+			return ormerrors.InvalidTableDefinition.Wrapf("index with fields %s can't encode the primary key field %s as a decimal", idxFields, field)
+		}
+	}
+	return nil
+}
+
+func sha256Hash(bz []byte) []byte {
+	hash := sha256.Sum256(bz)
+	return hash[:]
+}
+
+// getNullableFields returns the fields of the index with the provided fields
+// which can be null with Options.NullsLastIndexes: the non-primary key fields
+// with presence.
+func getNullableFields(messageDescriptor protoreflect.MessageDescriptor, idxFields fieldnames.FieldNames, pkFieldNames []protoreflect.Name) ([]protoreflect.Name, error) {
+	var nullableFields []protoreflect.Name
+	for _, name := range idxFields.Names() {
+		field := messageDescriptor.Fields().ByName(name)
+		if field == nil {
+			return nil, ormerrors.FieldNotFound.Wrapf("field %s on %s", name, messageDescriptor.FullName())
+		}
+		if field.HasPresence() && !field.IsList() && !isPrimaryKeyField(name, pkFieldNames) {
+			nullableFields = append(nullableFields, name)
+		}
+	}
+
+	if len(nullableFields) == 0 {
+		return nil, ormerrors.InvalidTableDefinition.Wrapf("index with fields %s has no optional non-primary key field to sort nulls last", idxFields)
+	}
+
+	return nullableFields, nil
+}
+
+// hasAscendingIndexOn checks if the table has an ascending secondary index whose
+// first field is field.
+func hasAscendingIndexOn(table *tableImpl, field protoreflect.Name, descendingIndexes []string) bool {
+	descending := map[concreteIndex]bool{}
+	for _, fields := range descendingIndexes {
+		if idx, ok := table.indexesByFields[fieldnames.CommaSeparatedFieldNames(fields)]; ok {
+			descending[idx] = true
+		}
+	}
+
+	for fields, idx := range table.indexesByFields {
+		if fields.Names()[0] == field && !descending[idx] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isPrimaryKeyField(field protoreflect.Name, pkFieldNames []protoreflect.Name) bool {
+	for _, name := range pkFieldNames {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
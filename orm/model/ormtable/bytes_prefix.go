@@ -0,0 +1,137 @@
+package ormtable
+
+import (
+	"bytes"
+
+	"github.com/cosmos/cosmos-sdk/orm/types/kv"
+)
+
+// multiPrefixIterator iterates over the keys with any of several prefixes,
+// scanning them one after the other. The prefixes must be in ascending order
+// and none of them may be a prefix of another one, so that the concatenation
+// of the scans is ordered.
+type multiPrefixIterator struct {
+	store   kv.ReadonlyStore
+	reverse bool
+
+	// ranges are the remaining [start, end) ranges to scan, in iteration
+	// order.
+	ranges     [][2][]byte
+	start, end []byte
+	iterator   kv.Iterator
+	err        error
+}
+
+func newMultiPrefixIterator(store kv.ReadonlyStore, prefixes [][]byte, cursor []byte, reverse bool) (*multiPrefixIterator, error) {
+	var ranges [][2][]byte
+	for _, prefix := range prefixes {
+		start, end := prefix, prefixEndBytes(prefix)
+		if len(cursor) != 0 {
+			if !reverse {
+				// must start right after cursor
+				if end != nil && bytes.Compare(cursor, end) >= 0 {
+					continue
+				}
+				if bytes.Compare(cursor, start) >= 0 {
+					start = inclusiveEndBytes(append([]byte{}, cursor...))
+				}
+			} else {
+				// end bytes is already exclusive by default
+				if bytes.Compare(cursor, start) <= 0 {
+					continue
+				}
+				if end == nil || bytes.Compare(cursor, end) < 0 {
+					end = cursor
+				}
+			}
+		}
+		ranges = append(ranges, [2][]byte{start, end})
+	}
+
+	it := &multiPrefixIterator{store: store, reverse: reverse}
+	if len(ranges) == 0 {
+		return it, nil
+	}
+
+	it.start, it.end = ranges[0][0], ranges[len(ranges)-1][1]
+	if reverse {
+		for i, j := 0, len(ranges)-1; i < j; i, j = i+1, j-1 {
+			ranges[i], ranges[j] = ranges[j], ranges[i]
+		}
+	}
+	it.ranges = ranges
+
+	if err := it.skipEmptyRanges(); err != nil {
+		it.Close()
+		return nil, err
+	}
+
+	return it, nil
+}
+
+// skipEmptyRanges opens iterators over the remaining ranges until one of them
+// is valid or there are no ranges left.
+func (m *multiPrefixIterator) skipEmptyRanges() error {
+	for (m.iterator == nil || !m.iterator.Valid()) && len(m.ranges) != 0 {
+		if m.iterator != nil {
+			if err := m.iterator.Close(); err != nil {
+				return err
+			}
+			m.iterator = nil
+		}
+
+		r := m.ranges[0]
+		m.ranges = m.ranges[1:]
+
+		var err error
+		if m.reverse {
+			m.iterator, err = m.store.ReverseIterator(r[0], r[1])
+		} else {
+			m.iterator, err = m.store.Iterator(r[0], r[1])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *multiPrefixIterator) Domain() (start, end []byte) {
+	return m.start, m.end
+}
+
+func (m *multiPrefixIterator) Valid() bool {
+	return m.err == nil && m.iterator != nil && m.iterator.Valid()
+}
+
+func (m *multiPrefixIterator) Next() {
+	m.iterator.Next()
+	m.err = m.skipEmptyRanges()
+}
+
+func (m *multiPrefixIterator) Key() []byte {
+	return m.iterator.Key()
+}
+
+func (m *multiPrefixIterator) Value() []byte {
+	return m.iterator.Value()
+}
+
+func (m *multiPrefixIterator) Error() error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.iterator != nil {
+		return m.iterator.Error()
+	}
+	return nil
+}
+
+func (m *multiPrefixIterator) Close() error {
+	if m.iterator == nil {
+		return nil
+	}
+	return m.iterator.Close()
+}
+
+var _ kv.Iterator = &multiPrefixIterator{}
@@ -0,0 +1,183 @@
+package ormtable
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cosmos/cosmos-sdk/orm/encoding/encodeutil"
+	"github.com/cosmos/cosmos-sdk/orm/encoding/ormkv"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// CachingUniqueIndex wraps a UniqueIndex and memoizes the messages decoded by
+// Get, keyed by the encoded lookup key, evicting the least recently used
+// entries once the cache holds its maximum number of entries.
+//
+// The cache isn't aware of the underlying store, so it must be registered as
+// the WriteHooks (possibly using MultiWriteHooks) of every backend writing to
+// the table in order for cache entries to be invalidated when the
+// corresponding entries are inserted, updated or deleted. Because it isn't
+// aware of transactions either, it should only be used for queries and never
+// for state machine reads, so the cache is only used for contexts returned by
+// EnableIndexCache and all other reads are passed through to the index.
+type CachingUniqueIndex struct {
+	UniqueIndex
+	keyCodec   *ormkv.KeyCodec
+	maxEntries int
+
+	mtx     sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cacheEntry struct {
+	key     string
+	message proto.Message
+}
+
+// NewCachingUniqueIndex returns a CachingUniqueIndex caching up to maxEntries
+// messages retrieved from index. If maxEntries is not positive, caching is
+// disabled and all calls are passed through to index.
+func NewCachingUniqueIndex(index UniqueIndex, maxEntries int) (*CachingUniqueIndex, error) {
+	keyCodec, err := uniqueIndexKeyCodec(index)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CachingUniqueIndex{
+		UniqueIndex: index,
+		keyCodec:    keyCodec,
+		maxEntries:  maxEntries,
+		entries:     map[string]*list.Element{},
+		lru:         list.New(),
+	}, nil
+}
+
+func uniqueIndexKeyCodec(index UniqueIndex) (*ormkv.KeyCodec, error) {
+	switch index := index.(type) {
+	case *primaryKeyIndex:
+		return index.KeyCodec, nil
+	case *uniqueKeyIndex:
+		return index.GetKeyCodec(), nil
+	default:
+		return nil, ormerrors.UnsupportedOperation.Wrapf("can't cache index %T", index)
+	}
+}
+
+type enableIndexCacheKey struct{}
+
+// EnableIndexCache returns a context for which CachingUniqueIndex reads use
+// the cache. It should only wrap query contexts, whose backend is observed by
+// the cache.
+func EnableIndexCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, enableIndexCacheKey{}, true)
+}
+
+func (c *CachingUniqueIndex) enabled(ctx context.Context) bool {
+	return c.maxEntries > 0 && ctx.Value(enableIndexCacheKey{}) != nil
+}
+
+// Get retrieves the message using the cache if it is enabled for ctx, see
+// UniqueIndex.Get.
+func (c *CachingUniqueIndex) Get(ctx context.Context, message proto.Message, keyValues ...interface{}) (found bool, err error) {
+	if !c.enabled(ctx) {
+		return c.UniqueIndex.Get(ctx, message, keyValues...)
+	}
+
+	keyBz, err := c.keyCodec.EncodeKey(encodeutil.ValuesOf(keyValues...))
+	if err != nil {
+		return false, err
+	}
+	key := string(keyBz)
+
+	c.mtx.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		proto.Reset(message)
+		proto.Merge(message, elem.Value.(*cacheEntry).message)
+		c.mtx.Unlock()
+		return true, nil
+	}
+	c.mtx.Unlock()
+
+	found, err = c.UniqueIndex.Get(ctx, message, keyValues...)
+	if err != nil || !found {
+		return found, err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		return true, nil
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, message: proto.Clone(message)})
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+
+	return true, nil
+}
+
+// Len returns the number of cached messages.
+func (c *CachingUniqueIndex) Len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.lru.Len()
+}
+
+// Purge removes all cached messages.
+func (c *CachingUniqueIndex) Purge() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries = map[string]*list.Element{}
+	c.lru.Init()
+}
+
+func (c *CachingUniqueIndex) invalidate(message proto.Message) {
+	mref := message.ProtoReflect()
+	if mref.Descriptor().FullName() != c.keyCodec.MessageType().Descriptor().FullName() {
+		return
+	}
+
+	_, keyBz, err := c.keyCodec.EncodeKeyFromMessage(mref)
+	if err != nil {
+		// the key can't be known, so all entries may be stale
+		c.Purge()
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if elem, ok := c.entries[string(keyBz)]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, string(keyBz))
+	}
+}
+
+// OnInsert implements WriteHooks.OnInsert.
+func (c *CachingUniqueIndex) OnInsert(_ context.Context, message proto.Message) {
+	c.invalidate(message)
+}
+
+// OnUpdate implements WriteHooks.OnUpdate, invalidating both the existing
+// and the new key.
+func (c *CachingUniqueIndex) OnUpdate(_ context.Context, existing, new proto.Message) {
+	c.invalidate(existing)
+	c.invalidate(new)
+}
+
+// OnDelete implements WriteHooks.OnDelete.
+func (c *CachingUniqueIndex) OnDelete(_ context.Context, message proto.Message) {
+	c.invalidate(message)
+}
+
+var (
+	_ UniqueIndex = &CachingUniqueIndex{}
+	_ WriteHooks  = &CachingUniqueIndex{}
+)
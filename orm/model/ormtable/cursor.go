@@ -0,0 +1,79 @@
+package ormtable
+
+import (
+	"bytes"
+
+	"github.com/cosmos/cosmos-sdk/orm/model/ormlist"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// cursorVersion is the first byte of encoded cursors and allows the encoding
+// to change in the future.
+const cursorVersion byte = 1
+
+// EncodeCursor encodes a cursor returned by an iterator over index so that it
+// can be handed out to clients, for instance as the next key of a paginated
+// query. Encoded cursors must be decoded with DecodeCursor before being passed
+// to ormlist.Cursor. An empty cursor is encoded as nil.
+func EncodeCursor(index Index, cursor ormlist.CursorT) ([]byte, error) {
+	if len(cursor) == 0 {
+		return nil, nil
+	}
+
+	err := checkCursor(index, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{cursorVersion}, cursor...), nil
+}
+
+// DecodeCursor decodes a cursor encoded with EncodeCursor for the same index.
+// An ormerrors.InvalidCursor error is returned if the cursor is malformed or
+// was returned by an iterator over another index, so that client provided
+// cursors can't resume iteration at an unrelated position. An empty cursor is
+// decoded as nil.
+func DecodeCursor(index Index, bz []byte) (ormlist.CursorT, error) {
+	if len(bz) == 0 {
+		return nil, nil
+	}
+
+	if bz[0] != cursorVersion {
+		return nil, ormerrors.InvalidCursor.Wrapf("unknown cursor version %d", bz[0])
+	}
+
+	cursor := ormlist.CursorT(bz[1:])
+	err := checkCursor(index, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor, nil
+}
+
+func checkCursor(index Index, cursor ormlist.CursorT) error {
+	prefix, err := indexPrefix(index)
+	if err != nil {
+		return err
+	}
+
+	if len(cursor) <= len(prefix) || !bytes.HasPrefix(cursor, prefix) {
+		return ormerrors.InvalidCursor.Wrapf("cursor doesn't belong to index %s of %s",
+			index.Fields(), index.MessageType().Descriptor().FullName())
+	}
+
+	return nil
+}
+
+// indexPrefix returns the store prefix of all the keys of index.
+func indexPrefix(index Index) ([]byte, error) {
+	switch index := index.(type) {
+	case *uniqueKeyIndex:
+		return index.GetKeyCodec().Prefix(), nil
+	case interface{ Prefix() []byte }:
+		// primary keys, tables and non-unique indexes
+		return index.Prefix(), nil
+	default:
+		return nil, ormerrors.UnsupportedOperation.Wrapf("can't get the prefix of index %T", index)
+	}
+}
@@ -0,0 +1,64 @@
+package ormtable
+
+import (
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// UniqueKeyViolationError is returned when inserting or updating an entry
+// violates a unique index constraint. It wraps ormerrors.UniqueKeyViolation.
+type UniqueKeyViolationError struct {
+	// Fields are the fields of the unique index.
+	Fields string
+
+	// Values are the values of the index fields which already exist in
+	// the table.
+	Values []protoreflect.Value
+}
+
+func (e *UniqueKeyViolationError) Error() string {
+	return ormerrors.UniqueKeyViolation.Wrapf("%q", e.Fields).Error()
+}
+
+func (e *UniqueKeyViolationError) Unwrap() error {
+	return ormerrors.UniqueKeyViolation
+}
+
+func (e *UniqueKeyViolationError) Cause() error {
+	return ormerrors.UniqueKeyViolation
+}
+
+func (e *UniqueKeyViolationError) GRPCStatus() *grpcstatus.Status {
+	return ormerrors.UniqueKeyViolation.GRPCStatus()
+}
+
+// ForeignKeyViolationError is returned when a write violates a ForeignKey
+// constraint. It wraps ormerrors.ForeignKeyViolation.
+type ForeignKeyViolationError struct {
+	// Constraint is the name of the violated foreign key.
+	Constraint string
+
+	// Values are the referenced key values.
+	Values []protoreflect.Value
+
+	// Reason describes the violation.
+	Reason string
+}
+
+func (e *ForeignKeyViolationError) Error() string {
+	return ormerrors.ForeignKeyViolation.Wrapf("%q: %s", e.Constraint, e.Reason).Error()
+}
+
+func (e *ForeignKeyViolationError) Unwrap() error {
+	return ormerrors.ForeignKeyViolation
+}
+
+func (e *ForeignKeyViolationError) Cause() error {
+	return ormerrors.ForeignKeyViolation
+}
+
+func (e *ForeignKeyViolationError) GRPCStatus() *grpcstatus.Status {
+	return ormerrors.ForeignKeyViolation.GRPCStatus()
+}
@@ -0,0 +1,63 @@
+package ormtable
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+var timestampFullName = (&timestamppb.Timestamp{}).ProtoReflect().Descriptor().FullName()
+
+// PurgeExpired deletes the entries of the table whose expiry field, as
+// configured with Options.ExpiryField, is before or equal to now, and returns
+// the number of deleted entries. At most limit entries are deleted, unless
+// limit is 0, so that the cost of a call is bounded; entries left behind are
+// deleted by the next calls. Secondary indexes and hooks are handled as for
+// regular deletes. It is typically called in BeginBlock.
+//
+// The entries are listed in expiry order using the index on the expiry field,
+// up to now, so that only expired entries are read. Entries where the expiry
+// field is unset sort last in the index and are never read.
+func PurgeExpired(ctx context.Context, table Table, now time.Time, limit uint64) (purged uint64, err error) {
+	purger, ok := table.(interface {
+		purgeExpired(ctx context.Context, now time.Time, limit uint64) (uint64, error)
+	})
+	if !ok {
+		return 0, ormerrors.UnsupportedOperation.Wrapf("can't purge expired entries of %T", table)
+	}
+
+	return purger.purgeExpired(ctx, now, limit)
+}
+
+func (t tableImpl) purgeExpired(ctx context.Context, now time.Time, limit uint64) (uint64, error) {
+	if t.expiryField == nil {
+		return 0, ormerrors.UnsupportedOperation.Wrapf("table %s has no expiry field", t.MessageType().Descriptor().FullName())
+	}
+
+	backend, err := t.getWriteBackend(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	it, err := t.expiryIndex.ListRange(ctx, []interface{}{}, []interface{}{timestamppb.New(now)})
+	if err != nil {
+		return 0, err
+	}
+
+	var entries []deleteEntry
+	for (limit == 0 || uint64(len(entries)) < limit) && it.Next() {
+		entry, err := t.readDeleteEntry(it)
+		if err != nil {
+			it.Close()
+			return 0, err
+		}
+
+		entries = append(entries, entry)
+	}
+	it.Close()
+
+	return t.deleteEntries(ctx, backend, entries)
+}
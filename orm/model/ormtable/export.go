@@ -0,0 +1,156 @@
+package ormtable
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// ExportTable writes all the entries of the table to writer in primary key
+// order, in the binary format read by ImportTable. Each entry is written as
+// its deterministic protobuf encoding prefixed by its length as a uvarint.
+// For auto-increment tables, the current sequence number is written first as
+// a uvarint, so that IDs aren't reused after an import, followed by the values
+// of the named sequences of the table as uvarints. The output only
+// depends on the table's entries, which makes it suitable for reproducible
+// genesis files.
+func ExportTable(ctx context.Context, table Table, writer io.Writer) error {
+	exporter, ok := table.(interface {
+		exportBinary(ctx context.Context, writer io.Writer) error
+	})
+	if !ok {
+		return ormerrors.UnsupportedOperation.Wrapf("can't export %T", table)
+	}
+
+	return exporter.exportBinary(ctx, writer)
+}
+
+// ImportTable imports entries written by ExportTable into the table. Entries
+// are inserted, so importing an entry which already exists fails. Like
+// ImportJSON, it is not atomic with respect to the underlying store and should
+// be called in the context of a transaction which can be rolled back.
+func ImportTable(ctx context.Context, table Table, reader io.Reader) error {
+	importer, ok := table.(interface {
+		importBinary(ctx context.Context, reader *bufio.Reader) error
+	})
+	if !ok {
+		return ormerrors.UnsupportedOperation.Wrapf("can't import %T", table)
+	}
+
+	return importer.importBinary(ctx, bufio.NewReader(reader))
+}
+
+func (t tableImpl) exportBinary(ctx context.Context, writer io.Writer) error {
+	marshalOptions := proto.MarshalOptions{Deterministic: true}
+
+	backend, err := t.getBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = t.exportSequencesBinary(backend, writer)
+	if err != nil {
+		return err
+	}
+
+	it, err := t.List(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for it.Next() {
+		msg, err := it.GetMessage()
+		if err != nil {
+			return err
+		}
+
+		bz, err := marshalOptions.Marshal(msg)
+		if err != nil {
+			return err
+		}
+
+		err = writeUvarint(writer, uint64(len(bz)))
+		if err != nil {
+			return err
+		}
+
+		_, err = writer.Write(bz)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t tableImpl) importBinary(ctx context.Context, reader *bufio.Reader) error {
+	backend, err := t.getWriteBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = t.importSequencesBinary(backend, reader)
+	if err == io.EOF {
+		// nothing was exported
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	ctx = withImporting(ctx)
+	return t.decodeBinary(reader, func(message proto.Message) error {
+		return t.save(ctx, backend, message, saveModeInsert)
+	})
+}
+
+// decodeBinary decodes the length prefixed messages written by exportBinary
+// until the end of reader. Messages are read through a reader limited to
+// their length prefix, so that the memory used is bounded by the size of the
+// input rather than by a possibly corrupted prefix.
+func (t tableImpl) decodeBinary(reader *bufio.Reader, onMsg func(message proto.Message) error) error {
+	unmarshalOptions := proto.UnmarshalOptions{Resolver: t.typeResolver}
+
+	for {
+		size, err := binary.ReadUvarint(reader)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		n, err := io.Copy(&buf, io.LimitReader(reader, int64(size)))
+		if err != nil {
+			return err
+		}
+		if size > math.MaxInt64 || uint64(n) != size {
+			return io.ErrUnexpectedEOF
+		}
+
+		msg := t.MessageType().New().Interface()
+		err = unmarshalOptions.Unmarshal(buf.Bytes(), msg)
+		if err != nil {
+			return err
+		}
+
+		err = onMsg(msg)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeUvarint(writer io.Writer, x uint64) error {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, x)
+	_, err := writer.Write(buf[:n])
+	return err
+}
@@ -1,6 +1,11 @@
 package ormtable
 
-import "google.golang.org/protobuf/proto"
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/types/kv"
+)
 
 type filterIterator struct {
 	Iterator
@@ -26,3 +31,73 @@ func (f *filterIterator) Next() bool {
 func (f filterIterator) GetMessage() (proto.Message, error) {
 	return f.msg, nil
 }
+
+// filteredIndexer wraps an indexer so that only the messages for which filter
+// returns true are indexed.
+type filteredIndexer struct {
+	indexer
+	filter func(proto.Message) bool
+}
+
+func (f filteredIndexer) onInsert(store kv.Store, message protoreflect.Message) error {
+	if !f.filter(message.Interface()) {
+		return nil
+	}
+
+	return f.indexer.onInsert(store, message)
+}
+
+func (f filteredIndexer) onUpdate(store kv.Store, new, existing protoreflect.Message) error {
+	newMatches, existingMatches := f.filter(new.Interface()), f.filter(existing.Interface())
+	switch {
+	case newMatches && existingMatches:
+		return f.indexer.onUpdate(store, new, existing)
+	case newMatches:
+		return f.indexer.onInsert(store, new)
+	case existingMatches:
+		return f.indexer.onDelete(store, existing)
+	default:
+		return nil
+	}
+}
+
+func (f filteredIndexer) onDelete(store kv.Store, message protoreflect.Message) error {
+	if !f.filter(message.Interface()) {
+		return nil
+	}
+
+	return f.indexer.onDelete(store, message)
+}
+
+func (f filteredIndexer) indexKeys(message protoreflect.Message) ([][]byte, error) {
+	if !f.filter(message.Interface()) {
+		return nil, nil
+	}
+
+	return f.indexer.indexKeys(message)
+}
+
+var _ indexer = filteredIndexer{}
+
+// skipUnsetFilter returns an index filter which only accepts the messages
+// where all the fields which aren't part of the primary key are set, and
+// which are accepted by filter if it isn't nil.
+func skipUnsetFilter(messageDescriptor protoreflect.MessageDescriptor, fields, pkFields []protoreflect.Name, filter func(proto.Message) bool) func(proto.Message) bool {
+	var checked []protoreflect.FieldDescriptor
+	for _, field := range fields {
+		if !isPrimaryKeyField(field, pkFields) {
+			checked = append(checked, messageDescriptor.Fields().ByName(field))
+		}
+	}
+
+	return func(message proto.Message) bool {
+		mref := message.ProtoReflect()
+		for _, field := range checked {
+			if !mref.Has(field) {
+				return false
+			}
+		}
+
+		return filter == nil || filter(message)
+	}
+}
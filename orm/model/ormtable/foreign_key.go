@@ -0,0 +1,226 @@
+package ormtable
+
+import (
+	"bytes"
+	"context"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/internal/fieldnames"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// ForeignKeyDeleteMode defines what happens to referencing entries when the
+// entry they reference is deleted.
+type ForeignKeyDeleteMode int
+
+const (
+	// RestrictDelete rejects deleting entries which are still referenced.
+	RestrictDelete ForeignKeyDeleteMode = iota
+
+	// CascadeDelete deletes the referencing entries together with the entry
+	// they reference.
+	CascadeDelete
+)
+
+// ForeignKey is a constraint ensuring that entries of a table only reference
+// existing entries of another table. It is enforced as ValidateHooks, which
+// must be registered on the backend, using MultiValidateHooks to combine
+// several constraints.
+//
+// References whose fields all have their default value are considered unset
+// and aren't checked. Updating the referenced key of an entry which is still
+// referenced is rejected in both delete modes.
+type ForeignKey struct {
+	name             string
+	table            Table
+	index            Index
+	fields           []protoreflect.FieldDescriptor
+	referenced       UniqueIndex
+	referencedFields []protoreflect.FieldDescriptor
+	onDelete         ForeignKeyDeleteMode
+}
+
+var _ ValidateHooks = &ForeignKey{}
+
+// NewForeignKey returns a ForeignKey with the provided name, for which the
+// values of the provided fields of table must match the key of an entry of the
+// referenced unique index. table must have an index on the fields, and their
+// types must match the types of the referenced index's fields.
+func NewForeignKey(name string, table Table, fields string, referenced UniqueIndex, onDelete ForeignKeyDeleteMode) (*ForeignKey, error) {
+	index := table.GetIndex(fields)
+	if index == nil {
+		return nil, ormerrors.CantFindIndex.Wrapf("foreign key %s needs an index on fields %s of %s",
+			name, fields, table.MessageType().Descriptor().FullName())
+	}
+
+	fkFields, err := getFieldDescriptors(table.MessageType(), fieldnames.CommaSeparatedFieldNames(fields))
+	if err != nil {
+		return nil, err
+	}
+
+	referencedFields, err := getFieldDescriptors(referenced.MessageType(), fieldnames.CommaSeparatedFieldNames(referenced.Fields()))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fkFields) != len(referencedFields) {
+		return nil, ormerrors.InvalidTableDefinition.Wrapf("foreign key %s has %d fields but references %d fields",
+			name, len(fkFields), len(referencedFields))
+	}
+
+	for i, field := range fkFields {
+		referencedField := referencedFields[i]
+		if field.Kind() != referencedField.Kind() ||
+			(field.Kind() == protoreflect.MessageKind && field.Message().FullName() != referencedField.Message().FullName()) {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("foreign key %s field %s doesn't have the type of %s",
+				name, field.FullName(), referencedField.FullName())
+		}
+	}
+
+	return &ForeignKey{
+		name:             name,
+		table:            table,
+		index:            index,
+		fields:           fkFields,
+		referenced:       referenced,
+		referencedFields: referencedFields,
+		onDelete:         onDelete,
+	}, nil
+}
+
+func getFieldDescriptors(messageType protoreflect.MessageType, fields fieldnames.FieldNames) ([]protoreflect.FieldDescriptor, error) {
+	var res []protoreflect.FieldDescriptor
+	for _, name := range fields.Names() {
+		field := messageType.Descriptor().Fields().ByName(name)
+		if field == nil {
+			return nil, ormerrors.FieldNotFound.Wrapf("field %s on %s", name, messageType.Descriptor().FullName())
+		}
+		res = append(res, field)
+	}
+	return res, nil
+}
+
+func (f *ForeignKey) ValidateInsert(ctx context.Context, message proto.Message) error {
+	if isMessageOf(message, f.table) {
+		return f.checkReferenced(ctx, message)
+	}
+	return nil
+}
+
+func (f *ForeignKey) ValidateUpdate(ctx context.Context, existing, new proto.Message) error {
+	if isMessageOf(new, f.referenced) {
+		existingValues := getValues(existing, f.referencedFields)
+		if !valuesEqual(existingValues, getValues(new, f.referencedFields)) {
+			err := f.checkNotReferenced(ctx, existingValues)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if isMessageOf(new, f.table) {
+		return f.checkReferenced(ctx, new)
+	}
+
+	return nil
+}
+
+func (f *ForeignKey) cascadesDeletes() bool {
+	return f.onDelete == CascadeDelete
+}
+
+func (f *ForeignKey) ValidateDelete(ctx context.Context, message proto.Message) error {
+	if !isMessageOf(message, f.referenced) {
+		return nil
+	}
+
+	values := getValues(message, f.referencedFields)
+	if f.onDelete == CascadeDelete {
+		return f.index.DeleteBy(ctx, valuesToInterfaces(values)...)
+	}
+
+	return f.checkNotReferenced(ctx, values)
+}
+
+// checkReferenced checks that the entry referenced by message exists.
+func (f *ForeignKey) checkReferenced(ctx context.Context, message proto.Message) error {
+	msgRef := message.ProtoReflect()
+	set := false
+	for _, field := range f.fields {
+		if msgRef.Has(field) {
+			set = true
+		}
+	}
+	if !set {
+		return nil
+	}
+
+	values := getValues(message, f.fields)
+	found, err := f.referenced.Has(ctx, valuesToInterfaces(values)...)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return &ForeignKeyViolationError{Constraint: f.name, Values: values, Reason: "referenced entry not found"}
+	}
+
+	return nil
+}
+
+// checkNotReferenced checks that no entry references the provided key values.
+func (f *ForeignKey) checkNotReferenced(ctx context.Context, values []protoreflect.Value) error {
+	found, err := f.index.HasPrefix(ctx, valuesToInterfaces(values)...)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		return &ForeignKeyViolationError{Constraint: f.name, Values: values, Reason: "entry is still referenced"}
+	}
+
+	return nil
+}
+
+func isMessageOf(message proto.Message, index Index) bool {
+	return message.ProtoReflect().Descriptor().FullName() == index.MessageType().Descriptor().FullName()
+}
+
+func getValues(message proto.Message, fields []protoreflect.FieldDescriptor) []protoreflect.Value {
+	msgRef := message.ProtoReflect()
+	values := make([]protoreflect.Value, len(fields))
+	for i, field := range fields {
+		values[i] = msgRef.Get(field)
+	}
+	return values
+}
+
+func valuesToInterfaces(values []protoreflect.Value) []interface{} {
+	res := make([]interface{}, len(values))
+	for i, value := range values {
+		res[i] = value.Interface()
+	}
+	return res
+}
+
+func valuesEqual(values1, values2 []protoreflect.Value) bool {
+	for i, v1 := range values1 {
+		switch x := v1.Interface().(type) {
+		case []byte:
+			if !bytes.Equal(x, values2[i].Bytes()) {
+				return false
+			}
+		case protoreflect.Message:
+			if !proto.Equal(x.Interface(), values2[i].Message().Interface()) {
+				return false
+			}
+		default:
+			if x != values2[i].Interface() {
+				return false
+			}
+		}
+	}
+	return true
+}
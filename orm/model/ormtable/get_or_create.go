@@ -0,0 +1,52 @@
+package ormtable
+
+import (
+	"bytes"
+	"context"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cosmos/cosmos-sdk/orm/encoding/encodeutil"
+	"github.com/cosmos/cosmos-sdk/orm/encoding/ormkv"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// getOrCreate implements UniqueIndex.GetOrCreate for index, whose keys are
+// encoded with keyCodec, using insert to insert the created message.
+func getOrCreate(ctx context.Context, index UniqueIndex, keyCodec *ormkv.KeyCodec, insert func(context.Context, proto.Message) error,
+	message proto.Message, create func() proto.Message, keyValues []interface{},
+) (created bool, err error) {
+	found, err := index.Get(ctx, message, keyValues...)
+	if err != nil || found {
+		return false, err
+	}
+
+	newMsg := create()
+	newRef := newMsg.ProtoReflect()
+	if newRef.Descriptor().FullName() != keyCodec.MessageType().Descriptor().FullName() {
+		return false, ormerrors.UnexpectedError.Wrapf("expected %s, got %s", keyCodec.MessageType().Descriptor().FullName(), newRef.Descriptor().FullName())
+	}
+
+	// the created message must be found by the same key values next time,
+	// keys are compared encoded so that normalization and hashing apply
+	key, err := keyCodec.EncodeKey(encodeutil.ValuesOf(keyValues...))
+	if err != nil {
+		return false, err
+	}
+	_, newKey, err := keyCodec.EncodeKeyFromMessage(newRef)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(key, newKey) {
+		return false, ormerrors.UnexpectedError.Wrapf("created %s doesn't have the key values %v", newRef.Descriptor().FullName(), keyValues)
+	}
+
+	err = insert(ctx, newMsg)
+	if err != nil {
+		return false, err
+	}
+
+	proto.Reset(message)
+	proto.Merge(message, newMsg)
+	return true, nil
+}
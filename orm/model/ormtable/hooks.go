@@ -23,12 +23,70 @@ type ValidateHooks interface {
 	ValidateDelete(context.Context, proto.Message) error
 }
 
+// cascadingValidateHooks is implemented by ValidateHooks whose ValidateDelete
+// may delete other entries, such as cascading foreign keys. Entries deleted by
+// iteration are buffered before being deleted when they are registered.
+type cascadingValidateHooks interface {
+	cascadesDeletes() bool
+}
+
+// MultiValidateHooks returns ValidateHooks which call each of the provided
+// hooks in order, stopping at the first error.
+func MultiValidateHooks(hooks ...ValidateHooks) ValidateHooks {
+	return multiValidateHooks(hooks)
+}
+
+type multiValidateHooks []ValidateHooks
+
+func (m multiValidateHooks) ValidateInsert(ctx context.Context, message proto.Message) error {
+	for _, hooks := range m {
+		if err := hooks.ValidateInsert(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multiValidateHooks) ValidateUpdate(ctx context.Context, existing, new proto.Message) error {
+	for _, hooks := range m {
+		if err := hooks.ValidateUpdate(ctx, existing, new); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multiValidateHooks) cascadesDeletes() bool {
+	for _, hooks := range m {
+		if cascading, ok := hooks.(cascadingValidateHooks); ok && cascading.cascadesDeletes() {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiValidateHooks) ValidateDelete(ctx context.Context, message proto.Message) error {
+	for _, hooks := range m {
+		if err := hooks.ValidateDelete(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // WriteHooks defines an interface for listening to insertions, updates and
 // deletes after they are written to the store. This can be used for indexing
 // state in another database. Indexers should make sure they coordinate with
 // transactions at live at the next level above the ORM as they write hooks
 // may be called but the enclosing transaction may still fail. The context
 // is provided in each method to help coordinate this.
+//
+// Write hooks are only called once the corresponding writes were applied to
+// the store. Panics in write hooks are recovered, so that they can't prevent
+// the remaining pending writes from being applied, and reported to the
+// BackendOptions.WriteHookPanicHandler of the backend, which can panic again
+// to abort the transaction, such as on out of gas panics.
+// Several WriteHooks can be combined with MultiWriteHooks.
 type WriteHooks interface {
 
 	// OnInsert is called after an message is inserted into the store.
@@ -40,3 +98,56 @@ type WriteHooks interface {
 	// OnDelete is called after the entity is deleted from the store.
 	OnDelete(context.Context, proto.Message)
 }
+
+// MultiWriteHooks returns WriteHooks which call each of the provided hooks
+// in order. When they are called by the ORM, a panic in one of them is
+// reported to the WriteHookPanicHandler of the backend, and doesn't prevent
+// the next ones from being called unless the handler panics.
+func MultiWriteHooks(hooks ...WriteHooks) WriteHooks {
+	return multiWriteHooks(hooks)
+}
+
+type multiWriteHooks []WriteHooks
+
+func (m multiWriteHooks) OnInsert(ctx context.Context, message proto.Message) {
+	for _, hooks := range m {
+		hooks.OnInsert(ctx, message)
+	}
+}
+
+func (m multiWriteHooks) OnUpdate(ctx context.Context, existing, new proto.Message) {
+	for _, hooks := range m {
+		hooks.OnUpdate(ctx, existing, new)
+	}
+}
+
+func (m multiWriteHooks) OnDelete(ctx context.Context, message proto.Message) {
+	for _, hooks := range m {
+		hooks.OnDelete(ctx, message)
+	}
+}
+
+// callWriteHooks calls the provided write hooks with call, or each of them if
+// they are multiWriteHooks, recovering from their panics and passing the
+// recovered values to onPanic. A panic of onPanic isn't recovered, which
+// prevents the remaining hooks from being called.
+func callWriteHooks(onPanic func(recovered interface{}), hooks WriteHooks, call func(WriteHooks)) {
+	if multi, ok := hooks.(multiWriteHooks); ok {
+		for _, h := range multi {
+			callWriteHooks(onPanic, h, call)
+		}
+		return
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			onPanic(recovered)
+		}
+	}()
+
+	call(hooks)
+}
+
+// ignoreWriteHookPanic is the default handler of the panics recovered from
+// write hooks, which ignores them.
+func ignoreWriteHookPanic(interface{}) {}
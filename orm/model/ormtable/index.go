@@ -10,6 +10,7 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/orm/encoding/ormkv"
 	"github.com/cosmos/cosmos-sdk/orm/model/ormlist"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
 )
 
 // Index defines an index on a table. Index instances
@@ -36,15 +37,43 @@ type Index interface {
 	// over an index with a bytes field, both start and end must have the same
 	// value for bytes.
 	//
-	// Range iteration is inclusive at both ends.
+	// Range iteration is inclusive at both ends, unless the
+	// ormlist.ExclusiveEnd option is provided.
 	ListRange(ctx context.Context, from, to []interface{}, options ...ormlist.Option) (Iterator, error)
 
-	// DeleteBy deletes any entries which match the provided prefix key.
+	// First retrieves the first message, in iteration order, which matches
+	// the provided prefix key, if there is one.
+	First(context context.Context, message proto.Message, prefixKey ...interface{}) (found bool, err error)
+
+	// Last retrieves the last message, in iteration order, which matches
+	// the provided prefix key, if there is one.
+	Last(context context.Context, message proto.Message, prefixKey ...interface{}) (found bool, err error)
+
+	// DeleteBy deletes any entries which match the provided prefix key. The
+	// entries of all the table's indexes for the deleted entries are removed
+	// as well. Use DeleteByPrefix to know how many entries were deleted.
 	DeleteBy(context context.Context, prefixKey ...interface{}) error
 
 	// DeleteRange deletes any entries between the provided range keys.
 	DeleteRange(context context.Context, from, to []interface{}) error
 
+	// Count returns the number of entries which match the provided prefix key.
+	// Entries are counted without being decoded, so this is cheaper than
+	// iterating with List.
+	Count(context context.Context, prefixKey ...interface{}) (uint64, error)
+
+	// EstimateCount returns the number of entries which match the provided
+	// prefix key like Count does, but stops counting after EstimateCountLimit
+	// entries so that it stays cheap for large tables. If exact is false the
+	// limit was reached, and count is only a lower bound of the number of
+	// entries.
+	EstimateCount(context context.Context, prefixKey ...interface{}) (count uint64, exact bool, err error)
+
+	// HasPrefix returns true if any entry matches the provided prefix key,
+	// which can contain fewer values than the index's fields. Entries are not
+	// decoded and iteration stops at the first matching key.
+	HasPrefix(context context.Context, prefixKey ...interface{}) (found bool, err error)
+
 	// MessageType returns the protobuf message type of the index.
 	MessageType() protoreflect.MessageType
 
@@ -60,6 +89,7 @@ type concreteIndex interface {
 	ormkv.IndexCodec
 
 	readValueFromIndexKey(context ReadBackend, primaryKey []protoreflect.Value, value []byte, message proto.Message) error
+	readProjectedValueFromIndexKey(context ReadBackend, primaryKey []protoreflect.Value, value []byte, message proto.Message, fields []protoreflect.FieldDescriptor) error
 }
 
 // UniqueIndex defines an unique index on a table.
@@ -71,10 +101,39 @@ type UniqueIndex interface {
 
 	// Get retrieves the message if one exists for the provided key values.
 	Get(context context.Context, message proto.Message, keyValues ...interface{}) (found bool, err error)
+
+	// GetOrCreate retrieves the message if one exists for the provided key
+	// values, as Get does. Otherwise, it inserts the message returned by
+	// create into the table, updating all its indexes as Table.Insert does,
+	// and sets it on message. The created message must have the provided key
+	// values. created is true if the message was inserted.
+	GetOrCreate(context context.Context, message proto.Message, create func() proto.Message, keyValues ...interface{}) (created bool, err error)
 }
 
 type indexer interface {
 	onInsert(store kv.Store, message protoreflect.Message) error
 	onUpdate(store kv.Store, new, existing protoreflect.Message) error
 	onDelete(store kv.Store, message protoreflect.Message) error
+
+	// indexKeys returns the keys which onInsert writes for the message.
+	indexKeys(message protoreflect.Message) ([][]byte, error)
+}
+
+// DeleteByPrefix deletes the entries of index which match the provided prefix
+// key, as Index.DeleteBy does, and returns the number of deleted entries,
+// counted while deleting them. The entries of all the table's indexes for the
+// deleted entries are removed as well.
+func DeleteByPrefix(ctx context.Context, index Index, prefixKey ...interface{}) (deleted uint64, err error) {
+	if table, ok := index.(Table); ok {
+		index = table.PrimaryKey()
+	}
+
+	deleter, ok := index.(interface {
+		deleteByPrefix(ctx context.Context, prefixKey []interface{}) (uint64, error)
+	})
+	if !ok {
+		return 0, ormerrors.UnsupportedOperation.Wrapf("can't delete entries of %T by prefix", index)
+	}
+
+	return deleter.deleteByPrefix(ctx, prefixKey)
 }
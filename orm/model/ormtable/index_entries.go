@@ -0,0 +1,40 @@
+package ormtable
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// IndexEntriesFor returns the encoded keys of all the secondary index entries
+// which the table writes when inserting message, following the current index
+// configuration of the table, including index filters. It doesn't read nor
+// write to any store, and can be used to diagnose missing or orphaned index
+// entries.
+func IndexEntriesFor(table Table, message proto.Message) ([][]byte, error) {
+	t, ok := table.(interface {
+		indexEntriesFor(message proto.Message) ([][]byte, error)
+	})
+	if !ok {
+		return nil, ormerrors.UnsupportedOperation.Wrapf("can't list index entries of %T", table)
+	}
+
+	return t.indexEntriesFor(message)
+}
+
+func (t tableImpl) indexEntriesFor(message proto.Message) ([][]byte, error) {
+	mref := message.ProtoReflect()
+	if mref.Descriptor().FullName() != t.MessageType().Descriptor().FullName() {
+		return nil, ormerrors.UnexpectedError.Wrapf("expected %s, got %s", t.MessageType().Descriptor().FullName(), mref.Descriptor().FullName())
+	}
+
+	var keys [][]byte
+	for _, idx := range t.indexers {
+		idxKeys, err := idx.indexKeys(mref)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, idxKeys...)
+	}
+	return keys, nil
+}
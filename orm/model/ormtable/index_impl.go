@@ -26,9 +26,14 @@ type indexKeyIndex struct {
 }
 
 func (i indexKeyIndex) DeleteBy(ctx context.Context, keyValues ...interface{}) error {
+	_, err := i.deleteByPrefix(ctx, keyValues)
+	return err
+}
+
+func (i indexKeyIndex) deleteByPrefix(ctx context.Context, keyValues []interface{}) (uint64, error) {
 	it, err := i.List(ctx, keyValues)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	return i.primaryKey.deleteByIterator(ctx, it)
@@ -40,7 +45,8 @@ func (i indexKeyIndex) DeleteRange(ctx context.Context, from, to []interface{})
 		return err
 	}
 
-	return i.primaryKey.deleteByIterator(ctx, it)
+	_, err = i.primaryKey.deleteByIterator(ctx, it)
+	return err
 }
 
 func (i indexKeyIndex) List(ctx context.Context, prefixKey []interface{}, options ...ormlist.Option) (Iterator, error) {
@@ -61,48 +67,122 @@ func (i indexKeyIndex) ListRange(ctx context.Context, from, to []interface{}, op
 	return rangeIterator(backend.IndexStoreReader(), backend, i, i.KeyCodec, from, to, options)
 }
 
+func (i indexKeyIndex) First(ctx context.Context, message proto.Message, prefixKey ...interface{}) (found bool, err error) {
+	return getFirst(ctx, i, message, prefixKey)
+}
+
+func (i indexKeyIndex) Last(ctx context.Context, message proto.Message, prefixKey ...interface{}) (found bool, err error) {
+	return getFirst(ctx, i, message, prefixKey, ormlist.Reverse())
+}
+
+func (i indexKeyIndex) Count(ctx context.Context, prefixKey ...interface{}) (uint64, error) {
+	backend, err := i.getReadBackend(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return countPrefix(backend.IndexStoreReader(), i.KeyCodec, prefixKey, 0)
+}
+
+func (i indexKeyIndex) EstimateCount(ctx context.Context, prefixKey ...interface{}) (count uint64, exact bool, err error) {
+	backend, err := i.getReadBackend(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return estimateCount(backend.IndexStoreReader(), i.KeyCodec, prefixKey)
+}
+
+func (i indexKeyIndex) HasPrefix(ctx context.Context, prefixKey ...interface{}) (found bool, err error) {
+	backend, err := i.getReadBackend(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return hasPrefix(backend.IndexStoreReader(), i.KeyCodec, prefixKey)
+}
+
 var _ indexer = &indexKeyIndex{}
 var _ Index = &indexKeyIndex{}
 
 func (i indexKeyIndex) doNotImplement() {}
 
+func (i indexKeyIndex) indexKeys(message protoreflect.Message) ([][]byte, error) {
+	return i.EncodeKeysFromMessage(message)
+}
+
 func (i indexKeyIndex) onInsert(store kv.Store, message protoreflect.Message) error {
-	k, v, err := i.EncodeKVFromMessage(message)
+	keys, err := i.EncodeKeysFromMessage(message)
 	if err != nil {
 		return err
 	}
-	return store.Set(k, v)
-}
 
-func (i indexKeyIndex) onUpdate(store kv.Store, new, existing protoreflect.Message) error {
-	newValues := i.GetKeyValues(new)
-	existingValues := i.GetKeyValues(existing)
-	if i.CompareKeys(newValues, existingValues) == 0 {
-		return nil
+	for _, k := range keys {
+		err = store.Set(k, []byte{})
+		if err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	existingKey, err := i.EncodeKey(existingValues)
+func (i indexKeyIndex) onUpdate(store kv.Store, new, existing protoreflect.Message) error {
+	newKeys, err := i.EncodeKeysFromMessage(new)
 	if err != nil {
 		return err
 	}
-	err = store.Delete(existingKey)
+
+	existingKeys, err := i.EncodeKeysFromMessage(existing)
 	if err != nil {
 		return err
 	}
 
-	newKey, err := i.EncodeKey(newValues)
-	if err != nil {
-		return err
+	// only write the entries which changed, for repeated fields this is
+	// the difference between the existing and new elements
+	keep := map[string]bool{}
+	for _, k := range newKeys {
+		keep[string(k)] = true
+	}
+
+	existingSet := map[string]bool{}
+	for _, k := range existingKeys {
+		existingSet[string(k)] = true
+		if keep[string(k)] {
+			continue
+		}
+
+		err = store.Delete(k)
+		if err != nil {
+			return err
+		}
 	}
-	return store.Set(newKey, []byte{})
+
+	for _, k := range newKeys {
+		if existingSet[string(k)] {
+			continue
+		}
+
+		err = store.Set(k, []byte{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (i indexKeyIndex) onDelete(store kv.Store, message protoreflect.Message) error {
-	_, key, err := i.EncodeKeyFromMessage(message)
+	keys, err := i.EncodeKeysFromMessage(message)
 	if err != nil {
 		return err
 	}
-	return store.Delete(key)
+
+	for _, k := range keys {
+		err = store.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (i indexKeyIndex) readValueFromIndexKey(backend ReadBackend, primaryKey []protoreflect.Value, _ []byte, message proto.Message) error {
@@ -118,6 +198,19 @@ func (i indexKeyIndex) readValueFromIndexKey(backend ReadBackend, primaryKey []p
 	return nil
 }
 
+func (i indexKeyIndex) readProjectedValueFromIndexKey(backend ReadBackend, primaryKey []protoreflect.Value, _ []byte, message proto.Message, fields []protoreflect.FieldDescriptor) error {
+	found, err := i.primaryKey.getProjected(backend, message, primaryKey, fields)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return ormerrors.UnexpectedError.Wrapf("can't find primary key")
+	}
+
+	return nil
+}
+
 func (p indexKeyIndex) Fields() string {
 	return p.fields.String()
 }
@@ -0,0 +1,77 @@
+package ormtable
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/types/kv"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+type importingContextKey struct{}
+
+// withImporting returns a context marking the saves made with it as imports.
+func withImporting(ctx context.Context) context.Context {
+	return context.WithValue(ctx, importingContextKey{}, true)
+}
+
+func isImporting(ctx context.Context) bool {
+	importing, _ := ctx.Value(importingContextKey{}).(bool)
+	return importing
+}
+
+// setInsertionPosition sets the next value of the insertion sequence on a
+// message being inserted. Imported messages keep their position, and the
+// sequence is advanced past it if needed, while other messages can't have a
+// position already set.
+func (t tableImpl) setInsertionPosition(ctx context.Context, store kv.Store, message protoreflect.Message) error {
+	bz, err := store.Get(t.insertionSeqCodec.Prefix())
+	if err != nil {
+		return err
+	}
+
+	seq, err := t.insertionSeqCodec.DecodeValue(bz)
+	if err != nil {
+		return err
+	}
+
+	pos := message.Get(t.insertionOrderField).Uint()
+	if pos != 0 && !isImporting(ctx) {
+		return ormerrors.InsertionPositionAlreadySet.Wrapf("%s is %d", t.insertionOrderField.FullName(), pos)
+	}
+
+	switch {
+	case pos == 0:
+		seq++
+		message.Set(t.insertionOrderField, protoreflect.ValueOfUint64(seq))
+	case pos > seq:
+		seq = pos
+	default:
+		return nil
+	}
+
+	return store.Set(t.insertionSeqCodec.Prefix(), t.insertionSeqCodec.EncodeValue(seq))
+}
+
+// insertionPositions returns the insertion positions of messages before they
+// are saved, so that restoreInsertionPositions can undo the positions set on
+// them if saving fails, which lets the caller retry with the same messages.
+func (t tableImpl) insertionPositions(messages ...proto.Message) []protoreflect.Value {
+	if t.insertionOrderField == nil {
+		return nil
+	}
+
+	positions := make([]protoreflect.Value, len(messages))
+	for i, message := range messages {
+		positions[i] = message.ProtoReflect().Get(t.insertionOrderField)
+	}
+	return positions
+}
+
+func (t tableImpl) restoreInsertionPositions(positions []protoreflect.Value, messages ...proto.Message) {
+	for i, pos := range positions {
+		messages[i].ProtoReflect().Set(t.insertionOrderField, pos)
+	}
+}
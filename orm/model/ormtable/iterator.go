@@ -1,6 +1,9 @@
 package ormtable
 
 import (
+	"bytes"
+	"context"
+
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/orm/internal/listinternal"
 	"github.com/cosmos/cosmos-sdk/orm/model/ormlist"
 	"github.com/cosmos/cosmos-sdk/orm/types/kv"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
 )
 
 // Iterator defines the interface for iterating over indexes.
@@ -57,20 +61,46 @@ func prefixIterator(iteratorStore kv.ReadonlyStore, backend ReadBackend, index c
 		return nil, err
 	}
 
-	var prefixBz []byte
-	prefixBz, err := codec.EncodeKey(encodeutil.ValuesOf(prefix...))
-	if err != nil {
+	if err := checkProjection(index, options.Projection); err != nil {
 		return nil, err
 	}
 
+	var prefixBz []byte
+	if options.BytesPrefix {
+		prefixKeys, err := codec.EncodeBytesPrefixKeys(encodeutil.ValuesOf(prefix...))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(prefixKeys) > 1 {
+			it, err := newMultiPrefixIterator(iteratorStore, prefixKeys, options.Cursor, options.Reverse)
+			if err != nil {
+				return nil, err
+			}
+
+			return applyCommonIteratorOptions(&indexIterator{
+				index:      index,
+				store:      backend,
+				iterator:   it,
+				started:    false,
+				projection: options.Projection,
+			}, options)
+		}
+
+		prefixBz = prefixKeys[0]
+	} else {
+		var err error
+		prefixBz, err = codec.EncodeKey(encodeutil.ValuesOf(prefix...))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var res Iterator
 	if !options.Reverse {
-		var start []byte
+		start := prefixBz
 		if len(options.Cursor) != 0 {
-			// must start right after cursor
-			start = append(options.Cursor, 0x0)
-		} else {
-			start = prefixBz
+			start = cursorStart(start, options.Cursor)
 		}
 		end := prefixEndBytes(prefixBz)
 		it, err := iteratorStore.Iterator(start, end)
@@ -78,18 +108,16 @@ func prefixIterator(iteratorStore kv.ReadonlyStore, backend ReadBackend, index c
 			return nil, err
 		}
 		res = &indexIterator{
-			index:    index,
-			store:    backend,
-			iterator: it,
-			started:  false,
+			index:      index,
+			store:      backend,
+			iterator:   it,
+			started:    false,
+			projection: options.Projection,
 		}
 	} else {
-		var end []byte
+		end := prefixEndBytes(prefixBz)
 		if len(options.Cursor) != 0 {
-			// end bytes is already exclusive by default
-			end = options.Cursor
-		} else {
-			end = prefixEndBytes(prefixBz)
+			end = cursorEnd(end, options.Cursor)
 		}
 		it, err := iteratorStore.ReverseIterator(prefixBz, end)
 		if err != nil {
@@ -97,10 +125,11 @@ func prefixIterator(iteratorStore kv.ReadonlyStore, backend ReadBackend, index c
 		}
 
 		res = &indexIterator{
-			index:    index,
-			store:    backend,
-			iterator: it,
-			started:  false,
+			index:      index,
+			store:      backend,
+			iterator:   it,
+			started:    false,
+			projection: options.Projection,
 		}
 	}
 
@@ -114,6 +143,14 @@ func rangeIterator(iteratorStore kv.ReadonlyStore, reader ReadBackend, index con
 		return nil, err
 	}
 
+	if options.BytesPrefix {
+		return nil, ormerrors.InvalidListOptions.Wrap("BytesPrefix doesn't apply to ListRange")
+	}
+
+	if err := checkProjection(index, options.Projection); err != nil {
+		return nil, err
+	}
+
 	startValues := encodeutil.ValuesOf(start...)
 	endValues := encodeutil.ValuesOf(end...)
 	err := codec.CheckValidRangeIterationKeys(startValues, endValues)
@@ -138,13 +175,15 @@ func rangeIterator(iteratorStore kv.ReadonlyStore, reader ReadBackend, index con
 	var res Iterator
 	if !options.Reverse {
 		if len(options.Cursor) != 0 {
-			startBz = append(options.Cursor, 0)
+			startBz = cursorStart(startBz, options.Cursor)
 		}
 
-		if fullEndKey {
-			endBz = inclusiveEndBytes(endBz)
-		} else {
-			endBz = prefixEndBytes(endBz)
+		if !options.ExclusiveEnd {
+			if fullEndKey {
+				endBz = inclusiveEndBytes(endBz)
+			} else {
+				endBz = prefixEndBytes(endBz)
+			}
 		}
 
 		it, err := iteratorStore.Iterator(startBz, endBz)
@@ -152,37 +191,143 @@ func rangeIterator(iteratorStore kv.ReadonlyStore, reader ReadBackend, index con
 			return nil, err
 		}
 		res = &indexIterator{
-			index:    index,
-			store:    reader,
-			iterator: it,
-			started:  false,
+			index:      index,
+			store:      reader,
+			iterator:   it,
+			started:    false,
+			projection: options.Projection,
 		}
 	} else {
-		if len(options.Cursor) != 0 {
-			endBz = options.Cursor
-		} else {
+		if !options.ExclusiveEnd {
 			if fullEndKey {
 				endBz = inclusiveEndBytes(endBz)
 			} else {
 				endBz = prefixEndBytes(endBz)
 			}
 		}
+
+		if len(options.Cursor) != 0 {
+			endBz = cursorEnd(endBz, options.Cursor)
+		}
 		it, err := iteratorStore.ReverseIterator(startBz, endBz)
 		if err != nil {
 			return nil, err
 		}
 
 		res = &indexIterator{
-			index:    index,
-			store:    reader,
-			iterator: it,
-			started:  false,
+			index:      index,
+			store:      reader,
+			iterator:   it,
+			started:    false,
+			projection: options.Projection,
 		}
 	}
 
 	return applyCommonIteratorOptions(res, options)
 }
 
+// cursorStart returns the start of a forward iteration from start which
+// resumes right after cursor, so that cursor is excluded but no key less than
+// start is included.
+func cursorStart(start []byte, cursor ormlist.CursorT) []byte {
+	// copy the cursor so that appending doesn't write to the caller's slice
+	afterCursor := inclusiveEndBytes(append([]byte{}, cursor...))
+	if bytes.Compare(afterCursor, start) < 0 {
+		return start
+	}
+	return afterCursor
+}
+
+// cursorEnd returns the exclusive end of a reverse iteration until end which
+// resumes right before cursor, so that cursor is excluded but no key greater
+// than or equal to end is included. A nil end is unbounded.
+func cursorEnd(end []byte, cursor ormlist.CursorT) []byte {
+	if end != nil && bytes.Compare(cursor, end) >= 0 {
+		return end
+	}
+	return cursor
+}
+
+func checkProjection(index concreteIndex, projection []protoreflect.FieldDescriptor) error {
+	messageName := index.MessageType().Descriptor().FullName()
+	for _, field := range projection {
+		if field.ContainingMessage().FullName() != messageName {
+			return ormerrors.InvalidListOptions.Wrapf("can't project field %s of %s", field.FullName(), messageName)
+		}
+	}
+	return nil
+}
+
+// getFirst retrieves the first message listed by the index with the provided
+// prefix key and options.
+func getFirst(ctx context.Context, index Index, message proto.Message, prefixKey []interface{}, options ...ormlist.Option) (found bool, err error) {
+	it, err := index.List(ctx, prefixKey, options...)
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+
+	if !it.Next() {
+		return false, nil
+	}
+
+	return true, it.UnmarshalMessage(message)
+}
+
+// countPrefix counts the keys with the provided prefix, counting at most limit
+// keys unless limit is 0.
+func countPrefix(store kv.ReadonlyStore, codec *ormkv.KeyCodec, prefix []interface{}, limit uint64) (uint64, error) {
+	prefixBz, err := codec.EncodeKey(encodeutil.ValuesOf(prefix...))
+	if err != nil {
+		return 0, err
+	}
+
+	it, err := store.Iterator(prefixBz, prefixEndBytes(prefixBz))
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+
+	var count uint64
+	for ; it.Valid() && (limit == 0 || count < limit); it.Next() {
+		count++
+	}
+
+	return count, nil
+}
+
+// EstimateCountLimit is the number of entries after which EstimateCount stops
+// counting.
+const EstimateCountLimit = 10000
+
+func estimateCount(store kv.ReadonlyStore, codec *ormkv.KeyCodec, prefix []interface{}) (count uint64, exact bool, err error) {
+	count, err = countPrefix(store, codec, prefix, EstimateCountLimit+1)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if count > EstimateCountLimit {
+		return EstimateCountLimit, false, nil
+	}
+
+	return count, true, nil
+}
+
+func hasPrefix(store kv.ReadonlyStore, codec *ormkv.KeyCodec, prefix []interface{}) (bool, error) {
+	prefixBz, err := codec.EncodeKey(encodeutil.ValuesOf(prefix...))
+	if err != nil {
+		return false, err
+	}
+
+	it, err := store.Iterator(prefixBz, prefixEndBytes(prefixBz))
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+
+	return it.Valid(), nil
+}
+
 func applyCommonIteratorOptions(iterator Iterator, options *listinternal.Options) (Iterator, error) {
 	if options.Filter != nil {
 		iterator = &filterIterator{Iterator: iterator, filter: options.Filter}
@@ -204,6 +349,7 @@ type indexIterator struct {
 	primaryKey  []protoreflect.Value
 	value       []byte
 	started     bool
+	projection  []protoreflect.FieldDescriptor
 }
 
 func (i *indexIterator) PageResponse() *queryv1beta1.PageResponse {
@@ -240,6 +386,9 @@ func (i indexIterator) UnmarshalMessage(message proto.Message) error {
 	if err != nil {
 		return err
 	}
+	if i.projection != nil {
+		return i.index.readProjectedValueFromIndexKey(i.store, pk, i.value, message, i.projection)
+	}
 	return i.index.readValueFromIndexKey(i.store, pk, i.value, message)
 }
 
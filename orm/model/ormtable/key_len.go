@@ -0,0 +1,53 @@
+package ormtable
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/internal/fieldnames"
+	"github.com/cosmos/cosmos-sdk/orm/types/kv"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// keyLenLimitedIndexer wraps an indexer so that writing index keys longer than
+// maxKeyLen fails.
+type keyLenLimitedIndexer struct {
+	indexer
+	fields    fieldnames.FieldNames
+	maxKeyLen int
+}
+
+func (l keyLenLimitedIndexer) onInsert(store kv.Store, message protoreflect.Message) error {
+	err := l.checkKeyLen(message)
+	if err != nil {
+		return err
+	}
+
+	return l.indexer.onInsert(store, message)
+}
+
+func (l keyLenLimitedIndexer) onUpdate(store kv.Store, new, existing protoreflect.Message) error {
+	err := l.checkKeyLen(new)
+	if err != nil {
+		return err
+	}
+
+	return l.indexer.onUpdate(store, new, existing)
+}
+
+func (l keyLenLimitedIndexer) checkKeyLen(message protoreflect.Message) error {
+	keys, err := l.indexer.indexKeys(message)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if len(k) > l.maxKeyLen {
+			return ormerrors.KeyTooLong.Wrapf("key of index with fields %s on %s is %d bytes, max %d",
+				l.fields, message.Descriptor().FullName(), len(k), l.maxKeyLen)
+		}
+	}
+
+	return nil
+}
+
+var _ indexer = keyLenLimitedIndexer{}
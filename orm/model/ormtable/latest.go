@@ -0,0 +1,53 @@
+package ormtable
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cosmos/cosmos-sdk/orm/encoding/ormkv"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// LatestForPrefix retrieves into message the entry of index which matches the
+// provided prefix key and has the greatest values for the following index
+// fields, for instance the latest version of a document in a table with the
+// primary key "doc_id,version" and the prefix key doc_id. It works for
+// ascending and descending indexes alike by iterating once in the direction
+// in which the greatest entry comes first, and found is false if no entry
+// matches the prefix key. Values are compared in key order, which is the
+// numeric order for integer fields.
+func LatestForPrefix(ctx context.Context, index Index, message proto.Message, prefixKey ...interface{}) (found bool, err error) {
+	keyCodec, err := indexKeyCodec(index)
+	if err != nil {
+		return false, err
+	}
+
+	if n := len(keyCodec.GetFieldNames()); len(prefixKey) >= n {
+		return false, ormerrors.IndexOutOfBounds.Wrapf("prefix key of %d values leaves no field of the %d fields of index %s", len(prefixKey), n, index.Fields())
+	}
+
+	if keyCodec.IsDescending() {
+		return index.First(ctx, message, prefixKey...)
+	}
+
+	return index.Last(ctx, message, prefixKey...)
+}
+
+// indexKeyCodec returns the codec of the keys of index.
+func indexKeyCodec(index Index) (*ormkv.KeyCodec, error) {
+	if table, ok := index.(Table); ok {
+		index = table.PrimaryKey()
+	}
+
+	switch index := index.(type) {
+	case *primaryKeyIndex:
+		return index.KeyCodec, nil
+	case *uniqueKeyIndex:
+		return index.GetKeyCodec(), nil
+	case *indexKeyIndex:
+		return index.KeyCodec, nil
+	default:
+		return nil, ormerrors.UnsupportedOperation.Wrapf("can't get the key codec of index %T", index)
+	}
+}
@@ -0,0 +1,79 @@
+package ormtable
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/model/ormlist"
+)
+
+// IndexEntry is an entry listed by an EntryIterator.
+type IndexEntry struct {
+	// IndexKey and PrimaryKey are the values of the index key and primary key
+	// of the entry.
+	IndexKey, PrimaryKey []protoreflect.Value
+
+	// Message is the primary message referenced by the entry.
+	Message proto.Message
+
+	// Err is the error which occurred while decoding the entry, if any, in
+	// which case the other fields may be unset.
+	Err error
+}
+
+// EntryIterator iterates over the entries of an index, resolving and
+// decoding the primary message referenced by each index entry as it goes.
+type EntryIterator struct {
+	it    Iterator
+	entry IndexEntry
+}
+
+// ListEntries lists the entries of index with the provided prefix key and
+// options, as Index.List does, yielding for each of them its index key
+// together with the decoded primary message. Errors decoding an entry don't
+// stop the iteration but are reported in IndexEntry.Err, so that a single
+// corrupted entry doesn't prevent reading the other ones.
+func ListEntries(ctx context.Context, index Index, prefixKey []interface{}, options ...ormlist.Option) (*EntryIterator, error) {
+	it, err := index.List(ctx, prefixKey, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EntryIterator{it: it}, nil
+}
+
+// Next advances the iterator and decodes the next entry, returning true if
+// there is one. Next must be called before starting iteration.
+func (e *EntryIterator) Next() bool {
+	if !e.it.Next() {
+		e.entry = IndexEntry{}
+		return false
+	}
+
+	e.entry = IndexEntry{}
+	e.entry.IndexKey, e.entry.PrimaryKey, e.entry.Err = e.it.Keys()
+	if e.entry.Err != nil {
+		return true
+	}
+
+	e.entry.Message, e.entry.Err = e.it.GetMessage()
+	return true
+}
+
+// Entry returns the entry the iterator currently points to.
+func (e *EntryIterator) Entry() IndexEntry {
+	return e.entry
+}
+
+// Cursor returns the cursor referencing the current iteration position, see
+// Iterator.Cursor.
+func (e *EntryIterator) Cursor() ormlist.CursorT {
+	return e.it.Cursor()
+}
+
+// Close closes the iterator and must always be called when done using it.
+func (e *EntryIterator) Close() {
+	e.it.Close()
+}
@@ -0,0 +1,356 @@
+package ormtable
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/encoding/encodeutil"
+	"github.com/cosmos/cosmos-sdk/orm/encoding/ormkv"
+	"github.com/cosmos/cosmos-sdk/orm/internal/fieldnames"
+	"github.com/cosmos/cosmos-sdk/orm/types/kv"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// MinMax returns the minimum and maximum of the integer field tracked by the
+// min/max aggregate of the table with the provided group fields, see
+// Options.MinMaxAggregates, over the entries whose group field values start
+// with prefixKey. found is false if there are no such entries. It reads a
+// single entry for each bound, so it doesn't depend on the number of entries.
+func MinMax(ctx context.Context, table Table, fields string, prefixKey ...interface{}) (min, max int64, found bool, err error) {
+	tracker, ok := table.(interface {
+		minMax(ctx context.Context, fields string, prefixKey []interface{}) (int64, int64, bool, error)
+	})
+	if !ok {
+		return 0, 0, false, ormerrors.UnsupportedOperation.Wrapf("can't get min and max of entries of %T", table)
+	}
+
+	return tracker.minMax(ctx, fields, prefixKey)
+}
+
+func (t tableImpl) minMax(ctx context.Context, fields string, prefixKey []interface{}) (int64, int64, bool, error) {
+	agg, ok := t.minMaxAggregates[fieldnames.CommaSeparatedFieldNames(fields)]
+	if !ok {
+		return 0, 0, false, ormerrors.CantFindIndex.Wrapf("no min/max aggregate with fields %s", fields)
+	}
+
+	backend, err := t.getBackend(ctx)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	return agg.get(backend.IndexStoreReader(), encodeutil.ValuesOf(prefixKey...))
+}
+
+// minMaxAggregate is an indexer tracking, for each prefix of the values of the
+// group fields of the entries, the minimum and maximum of the field over the
+// entries with these values. For each prefix, every entry is stored in the
+// index store under the key tablePrefix|minMaxId|groupFields|0, followed by
+// the number of group field values of the prefix, by their encoding, by the
+// field value and by the primary key of the entry, so that the entries of a
+// prefix are sorted by field value. The minimum and maximum are the first and
+// last keys under the prefix. Removing the current extreme of a prefix only
+// deletes its keys, and the next query finds the new extreme by seeking the
+// next key instead of rescanning the entries of the prefix.
+type minMaxAggregate struct {
+	tableName   protoreflect.FullName
+	prefix      []byte
+	groupFields int
+	// keyCodecs[i] encodes the first i group fields followed by the field.
+	keyCodecs []*ormkv.KeyCodec
+	pkCodec   *ormkv.KeyCodec
+	field     protoreflect.FieldDescriptor
+}
+
+func newMinMaxAggregate(tablePrefix []byte, messageType protoreflect.MessageType, groupFields fieldnames.FieldNames, field string, pkFields []protoreflect.Name) (*minMaxAggregate, error) {
+	messageDescriptor := messageType.Descriptor()
+	fieldDesc := messageDescriptor.Fields().ByName(protoreflect.Name(field))
+	if fieldDesc == nil {
+		return nil, ormerrors.FieldNotFound.Wrapf("min/max field %s on %s", field, messageDescriptor.FullName())
+	}
+
+	switch fieldDesc.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+	default:
+		return nil, ormerrors.InvalidTableDefinition.Wrapf("min/max field %s must be a signed integer or a uint32", fieldDesc.FullName())
+	}
+	if fieldDesc.IsList() {
+		return nil, ormerrors.InvalidTableDefinition.Wrapf("min/max field %s can't be repeated", fieldDesc.FullName())
+	}
+
+	names := groupFields.Names()
+	keyCodecs := make([]*ormkv.KeyCodec, 0, len(names)+1)
+	for i := 0; i <= len(names); i++ {
+		if i < len(names) && names[i] == fieldDesc.Name() {
+			return nil, ormerrors.InvalidTableDefinition.Wrapf("min/max field %s can't be a group field", fieldDesc.FullName())
+		}
+
+		fields := append(append([]protoreflect.Name(nil), names[:i]...), fieldDesc.Name())
+		keyCodec, err := ormkv.NewKeyCodec(nil, messageType, fields)
+		if err != nil {
+			return nil, err
+		}
+		keyCodecs = append(keyCodecs, keyCodec)
+	}
+
+	pkCodec, err := ormkv.NewKeyCodec(nil, messageType, pkFields)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := encodeutil.AppendVarUInt32(append([]byte(nil), tablePrefix...), minMaxId)
+	prefix = append(append(prefix, groupFields.String()...), 0)
+	return &minMaxAggregate{
+		tableName:   messageDescriptor.FullName(),
+		prefix:      prefix,
+		groupFields: len(names),
+		keyCodecs:   keyCodecs,
+		pkCodec:     pkCodec,
+		field:       fieldDesc,
+	}, nil
+}
+
+// encodeKey encodes the group field values, followed by the field value and
+// the primary key bytes if they are provided.
+func (m minMaxAggregate) encodeKey(values []protoreflect.Value, pk []byte) ([]byte, error) {
+	if len(values) > m.groupFields+1 {
+		return nil, ormerrors.IndexOutOfBounds.Wrapf("cannot encode %d values into %d group fields", len(values), m.groupFields)
+	}
+
+	n := len(values)
+	if pk != nil {
+		// the last value is the field value
+		n--
+	}
+
+	bz, err := m.keyCodecs[n].EncodeKey(values)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 0, len(m.prefix)+1+len(bz)+len(pk))
+	key = append(append(key, m.prefix...), byte(n))
+	return append(append(key, bz...), pk...), nil
+}
+
+func (m minMaxAggregate) value(v protoreflect.Value) int64 {
+	switch m.field.Kind() {
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return int64(v.Uint())
+	default:
+		return v.Int()
+	}
+}
+
+// get returns the minimum and maximum of the field over the entries whose
+// group field values start with values.
+func (m minMaxAggregate) get(store kv.ReadonlyStore, values []protoreflect.Value) (int64, int64, bool, error) {
+	if len(values) > m.groupFields {
+		return 0, 0, false, ormerrors.IndexOutOfBounds.Wrapf("cannot encode %d values into %d group fields", len(values), m.groupFields)
+	}
+
+	prefix, err := m.encodeKey(values, nil)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	minValue, found, err := m.first(store.Iterator, prefix, len(values))
+	if err != nil || !found {
+		return 0, 0, false, err
+	}
+
+	maxValue, _, err := m.first(store.ReverseIterator, prefix, len(values))
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	return minValue, maxValue, true, nil
+}
+
+// first decodes the field value of the first key under prefix returned by
+// the iterator.
+func (m minMaxAggregate) first(iterator func(start, end []byte) (kv.Iterator, error), prefix []byte, n int) (int64, bool, error) {
+	it, err := iterator(prefix, prefixEndBytes(prefix))
+	if err != nil {
+		return 0, false, err
+	}
+	defer it.Close()
+
+	if !it.Valid() {
+		return 0, false, nil
+	}
+
+	values, err := m.keyCodecs[n].DecodeKey(bytes.NewReader(it.Key()[len(m.prefix)+1:]))
+	if err != nil {
+		return 0, false, err
+	}
+
+	return m.value(values[n]), true, nil
+}
+
+// keys returns the keys of the message for each prefix of its group field
+// values.
+func (m minMaxAggregate) keys(message protoreflect.Message) ([][]byte, error) {
+	_, pk, err := m.pkCodec.EncodeKeyFromMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if pk == nil {
+		pk = []byte{}
+	}
+
+	values := m.keyCodecs[m.groupFields].GetKeyValues(message)
+	fieldValue := values[m.groupFields]
+	keys := make([][]byte, 0, m.groupFields+1)
+	for i := 0; i <= m.groupFields; i++ {
+		prefixValues := append(append([]protoreflect.Value(nil), values[:i]...), fieldValue)
+		key, err := m.encodeKey(prefixValues, pk)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (m minMaxAggregate) onInsert(store kv.Store, message protoreflect.Message) error {
+	keys, err := m.keys(message)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if err = store.Set(k, []byte{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m minMaxAggregate) onUpdate(store kv.Store, new, existing protoreflect.Message) error {
+	if err := m.onDelete(store, existing); err != nil {
+		return err
+	}
+
+	return m.onInsert(store, new)
+}
+
+func (m minMaxAggregate) onDelete(store kv.Store, message protoreflect.Message) error {
+	keys, err := m.keys(message)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if err = store.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexKeys returns no keys since min/max keys aren't index entries of the
+// message.
+func (m minMaxAggregate) indexKeys(protoreflect.Message) ([][]byte, error) {
+	return nil, nil
+}
+
+func (m minMaxAggregate) DecodeEntry(k, v []byte) (ormkv.Entry, error) {
+	r := bytes.NewReader(k)
+	err := encodeutil.SkipPrefix(r, m.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if int(n) > m.groupFields {
+		return nil, ormerrors.UnexpectedDecodePrefix.Wrapf("invalid min/max key %x", k)
+	}
+
+	values, err := m.keyCodecs[n].DecodeKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	pkValues, err := m.pkCodec.DecodeKey(r)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(pkValues) != len(m.pkCodec.GetFieldNames()) || r.Len() != 0 {
+		return nil, ormerrors.UnexpectedDecodePrefix.Wrapf("invalid min/max key %x", k)
+	}
+
+	return &ormkv.MinMaxEntry{
+		TableName:  m.tableName,
+		Fields:     m.keyCodecs[m.groupFields].GetFieldNames()[:m.groupFields],
+		Prefix:     values[:n],
+		Value:      values[n],
+		PrimaryKey: pkValues,
+	}, nil
+}
+
+func (m minMaxAggregate) EncodeEntry(entry ormkv.Entry) (k, v []byte, err error) {
+	minMaxEntry, ok := entry.(*ormkv.MinMaxEntry)
+	if !ok || minMaxEntry.TableName != m.tableName {
+		return nil, nil, ormerrors.BadDecodeEntry.Wrapf("%s", entry)
+	}
+
+	pk, err := m.pkCodec.EncodeKey(minMaxEntry.PrimaryKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pk == nil {
+		pk = []byte{}
+	}
+
+	values := append(append([]protoreflect.Value(nil), minMaxEntry.Prefix...), minMaxEntry.Value)
+	k, err = m.encodeKey(values, pk)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return k, []byte{}, nil
+}
+
+var _ indexer = minMaxAggregate{}
+var _ ormkv.EntryCodec = minMaxAggregate{}
+
+// minMaxAggregatesCodec decodes and encodes the entries tracked by the
+// min/max aggregates of a table, which share the minMaxId prefix.
+type minMaxAggregatesCodec map[fieldnames.FieldNames]*minMaxAggregate
+
+func (m minMaxAggregatesCodec) DecodeEntry(k, v []byte) (ormkv.Entry, error) {
+	for _, agg := range m {
+		if bytes.HasPrefix(k, agg.prefix) {
+			return agg.DecodeEntry(k, v)
+		}
+	}
+
+	return nil, ormerrors.UnexpectedDecodePrefix.Wrapf("can't find min/max aggregate with key %x", k)
+}
+
+func (m minMaxAggregatesCodec) EncodeEntry(entry ormkv.Entry) (k, v []byte, err error) {
+	minMaxEntry, ok := entry.(*ormkv.MinMaxEntry)
+	if !ok {
+		return nil, nil, ormerrors.BadDecodeEntry.Wrapf("%s", entry)
+	}
+
+	agg, ok := m[fieldnames.FieldsFromNames(minMaxEntry.Fields)]
+	if !ok {
+		return nil, nil, ormerrors.BadDecodeEntry.Wrapf("can't find min/max aggregate with fields %s", minMaxEntry.Fields)
+	}
+
+	return agg.EncodeEntry(entry)
+}
+
+var _ ormkv.EntryCodec = minMaxAggregatesCodec{}
@@ -1,9 +1,13 @@
 package ormtable
 
 import (
+	"context"
 	"math"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/cosmos/cosmos-sdk/orm/internal/listinternal"
+	"github.com/cosmos/cosmos-sdk/orm/model/ormlist"
 
 	queryv1beta1 "github.com/cosmos/cosmos-sdk/api/cosmos/base/query/v1beta1"
 )
@@ -22,6 +26,7 @@ func paginate(it Iterator, options *listinternal.Options) Iterator {
 				return &paginationIterator{
 					Iterator: it,
 					pageRes:  &queryv1beta1.PageResponse{Total: uint64(i)},
+					ended:    true,
 				}
 			}
 		}
@@ -49,10 +54,18 @@ type paginationIterator struct {
 	countTotal bool
 	i          int
 	done       int
+	// ended is true once Next returned false, in which case the underlying
+	// iterator mustn't be advanced anymore
+	ended bool
 }
 
 func (it *paginationIterator) Next() bool {
+	if it.ended {
+		return false
+	}
+
 	if it.i >= it.done {
+		it.ended = true
 		it.pageRes = &queryv1beta1.PageResponse{}
 		cursor := it.Cursor()
 		next := it.Iterator.Next()
@@ -86,6 +99,7 @@ func (it *paginationIterator) Next() bool {
 		it.i++
 		return true
 	} else {
+		it.ended = true
 		it.pageRes = &queryv1beta1.PageResponse{
 			Total: uint64(it.i),
 		}
@@ -96,3 +110,44 @@ func (it *paginationIterator) Next() bool {
 func (it paginationIterator) PageResponse() *queryv1beta1.PageResponse {
 	return it.pageRes
 }
+
+// ListPage iterates over at most limit entries of the index matching the
+// provided prefix key, calling onMessage for each of them, and returns a cursor
+// which can be passed to ormlist.Cursor to list the next page. If onMessage
+// returns true, iteration stops after the current entry. The returned cursor is
+// nil when there are no more entries. A limit of 0 means no limit.
+func ListPage(ctx context.Context, index Index, prefixKey []interface{}, limit int, onMessage func(proto.Message) (stop bool, err error), options ...ormlist.Option) (ormlist.CursorT, error) {
+	it, err := index.List(ctx, prefixKey, options...)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var cursor ormlist.CursorT
+	for i := 0; limit == 0 || i < limit; i++ {
+		if !it.Next() {
+			return nil, nil
+		}
+
+		msg, err := it.GetMessage()
+		if err != nil {
+			return nil, err
+		}
+
+		cursor = it.Cursor()
+		stop, err := onMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		if stop {
+			break
+		}
+	}
+
+	if !it.Next() {
+		return nil, nil
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,42 @@
+package ormtable
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cosmos/cosmos-sdk/orm/internal/fieldnames"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// PrefixValuesFromMessage returns the values of the fields of index set in
+// message, in the index's field order, stopping at the first field which isn't
+// set, so that the result can be used as the prefix key of List, Count or
+// DeleteBy on index, e.g.:
+//
+//	prefix, err := ormtable.PrefixValuesFromMessage(index, &Balance{Address: addr})
+//	it, err := index.List(ctx, prefix)
+//
+// Fields set after an unset field are ignored. Since proto3 scalar fields
+// are unset when they have their zero value, a field can't be part of the
+// prefix with its zero value, the prefix key must be built manually then.
+func PrefixValuesFromMessage(index Index, message proto.Message) ([]interface{}, error) {
+	msg := message.ProtoReflect()
+	messageDescriptor := index.MessageType().Descriptor()
+	if msg.Descriptor().FullName() != messageDescriptor.FullName() {
+		return nil, ormerrors.UnexpectedError.Wrapf("expected %s, got %s", messageDescriptor.FullName(), msg.Descriptor().FullName())
+	}
+
+	fields, err := getFieldDescriptors(index.MessageType(), fieldnames.CommaSeparatedFieldNames(index.Fields()))
+	if err != nil {
+		return nil, err
+	}
+
+	var values []interface{}
+	for _, field := range fields {
+		if !msg.Has(field) {
+			break
+		}
+		values = append(values, msg.Get(field).Interface())
+	}
+
+	return values, nil
+}
@@ -23,6 +23,10 @@ type primaryKeyIndex struct {
 	fields     fieldnames.FieldNames
 	indexers   []indexer
 	getBackend func(context.Context) (ReadBackend, error)
+
+	// insert inserts a message into the table, it is set once the table is
+	// built.
+	insert func(context.Context, proto.Message) error
 }
 
 func (p primaryKeyIndex) List(ctx context.Context, prefixKey []interface{}, options ...ormlist.Option) (Iterator, error) {
@@ -43,6 +47,32 @@ func (p primaryKeyIndex) ListRange(ctx context.Context, from, to []interface{},
 	return rangeIterator(backend.CommitmentStoreReader(), backend, p, p.KeyCodec, from, to, options)
 }
 
+func (p primaryKeyIndex) First(ctx context.Context, message proto.Message, prefixKey ...interface{}) (found bool, err error) {
+	return getFirst(ctx, p, message, prefixKey)
+}
+
+func (p primaryKeyIndex) Last(ctx context.Context, message proto.Message, prefixKey ...interface{}) (found bool, err error) {
+	return getFirst(ctx, p, message, prefixKey, ormlist.Reverse())
+}
+
+func (p primaryKeyIndex) Count(ctx context.Context, prefixKey ...interface{}) (uint64, error) {
+	backend, err := p.getBackend(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return countPrefix(backend.CommitmentStoreReader(), p.KeyCodec, prefixKey, 0)
+}
+
+func (p primaryKeyIndex) EstimateCount(ctx context.Context, prefixKey ...interface{}) (count uint64, exact bool, err error) {
+	backend, err := p.getBackend(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return estimateCount(backend.CommitmentStoreReader(), p.KeyCodec, prefixKey)
+}
+
 func (p primaryKeyIndex) doNotImplement() {}
 
 func (p primaryKeyIndex) Has(ctx context.Context, key ...interface{}) (found bool, err error) {
@@ -54,6 +84,15 @@ func (p primaryKeyIndex) Has(ctx context.Context, key ...interface{}) (found boo
 	return p.has(backend, encodeutil.ValuesOf(key...))
 }
 
+func (p primaryKeyIndex) HasPrefix(ctx context.Context, prefixKey ...interface{}) (found bool, err error) {
+	backend, err := p.getBackend(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return hasPrefix(backend.CommitmentStoreReader(), p.KeyCodec, prefixKey)
+}
+
 func (p primaryKeyIndex) has(backend ReadBackend, values []protoreflect.Value) (found bool, err error) {
 	keyBz, err := p.EncodeKey(values)
 	if err != nil {
@@ -72,6 +111,10 @@ func (p primaryKeyIndex) Get(ctx context.Context, message proto.Message, values
 	return p.get(backend, message, encodeutil.ValuesOf(values...))
 }
 
+func (p primaryKeyIndex) GetOrCreate(ctx context.Context, message proto.Message, create func() proto.Message, keyValues ...interface{}) (created bool, err error) {
+	return getOrCreate(ctx, p, p.KeyCodec, p.insert, message, create, keyValues)
+}
+
 func (p primaryKeyIndex) get(backend ReadBackend, message proto.Message, values []protoreflect.Value) (found bool, err error) {
 	key, err := p.EncodeKey(values)
 	if err != nil {
@@ -86,9 +129,14 @@ func (p primaryKeyIndex) DeleteBy(ctx context.Context, primaryKeyValues ...inter
 		return p.doDelete(ctx, encodeutil.ValuesOf(primaryKeyValues...))
 	}
 
+	_, err := p.deleteByPrefix(ctx, primaryKeyValues)
+	return err
+}
+
+func (p primaryKeyIndex) deleteByPrefix(ctx context.Context, primaryKeyValues []interface{}) (uint64, error) {
 	it, err := p.List(ctx, primaryKeyValues)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	return p.deleteByIterator(ctx, it)
@@ -100,7 +148,8 @@ func (p primaryKeyIndex) DeleteRange(ctx context.Context, from, to []interface{}
 		return err
 	}
 
-	return p.deleteByIterator(ctx, it)
+	_, err = p.deleteByIterator(ctx, it)
+	return err
 }
 
 func (p primaryKeyIndex) getWriteBackend(ctx context.Context) (Backend, error) {
@@ -174,8 +223,8 @@ func (p primaryKeyIndex) doDeleteWithWriteBatch(ctx context.Context, backend Bac
 	}
 
 	if writeHooks := backend.WriteHooks(); writeHooks != nil {
-		writer.enqueueHook(func() {
-			writeHooks.OnDelete(ctx, message)
+		writer.enqueueHook(writeHooks, func(hooks WriteHooks) {
+			hooks.OnDelete(ctx, message)
 		})
 	}
 
@@ -199,46 +248,124 @@ func (p primaryKeyIndex) readValueFromIndexKey(_ ReadBackend, primaryKey []proto
 	return p.Unmarshal(primaryKey, value, message)
 }
 
+func (p primaryKeyIndex) readProjectedValueFromIndexKey(_ ReadBackend, primaryKey []protoreflect.Value, value []byte, message proto.Message, fields []protoreflect.FieldDescriptor) error {
+	return p.UnmarshalFields(primaryKey, value, message, fields)
+}
+
+func (p primaryKeyIndex) getProjected(backend ReadBackend, message proto.Message, values []protoreflect.Value, fields []protoreflect.FieldDescriptor) (found bool, err error) {
+	key, err := p.EncodeKey(values)
+	if err != nil {
+		return false, err
+	}
+
+	bz, err := backend.CommitmentStoreReader().Get(key)
+	if err != nil {
+		return false, err
+	}
+
+	if bz == nil {
+		return false, nil
+	}
+
+	return true, p.UnmarshalFields(values, bz, message, fields)
+}
+
 func (p primaryKeyIndex) Fields() string {
 	return p.fields.String()
 }
 
-func (p primaryKeyIndex) deleteByIterator(ctx context.Context, it Iterator) error {
+// deleteByIterator deletes the entries of it and returns the number of
+// deleted entries.
+func (p primaryKeyIndex) deleteByIterator(ctx context.Context, it Iterator) (uint64, error) {
 	backend, err := p.getWriteBackend(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// we batch writes while the iterator is still open
-	writer := newBatchIndexCommitmentWriter(backend)
+	// cascading validate hooks delete entries themselves, so the entries to
+	// delete are read before deleting them to avoid writing to the store while
+	// the iterator is still open
+	if hooks, ok := backend.ValidateHooks().(cascadingValidateHooks); ok && hooks.cascadesDeletes() {
+		var entries []deleteEntry
+		for it.Next() {
+			entry, err := p.readDeleteEntry(it)
+			if err != nil {
+				return 0, err
+			}
+
+			entries = append(entries, entry)
+		}
+		it.Close()
+
+		return p.deleteEntries(ctx, backend, entries)
+	}
+
+	// otherwise we batch writes while the iterator is still open, reads need
+	// to see the pending writes of the previous deletes to update sum
+	// aggregates
+	writer := newReadYourWritesBatchIndexCommitmentWriter(backend)
 	defer writer.Close()
 
+	var deleted uint64
 	for it.Next() {
-		_, pk, err := it.Keys()
+		entry, err := p.readDeleteEntry(it)
 		if err != nil {
-			return err
+			return 0, err
 		}
 
-		msg, err := it.GetMessage()
+		err = p.doDeleteWithWriteBatch(ctx, backend, writer, entry.pkBz, entry.msg)
 		if err != nil {
-			return err
+			return 0, err
 		}
+		deleted++
+	}
 
-		pkBz, err := p.EncodeKey(pk)
-		if err != nil {
-			return err
-		}
+	// close iterator
+	it.Close()
+	// then write batch
+	return deleted, writer.Write()
+}
 
-		err = p.doDeleteWithWriteBatch(ctx, backend, writer, pkBz, msg)
+// deleteEntry is an entry to delete with its encoded primary key.
+type deleteEntry struct {
+	pkBz []byte
+	msg  proto.Message
+}
+
+func (p primaryKeyIndex) readDeleteEntry(it Iterator) (deleteEntry, error) {
+	_, pk, err := it.Keys()
+	if err != nil {
+		return deleteEntry{}, err
+	}
+
+	msg, err := it.GetMessage()
+	if err != nil {
+		return deleteEntry{}, err
+	}
+
+	pkBz, err := p.EncodeKey(pk)
+	if err != nil {
+		return deleteEntry{}, err
+	}
+
+	return deleteEntry{pkBz: pkBz, msg: msg}, nil
+}
+
+func (p primaryKeyIndex) deleteEntries(ctx context.Context, backend Backend, entries []deleteEntry) (deleted uint64, err error) {
+	// reads need to see the pending writes of the previous deletes to update
+	// sum aggregates
+	writer := newReadYourWritesBatchIndexCommitmentWriter(backend)
+	defer writer.Close()
+
+	for _, e := range entries {
+		err = p.doDeleteWithWriteBatch(ctx, backend, writer, e.pkBz, e.msg)
 		if err != nil {
-			return err
+			return 0, err
 		}
+		deleted++
 	}
 
-	// close iterator
-	it.Close()
-	// then write batch
-	return writer.Write()
+	return deleted, writer.Write()
 }
 
 var _ UniqueIndex = &primaryKeyIndex{}
@@ -0,0 +1,114 @@
+package ormtable
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/orm/internal/fieldnames"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// RebuildIndex deletes all the entries of the secondary index of the table
+// with the provided fields and recreates them from the table's entries. It can
+// be used to recover an index which got out of sync with the table, and is
+// safe to run several times.
+func RebuildIndex(ctx context.Context, table Table, fields string) error {
+	rebuilder, ok := table.(interface {
+		rebuildIndex(ctx context.Context, fields string) error
+	})
+	if !ok {
+		return ormerrors.UnsupportedOperation.Wrapf("can't rebuild indexes of %T", table)
+	}
+
+	return rebuilder.rebuildIndex(ctx, fields)
+}
+
+func (t tableImpl) rebuildIndex(ctx context.Context, fields string) error {
+	backend, err := t.getWriteBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	index, idxIndexer, err := t.secondaryIndexer(fields)
+	if err != nil {
+		return err
+	}
+
+	prefix, err := indexPrefix(index)
+	if err != nil {
+		return err
+	}
+
+	// reads need to see the pending deletes and inserts to check unique
+	// constraints between the recreated entries
+	writer := newReadYourWritesBatchIndexCommitmentWriter(backend)
+	defer writer.Close()
+
+	// delete the existing entries
+	it, err := backend.IndexStoreReader().Iterator(prefix, prefixEndBytes(prefix))
+	if err != nil {
+		return err
+	}
+	for ; it.Valid(); it.Next() {
+		err = writer.IndexStore().Delete(it.Key())
+		if err != nil {
+			return err
+		}
+	}
+	err = it.Close()
+	if err != nil {
+		return err
+	}
+
+	// recreate them from the table's entries
+	tableIt, err := t.List(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for tableIt.Next() {
+		msg, err := tableIt.GetMessage()
+		if err != nil {
+			return err
+		}
+
+		err = idxIndexer.onInsert(writer.IndexStore(), msg.ProtoReflect())
+		if err != nil {
+			return err
+		}
+	}
+	tableIt.Close()
+
+	return writer.Write()
+}
+
+// secondaryIndexer returns the secondary index with the provided fields and
+// its indexer, including the wrappers applying the index options.
+func (t tableImpl) secondaryIndexer(fields string) (concreteIndex, indexer, error) {
+	index, ok := t.indexesByFields[fieldnames.CommaSeparatedFieldNames(fields)]
+	if !ok {
+		return nil, nil, ormerrors.CantFindIndex.Wrapf("no secondary index with fields %s", fields)
+	}
+
+	for _, ixr := range t.indexers {
+		if interface{}(unwrapIndexer(ixr)) == interface{}(index) {
+			return index, ixr, nil
+		}
+	}
+
+	// the primary key isn't stored in the index store
+	return nil, nil, ormerrors.CantFindIndex.Wrapf("no secondary index with fields %s", fields)
+}
+
+// unwrapIndexer returns the indexer wrapped by filteredIndexer and
+// keyLenLimitedIndexer.
+func unwrapIndexer(ixr indexer) indexer {
+	for {
+		switch wrapper := ixr.(type) {
+		case filteredIndexer:
+			ixr = wrapper.indexer
+		case keyLenLimitedIndexer:
+			ixr = wrapper.indexer
+		default:
+			return ixr
+		}
+	}
+}
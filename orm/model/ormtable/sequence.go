@@ -0,0 +1,265 @@
+package ormtable
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/encoding/encodeutil"
+	"github.com/cosmos/cosmos-sdk/orm/encoding/ormkv"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// Sequence is a named uint64 sequence of a table, see Options.Sequences,
+// which can be used to generate identifiers independently of the primary key
+// of the table. It is stored with the table, so that it is covered by the
+// transaction of the enclosing store and by JSON and binary exports.
+type Sequence interface {
+	// NextVal increments the sequence and returns its new value. The first
+	// value of a sequence is 1.
+	NextVal(ctx context.Context) (uint64, error)
+
+	// PeekNextVal returns the value the next call to NextVal will return,
+	// without incrementing the sequence.
+	PeekNextVal(ctx context.Context) (uint64, error)
+}
+
+// GetSequence returns the sequence of the table with the provided name, or
+// nil if the table has no such sequence.
+func GetSequence(table Table, name string) Sequence {
+	getter, ok := table.(interface {
+		getSequence(name string) *sequence
+	})
+	if !ok {
+		return nil
+	}
+
+	// avoid returning a non-nil interface holding a nil pointer
+	seq := getter.getSequence(name)
+	if seq == nil {
+		return nil
+	}
+	return seq
+}
+
+func (t tableImpl) getSequence(name string) *sequence {
+	for _, seq := range t.sequences {
+		if seq.name == name {
+			return seq
+		}
+	}
+	return nil
+}
+
+// sequence is a named sequence stored in the index store under the key
+// tablePrefix|sequencesId|name|0.
+type sequence struct {
+	name       string
+	codec      *ormkv.SeqCodec
+	primaryKey *primaryKeyIndex
+}
+
+func newSequence(tablePrefix []byte, messageType protoreflect.MessageType, name string, primaryKey *primaryKeyIndex) (*sequence, error) {
+	if name == "" || strings.IndexByte(name, 0) >= 0 {
+		return nil, ormerrors.InvalidTableDefinition.Wrapf("invalid sequence name %q", name)
+	}
+
+	prefix := encodeutil.AppendVarUInt32(append([]byte(nil), tablePrefix...), sequencesId)
+	prefix = append(append(prefix, name...), 0)
+	return &sequence{
+		name:       name,
+		codec:      ormkv.NewSeqCodec(messageType, prefix),
+		primaryKey: primaryKey,
+	}, nil
+}
+
+func (s *sequence) NextVal(ctx context.Context) (uint64, error) {
+	backend, err := s.primaryKey.getWriteBackend(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	seq, err := s.get(backend)
+	if err != nil {
+		return 0, err
+	}
+
+	seq++
+	return seq, s.set(backend, seq)
+}
+
+func (s *sequence) PeekNextVal(ctx context.Context) (uint64, error) {
+	backend, err := s.primaryKey.getBackend(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	seq, err := s.get(backend)
+	if err != nil {
+		return 0, err
+	}
+
+	return seq + 1, nil
+}
+
+func (s *sequence) get(backend ReadBackend) (uint64, error) {
+	bz, err := backend.IndexStoreReader().Get(s.codec.Prefix())
+	if err != nil {
+		return 0, err
+	}
+
+	return s.codec.DecodeValue(bz)
+}
+
+func (s *sequence) set(backend Backend, seq uint64) error {
+	writer := newBatchIndexCommitmentWriter(backend)
+	defer writer.Close()
+
+	err := writer.IndexStore().Set(s.codec.Prefix(), s.codec.EncodeValue(seq))
+	if err != nil {
+		return err
+	}
+
+	return writer.Write()
+}
+
+// sequencesCodec decodes the entries of the named sequences of a table.
+type sequencesCodec []*sequence
+
+func (s sequencesCodec) DecodeEntry(k, v []byte) (ormkv.Entry, error) {
+	for _, seq := range s {
+		if bytes.Equal(k, seq.codec.Prefix()) {
+			return seq.codec.DecodeEntry(k, v)
+		}
+	}
+
+	return nil, ormerrors.UnexpectedDecodePrefix.Wrapf("can't find sequence with key %x", k)
+}
+
+// EncodeEntry can't encode sequence entries since they don't contain the
+// name of the sequence.
+func (s sequencesCodec) EncodeEntry(ormkv.Entry) (k, v []byte, err error) {
+	return nil, nil, ormerrors.BadDecodeEntry.Wrap("can't encode named sequence entries")
+}
+
+var _ ormkv.EntryCodec = sequencesCodec{}
+
+// sequencesJSONKey is the key of the JSON object holding the values of the
+// named sequences in JSON exports. Messages can't have fields with this name,
+// so the object can't be mistaken for a message.
+const sequencesJSONKey = "$sequences"
+
+// exportSequencesJSON writes the values of the named sequences of the table
+// as a JSON object, if it has any, preceded by a comma unless start is true.
+// It returns whether the next element is still the first one.
+func (t tableImpl) exportSequencesJSON(backend ReadBackend, writer io.Writer, start bool) (bool, error) {
+	if len(t.sequences) == 0 {
+		return start, nil
+	}
+
+	values := map[string]uint64{}
+	for _, seq := range t.sequences {
+		value, err := seq.get(backend)
+		if err != nil {
+			return false, err
+		}
+		values[seq.name] = value
+	}
+
+	// json.Marshal sorts map keys, so the output is deterministic
+	bz, err := json.Marshal(map[string]map[string]uint64{sequencesJSONKey: values})
+	if err != nil {
+		return false, err
+	}
+
+	if !start {
+		_, err = writer.Write([]byte(",\n"))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	_, err = writer.Write(bz)
+	return false, err
+}
+
+// importSequencesJSON decodes the values of the named sequences from the
+// provided JSON element and sets them if backend isn't nil. It returns false
+// if the element isn't a named sequences object.
+func (t tableImpl) importSequencesJSON(backend Backend, message json.RawMessage) (bool, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(message, &obj); err != nil || len(obj) != 1 {
+		return false, nil
+	}
+
+	raw, ok := obj[sequencesJSONKey]
+	if !ok {
+		return false, nil
+	}
+
+	var values map[string]uint64
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return true, ormerrors.JSONImportError.Wrapf("invalid sequences: %s", err)
+	}
+
+	for name, value := range values {
+		seq := t.getSequence(name)
+		if seq == nil {
+			return true, ormerrors.JSONImportError.Wrapf("unknown sequence %s", name)
+		}
+
+		// backend is nil during validation
+		if backend != nil {
+			if err := seq.set(backend, value); err != nil {
+				return true, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// exportSequencesBinary writes the values of the named sequences of the table
+// as uvarints, in the order of their definition.
+func (t tableImpl) exportSequencesBinary(backend ReadBackend, writer io.Writer) error {
+	for _, seq := range t.sequences {
+		value, err := seq.get(backend)
+		if err != nil {
+			return err
+		}
+
+		err = writeUvarint(writer, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importSequencesBinary reads and sets the values of the named sequences of
+// the table written by exportSequencesBinary. It returns io.EOF if nothing
+// was exported.
+func (t tableImpl) importSequencesBinary(backend Backend, reader *bufio.Reader) error {
+	for i, seq := range t.sequences {
+		value, err := binary.ReadUvarint(reader)
+		if err == io.EOF && i != 0 {
+			return io.ErrUnexpectedEOF
+		} else if err != nil {
+			return err
+		}
+
+		err = seq.set(backend, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package ormtable
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/orm/model/ormlist"
+)
+
+// Stream lists the entries of index with the provided prefix key and options,
+// as ListEntries does, in a goroutine sending each entry on the returned
+// entries channel. The channel is unbuffered, so that iteration only advances
+// as fast as the entries are received.
+//
+// Iteration stops at the first error opening the iterator or decoding an
+// entry, or when ctx is cancelled, in which case the error, or ctx.Err(), is
+// sent on the returned errors channel. Both channels are closed once
+// iteration stops and the iterator is closed, so callers should receive from
+// the entries channel until it is closed and then check the errors channel.
+// A caller which stops receiving entries must cancel ctx so that the goroutine
+// exits.
+//
+// The same restrictions as for iterators apply: the table generally shouldn't
+// be mutated while streaming.
+func Stream(ctx context.Context, index Index, prefixKey []interface{}, options ...ormlist.Option) (<-chan IndexEntry, <-chan error) {
+	entries := make(chan IndexEntry)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(entries)
+
+		it, err := ListEntries(ctx, index, prefixKey, options...)
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer it.Close()
+
+		for it.Next() {
+			entry := it.Entry()
+			if entry.Err != nil {
+				errs <- entry.Err
+				return
+			}
+
+			// checked first since select picks randomly between ready cases
+			if err := ctx.Err(); err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case entries <- entry:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return entries, errs
+}
@@ -0,0 +1,306 @@
+package ormtable
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/encoding/encodeutil"
+	"github.com/cosmos/cosmos-sdk/orm/encoding/ormkv"
+	"github.com/cosmos/cosmos-sdk/orm/internal/fieldnames"
+	"github.com/cosmos/cosmos-sdk/orm/types/kv"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// Sum returns the sum of the integer field aggregated by the sum aggregate of
+// the table with the provided group fields, see Options.SumAggregates, over
+// the entries whose group field values start with prefixKey. It only reads
+// the stored sum, so it doesn't depend on the number of entries.
+func Sum(ctx context.Context, table Table, fields string, prefixKey ...interface{}) (int64, error) {
+	summer, ok := table.(interface {
+		sum(ctx context.Context, fields string, prefixKey []interface{}) (int64, error)
+	})
+	if !ok {
+		return 0, ormerrors.UnsupportedOperation.Wrapf("can't sum entries of %T", table)
+	}
+
+	return summer.sum(ctx, fields, prefixKey)
+}
+
+func (t tableImpl) sum(ctx context.Context, fields string, prefixKey []interface{}) (int64, error) {
+	agg, ok := t.sumAggregates[fieldnames.CommaSeparatedFieldNames(fields)]
+	if !ok {
+		return 0, ormerrors.CantFindIndex.Wrapf("no sum aggregate with fields %s", fields)
+	}
+
+	backend, err := t.getBackend(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := agg.encodeKey(encodeutil.ValuesOf(prefixKey...))
+	if err != nil {
+		return 0, err
+	}
+
+	return agg.get(backend.IndexStoreReader(), key)
+}
+
+// sumAggregate is an indexer maintaining, for each prefix of the values of
+// the group fields of the entries, the sum of the field over the entries with
+// these values. Sums are stored in the index store under the prefix
+// tablePrefix|sumsId|groupFields|0, followed by the number of group field
+// values of the prefix and by their encoding, so that prefixes of different
+// lengths don't collide. Zero sums aren't stored.
+type sumAggregate struct {
+	tableName protoreflect.FullName
+	prefix    []byte
+	keyCodec  *ormkv.KeyCodec
+	field     protoreflect.FieldDescriptor
+}
+
+func newSumAggregate(tablePrefix []byte, messageType protoreflect.MessageType, groupFields fieldnames.FieldNames, field string) (*sumAggregate, error) {
+	messageDescriptor := messageType.Descriptor()
+	fieldDesc := messageDescriptor.Fields().ByName(protoreflect.Name(field))
+	if fieldDesc == nil {
+		return nil, ormerrors.FieldNotFound.Wrapf("sum field %s on %s", field, messageDescriptor.FullName())
+	}
+
+	switch fieldDesc.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+	default:
+		return nil, ormerrors.InvalidTableDefinition.Wrapf("sum field %s must be a signed integer or a uint32", fieldDesc.FullName())
+	}
+	if fieldDesc.IsList() {
+		return nil, ormerrors.InvalidTableDefinition.Wrapf("sum field %s can't be repeated", fieldDesc.FullName())
+	}
+
+	keyCodec, err := ormkv.NewKeyCodec(nil, messageType, groupFields.Names())
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := encodeutil.AppendVarUInt32(append([]byte(nil), tablePrefix...), sumsId)
+	prefix = append(append(prefix, groupFields.String()...), 0)
+	return &sumAggregate{
+		tableName: messageDescriptor.FullName(),
+		prefix:    prefix,
+		keyCodec:  keyCodec,
+		field:     fieldDesc,
+	}, nil
+}
+
+func (s sumAggregate) encodeKey(values []protoreflect.Value) ([]byte, error) {
+	bz, err := s.keyCodec.EncodeKey(values)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 0, len(s.prefix)+1+len(bz))
+	key = append(append(key, s.prefix...), byte(len(values)))
+	return append(key, bz...), nil
+}
+
+func (s sumAggregate) value(message protoreflect.Message) int64 {
+	switch s.field.Kind() {
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return int64(message.Get(s.field).Uint())
+	default:
+		return message.Get(s.field).Int()
+	}
+}
+
+func (s sumAggregate) get(store kv.ReadonlyStore, key []byte) (int64, error) {
+	bz, err := store.Get(key)
+	if err != nil || bz == nil {
+		return 0, err
+	}
+
+	return decodeSum(bz)
+}
+
+func encodeSum(sum int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(sum))
+	return bz
+}
+
+func decodeSum(bz []byte) (int64, error) {
+	if len(bz) != 8 {
+		return 0, ormerrors.UnexpectedError.Wrapf("invalid sum value %X", bz)
+	}
+
+	return int64(binary.BigEndian.Uint64(bz)), nil
+}
+
+// addDeltas adds to deltas the value of the message for each prefix of its
+// group field values, multiplied by sign.
+func (s sumAggregate) addDeltas(deltas map[string]int64, message protoreflect.Message, sign int64) error {
+	v := s.value(message) * sign
+	if v == 0 {
+		return nil
+	}
+
+	values := s.keyCodec.GetKeyValues(message)
+	for i := 0; i <= len(values); i++ {
+		key, err := s.encodeKey(values[:i])
+		if err != nil {
+			return err
+		}
+		deltas[string(key)] += v
+	}
+
+	return nil
+}
+
+// applyDeltas applies deltas to the stored sums. Deltas are accumulated
+// first since batch writers don't necessarily see their pending writes.
+func (s sumAggregate) applyDeltas(store kv.Store, deltas map[string]int64) error {
+	for key, delta := range deltas {
+		if delta == 0 {
+			continue
+		}
+
+		cur, err := s.get(store, []byte(key))
+		if err != nil {
+			return err
+		}
+
+		if (delta > 0 && cur > math.MaxInt64-delta) || (delta < 0 && cur < math.MinInt64-delta) {
+			return ormerrors.ConstraintViolation.Wrapf("sum of %s overflows", s.field.FullName())
+		}
+
+		sum := cur + delta
+		if sum == 0 {
+			err = store.Delete([]byte(key))
+		} else {
+			err = store.Set([]byte(key), encodeSum(sum))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s sumAggregate) onInsert(store kv.Store, message protoreflect.Message) error {
+	deltas := map[string]int64{}
+	if err := s.addDeltas(deltas, message, 1); err != nil {
+		return err
+	}
+
+	return s.applyDeltas(store, deltas)
+}
+
+func (s sumAggregate) onUpdate(store kv.Store, new, existing protoreflect.Message) error {
+	deltas := map[string]int64{}
+	if err := s.addDeltas(deltas, existing, -1); err != nil {
+		return err
+	}
+	if err := s.addDeltas(deltas, new, 1); err != nil {
+		return err
+	}
+
+	return s.applyDeltas(store, deltas)
+}
+
+func (s sumAggregate) onDelete(store kv.Store, message protoreflect.Message) error {
+	deltas := map[string]int64{}
+	if err := s.addDeltas(deltas, message, -1); err != nil {
+		return err
+	}
+
+	return s.applyDeltas(store, deltas)
+}
+
+// indexKeys returns no keys since sums aren't index entries of the message.
+func (s sumAggregate) indexKeys(protoreflect.Message) ([][]byte, error) {
+	return nil, nil
+}
+
+func (s sumAggregate) DecodeEntry(k, v []byte) (ormkv.Entry, error) {
+	r := bytes.NewReader(k)
+	err := encodeutil.SkipPrefix(r, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := s.keyCodec.DecodeKey(r)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(values) != int(n) || r.Len() != 0 {
+		return nil, ormerrors.UnexpectedDecodePrefix.Wrapf("invalid sum key %x", k)
+	}
+
+	sum, err := decodeSum(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ormkv.SumEntry{
+		TableName: s.tableName,
+		Fields:    s.keyCodec.GetFieldNames(),
+		Prefix:    values,
+		Sum:       sum,
+	}, nil
+}
+
+func (s sumAggregate) EncodeEntry(entry ormkv.Entry) (k, v []byte, err error) {
+	sumEntry, ok := entry.(*ormkv.SumEntry)
+	if !ok || sumEntry.TableName != s.tableName {
+		return nil, nil, ormerrors.BadDecodeEntry.Wrapf("%s", entry)
+	}
+
+	k, err = s.encodeKey(sumEntry.Prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return k, encodeSum(sumEntry.Sum), nil
+}
+
+var _ indexer = sumAggregate{}
+var _ ormkv.EntryCodec = sumAggregate{}
+
+// sumAggregatesCodec decodes and encodes the sums stored by the sum
+// aggregates of a table, which share the sumsId prefix.
+type sumAggregatesCodec map[fieldnames.FieldNames]*sumAggregate
+
+func (s sumAggregatesCodec) DecodeEntry(k, v []byte) (ormkv.Entry, error) {
+	for _, agg := range s {
+		if bytes.HasPrefix(k, agg.prefix) {
+			return agg.DecodeEntry(k, v)
+		}
+	}
+
+	return nil, ormerrors.UnexpectedDecodePrefix.Wrapf("can't find sum aggregate with key %x", k)
+}
+
+func (s sumAggregatesCodec) EncodeEntry(entry ormkv.Entry) (k, v []byte, err error) {
+	sumEntry, ok := entry.(*ormkv.SumEntry)
+	if !ok {
+		return nil, nil, ormerrors.BadDecodeEntry.Wrapf("%s", entry)
+	}
+
+	agg, ok := s[fieldnames.FieldsFromNames(sumEntry.Fields)]
+	if !ok {
+		return nil, nil, ormerrors.BadDecodeEntry.Wrapf("can't find sum aggregate with fields %s", sumEntry.Fields)
+	}
+
+	return agg.EncodeEntry(entry)
+}
+
+var _ ormkv.EntryCodec = sumAggregatesCodec{}
@@ -78,6 +78,14 @@ type Table interface {
 	// ormerrors.AlreadyExists will be returned.
 	Insert(ctx context.Context, message proto.Message) error
 
+	// InsertBatch inserts the provided entries in the store in a single write
+	// batch, avoiding the per-entry overhead of Insert, for instance when
+	// importing genesis state. Constraints are checked against both the store
+	// and the previous entries of the batch. Either all the entries are
+	// inserted or none of them, unless there is an error with the underlying
+	// store. See Insert for more details on behavior.
+	InsertBatch(ctx context.Context, messages ...proto.Message) error
+
 	// Update updates the provided entry in the store and fails if an entry
 	// with a matching primary key does not exist. See Save for more details
 	// on behavior.
@@ -156,4 +164,10 @@ type AutoIncrementTable interface {
 	// InsertReturningID inserts the provided entry in the store and returns the newly
 	// generated ID for the message or an error.
 	InsertReturningID(ctx context.Context, message proto.Message) (newId uint64, err error)
+
+	// LastInsertedSequence returns the last ID generated for the table, or 0
+	// if no ID was generated yet. The next generated ID is this value plus one.
+	// The sequence is stored with the table, so that it is covered by the
+	// transaction of the enclosing store and by JSON and binary exports.
+	LastInsertedSequence(ctx context.Context) (uint64, error)
 }
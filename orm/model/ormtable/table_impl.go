@@ -12,6 +12,7 @@ import (
 
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/cosmos/cosmos-sdk/orm/encoding/encodeutil"
 	"github.com/cosmos/cosmos-sdk/orm/encoding/ormkv"
@@ -30,6 +31,14 @@ type tableImpl struct {
 	tableId               uint32
 	typeResolver          TypeResolver
 	customJSONValidator   func(message proto.Message) error
+	versionField          protoreflect.FieldDescriptor
+	expiryField           protoreflect.FieldDescriptor
+	expiryIndex           concreteIndex
+	insertionOrderField   protoreflect.FieldDescriptor
+	insertionSeqCodec     *ormkv.SeqCodec
+	sumAggregates         map[fieldnames.FieldNames]*sumAggregate
+	minMaxAggregates      map[fieldnames.FieldNames]*minMaxAggregate
+	sequences             []*sequence
 }
 
 func (t *tableImpl) GetTable(message proto.Message) Table {
@@ -80,7 +89,44 @@ func (t tableImpl) save(ctx context.Context, backend Backend, message proto.Mess
 	return t.doSave(ctx, writer, message, mode)
 }
 
+func (t tableImpl) InsertBatch(ctx context.Context, messages ...proto.Message) error {
+	backend, err := t.getWriteBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	writer := newReadYourWritesBatchIndexCommitmentWriter(backend)
+	defer writer.Close()
+
+	positions := t.insertionPositions(messages...)
+	for _, message := range messages {
+		err = t.doSaveWithWriteBatch(ctx, writer, message, saveModeInsert)
+		if err != nil {
+			t.restoreInsertionPositions(positions, messages...)
+			return err
+		}
+	}
+
+	err = writer.Write()
+	if err != nil {
+		t.restoreInsertionPositions(positions, messages...)
+	}
+	return err
+}
+
 func (t tableImpl) doSave(ctx context.Context, writer *batchIndexCommitmentWriter, message proto.Message, mode saveMode) error {
+	positions := t.insertionPositions(message)
+	err := t.doSaveWithWriteBatch(ctx, writer, message, mode)
+	if err == nil {
+		err = writer.Write()
+	}
+	if err != nil {
+		t.restoreInsertionPositions(positions, message)
+	}
+	return err
+}
+
+func (t tableImpl) doSaveWithWriteBatch(ctx context.Context, writer *batchIndexCommitmentWriter, message proto.Message, mode saveMode) error {
 	mref := message.ProtoReflect()
 	pkValues, pk, err := t.EncodeKeyFromMessage(mref)
 	if err != nil {
@@ -98,17 +144,42 @@ func (t tableImpl) doSave(ctx context.Context, writer *batchIndexCommitmentWrite
 			return ormerrors.AlreadyExists.Wrapf("%q:%+v", mref.Descriptor().FullName(), pkValues)
 		}
 
+		var version uint64
+		if t.versionField != nil {
+			version = mref.Get(t.versionField).Uint()
+			existingVersion := existing.ProtoReflect().Get(t.versionField).Uint()
+			if version != existingVersion {
+				return ormerrors.VersionConflict.Wrapf("%q:%+v has version %d, not %d",
+					mref.Descriptor().FullName(), pkValues, existingVersion, version)
+			}
+		}
+
+		if t.insertionOrderField != nil {
+			mref.Set(t.insertionOrderField, existing.ProtoReflect().Get(t.insertionOrderField))
+		}
+
 		if validateHooks := writer.ValidateHooks(); validateHooks != nil {
 			err = validateHooks.ValidateUpdate(ctx, existing, message)
 			if err != nil {
 				return err
 			}
 		}
+
+		if t.versionField != nil {
+			mref.Set(t.versionField, protoreflect.ValueOfUint64(version+1))
+		}
 	} else {
 		if mode == saveModeUpdate {
 			return ormerrors.NotFound.Wrapf("%q", mref.Descriptor().FullName())
 		}
 
+		if t.insertionOrderField != nil {
+			err = t.setInsertionPosition(ctx, writer.IndexStore(), mref)
+			if err != nil {
+				return err
+			}
+		}
+
 		if validateHooks := writer.ValidateHooks(); validateHooks != nil {
 			err = validateHooks.ValidateInsert(ctx, message)
 			if err != nil {
@@ -141,8 +212,8 @@ func (t tableImpl) doSave(ctx context.Context, writer *batchIndexCommitmentWrite
 
 		}
 		if writeHooks := writer.WriteHooks(); writeHooks != nil {
-			writer.enqueueHook(func() {
-				writeHooks.OnInsert(ctx, message)
+			writer.enqueueHook(writeHooks, func(hooks WriteHooks) {
+				hooks.OnInsert(ctx, message)
 			})
 		}
 	} else {
@@ -154,13 +225,13 @@ func (t tableImpl) doSave(ctx context.Context, writer *batchIndexCommitmentWrite
 			}
 		}
 		if writeHooks := writer.WriteHooks(); writeHooks != nil {
-			writer.enqueueHook(func() {
-				writeHooks.OnUpdate(ctx, existing, message)
+			writer.enqueueHook(writeHooks, func(hooks WriteHooks) {
+				hooks.OnUpdate(ctx, existing, message)
 			})
 		}
 	}
 
-	return writer.Write()
+	return nil
 }
 
 func (t tableImpl) Delete(ctx context.Context, message proto.Message) error {
@@ -184,13 +255,13 @@ func (t tableImpl) DefaultJSON() json.RawMessage {
 	return json.RawMessage("[]")
 }
 
-func (t tableImpl) decodeJson(reader io.Reader, onMsg func(message proto.Message) error) error {
+func (t tableImpl) decodeJson(backend Backend, reader io.Reader, onMsg func(message proto.Message) error) error {
 	decoder, err := t.startDecodeJson(reader)
 	if err != nil {
 		return err
 	}
 
-	return t.doDecodeJson(decoder, nil, onMsg)
+	return t.doDecodeJson(decoder, backend, nil, onMsg)
 }
 
 func (t tableImpl) startDecodeJson(reader io.Reader) (*json.Decoder, error) {
@@ -209,11 +280,14 @@ func (t tableImpl) startDecodeJson(reader io.Reader) (*json.Decoder, error) {
 
 // onFirst is called on the first RawMessage and used for auto-increment tables
 // to decode the sequence in which case it should return true.
+// The values of the named sequences, which precede the messages, are set with
+// backend unless it is nil.
 // onMsg is called on every decoded message
-func (t tableImpl) doDecodeJson(decoder *json.Decoder, onFirst func(message json.RawMessage) bool, onMsg func(message proto.Message) error) error {
+func (t tableImpl) doDecodeJson(decoder *json.Decoder, backend Backend, onFirst func(message json.RawMessage) bool, onMsg func(message proto.Message) error) error {
 	unmarshalOptions := protojson.UnmarshalOptions{Resolver: t.typeResolver}
 
 	first := true
+	leading := len(t.sequences) != 0
 	for decoder.More() {
 		var rawJson json.RawMessage
 		err := decoder.Decode(&rawJson)
@@ -231,6 +305,17 @@ func (t tableImpl) doDecodeJson(decoder *json.Decoder, onFirst func(message json
 			}
 		}
 
+		if leading {
+			leading = false
+			ok, err := t.importSequencesJSON(backend, rawJson)
+			if err != nil {
+				return err
+			}
+			if ok {
+				continue
+			}
+		}
+
 		msg := t.MessageType().New().Interface()
 		err = unmarshalOptions.Unmarshal(rawJson, msg)
 		if err != nil {
@@ -277,7 +362,7 @@ func DefaultJSONValidator(message proto.Message) error {
 }
 
 func (t tableImpl) ValidateJSON(reader io.Reader) error {
-	return t.decodeJson(reader, func(message proto.Message) error {
+	return t.decodeJson(nil, reader, func(message proto.Message) error {
 		if t.customJSONValidator != nil {
 			return t.customJSONValidator(message)
 		} else {
@@ -292,7 +377,8 @@ func (t tableImpl) ImportJSON(ctx context.Context, reader io.Reader) error {
 		return err
 	}
 
-	return t.decodeJson(reader, func(message proto.Message) error {
+	ctx = withImporting(ctx)
+	return t.decodeJson(backend, reader, func(message proto.Message) error {
 		return t.save(ctx, backend, message, saveModeDefault)
 	})
 }
@@ -312,7 +398,16 @@ func (t tableImpl) doExportJSON(ctx context.Context, writer io.Writer, start boo
 		Resolver:      t.typeResolver,
 	}
 
-	var err error
+	backend, err := t.getBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	start, err = t.exportSequencesJSON(backend, writer, start)
+	if err != nil {
+		return err
+	}
+
 	it, _ := t.List(ctx, nil)
 	for {
 		found := it.Next()
@@ -382,6 +477,10 @@ func (t tableImpl) EncodeEntry(entry ormkv.Entry) (k, v []byte, err error) {
 		}
 
 		return idx.EncodeEntry(entry)
+	case *ormkv.SumEntry:
+		return sumAggregatesCodec(t.sumAggregates).EncodeEntry(entry)
+	case *ormkv.MinMaxEntry:
+		return minMaxAggregatesCodec(t.minMaxAggregates).EncodeEntry(entry)
 	default:
 		return nil, nil, ormerrors.BadDecodeEntry.Wrapf("%s", entry)
 	}
@@ -3,7 +3,10 @@ package ormtable_test
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"testing"
@@ -23,6 +26,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/orm/types/kv"
 
 	queryv1beta1 "github.com/cosmos/cosmos-sdk/api/cosmos/base/query/v1beta1"
+	ormv1alpha1 "github.com/cosmos/cosmos-sdk/api/cosmos/orm/v1alpha1"
 	sdkerrors "github.com/cosmos/cosmos-sdk/errors"
 	"github.com/cosmos/cosmos-sdk/orm/encoding/ormkv"
 	"github.com/cosmos/cosmos-sdk/orm/internal/testkv"
@@ -146,7 +150,7 @@ func TestImportedMessageIterator(t *testing.T) {
 }
 
 // check that the ormkv.Entry's decode and encode to the same bytes
-func checkEncodeDecodeEntries(t *testing.T, table ormtable.Table, store kv.ReadonlyStore) {
+func checkEncodeDecodeEntries(t assert.TestingT, table ormtable.Table, store kv.ReadonlyStore) {
 	it, err := store.Iterator(nil, nil)
 	assert.NilError(t, err)
 	for it.Valid() {
@@ -790,3 +794,2951 @@ func TestReadonly(t *testing.T) {
 	ctx := ormtable.WrapContextDefault(readBackend)
 	assert.ErrorIs(t, ormerrors.ReadOnly, table.Insert(ctx, &testpb.ExampleTable{}))
 }
+
+func TestCount(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 1, I64: 1, Str: "a", U64: 1}))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 1, I64: 2, Str: "b", U64: 1}))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 2, I64: 1, Str: "a", U64: 2}))
+
+	count, err := table.Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	count, err = table.PrimaryKey().Count(ctx, uint32(1))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	count, err = table.PrimaryKey().Count(ctx, uint32(1), int64(2), "b")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	count, err = table.GetIndex("str,u32").Count(ctx, "a")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	count, err = table.GetUniqueIndex("u64,str").Count(ctx, uint64(1))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	count, err = table.GetIndex("bz,str").Count(ctx, []byte("missing"))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), count)
+}
+
+func TestListRangeExclusiveEnd(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := uint32(1); i <= 5; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, I64: 1, Str: "a", U64: uint64(i) * 2}))
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, I64: 2, Str: "a", U64: uint64(i)*2 + 1}))
+	}
+
+	listU32s := func(from, to []interface{}, opts ...ormlist.Option) []uint32 {
+		it, err := table.ListRange(ctx, from, to, opts...)
+		assert.NilError(t, err)
+		defer it.Close()
+
+		var res []uint32
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			res = append(res, msg.(*testpb.ExampleTable).U32)
+		}
+		return res
+	}
+
+	// inclusive by default
+	assert.DeepEqual(t, []uint32{2, 2, 3, 3, 4, 4}, listU32s([]interface{}{uint32(2)}, []interface{}{uint32(4)}))
+
+	// partial end key excludes all entries with the end key as prefix
+	assert.DeepEqual(t, []uint32{2, 2, 3, 3}, listU32s([]interface{}{uint32(2)}, []interface{}{uint32(4)}, ormlist.ExclusiveEnd()))
+	assert.DeepEqual(t, []uint32{3, 3, 2, 2}, listU32s([]interface{}{uint32(2)}, []interface{}{uint32(4)}, ormlist.ExclusiveEnd(), ormlist.Reverse()))
+
+	// full end key only excludes the end key itself
+	from := []interface{}{uint32(2), int64(2), "a"}
+	to := []interface{}{uint32(4), int64(2), "a"}
+	assert.DeepEqual(t, []uint32{2, 3, 3, 4}, listU32s(from, to, ormlist.ExclusiveEnd()))
+	assert.DeepEqual(t, []uint32{4, 3, 3, 2}, listU32s(from, to, ormlist.ExclusiveEnd(), ormlist.Reverse()))
+}
+
+func TestIndexHasPrefix(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 1, I64: 1, Str: "a", U64: 10}))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 3, I64: 1, Str: "b", U64: 10}))
+
+	uniqueIdx := table.GetUniqueIndex("u64,str")
+	testCases := []struct {
+		name   string
+		index  ormtable.Index
+		prefix []interface{}
+		found  bool
+	}{
+		{"unique index, empty prefix", uniqueIdx, nil, true},
+		{"unique index, partial prefix", uniqueIdx, []interface{}{uint64(10)}, true},
+		{"unique index, partial prefix not found", uniqueIdx, []interface{}{uint64(11)}, false},
+		{"unique index, full key", uniqueIdx, []interface{}{uint64(10), "b"}, true},
+		{"unique index, full key not found", uniqueIdx, []interface{}{uint64(10), "c"}, false},
+		{"index, partial prefix", table.GetIndex("str,u32"), []interface{}{"b"}, true},
+		{"index, partial prefix not found", table.GetIndex("str,u32"), []interface{}{"c"}, false},
+		{"index, full key", table.GetIndex("str,u32"), []interface{}{"a", uint32(1)}, true},
+		{"index, full key not found", table.GetIndex("str,u32"), []interface{}{"a", uint32(3)}, false},
+		{"primary key, partial prefix", table.PrimaryKey(), []interface{}{uint32(3)}, true},
+		{"primary key, partial prefix not found", table.PrimaryKey(), []interface{}{uint32(2)}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			found, err := tc.index.HasPrefix(ctx, tc.prefix...)
+			assert.NilError(t, err)
+			assert.Equal(t, tc.found, found)
+		})
+	}
+}
+
+func TestDeleteByPrefix(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := uint32(0); i < 10; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{
+			U32: i % 3, I64: int64(i), Str: fmt.Sprintf("s%d", i), U64: uint64(i), Bz: []byte{byte(i % 2)},
+		}))
+	}
+
+	deleted, err := ormtable.DeleteByPrefix(ctx, table, uint32(1))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(3), deleted)
+
+	// every index only has entries for the remaining rows
+	for _, idx := range table.Indexes() {
+		count, err := idx.Count(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, uint64(7), count, idx.Fields())
+	}
+
+	found, err := table.PrimaryKey().HasPrefix(ctx, uint32(1))
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+
+	// secondary indexes can be used too
+	deleted, err = ormtable.DeleteByPrefix(ctx, table.GetIndex("bz,str"), []byte{0})
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(4), deleted)
+	deleted, err = ormtable.DeleteByPrefix(ctx, table.GetUniqueIndex("u64,str"), uint64(9))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), deleted)
+	deleted, err = ormtable.DeleteByPrefix(ctx, table.GetIndex("bz,str"), []byte{0})
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), deleted)
+	count, err := table.Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), count)
+}
+
+func TestListPage(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := uint32(0); i < 5; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, U64: uint64(i)}))
+	}
+
+	listPages := func(limit int, opts ...ormlist.Option) (pages [][]uint32) {
+		var cursor ormlist.CursorT
+		for {
+			var page []uint32
+			cursor, err = ormtable.ListPage(ctx, table, nil, limit, func(message proto.Message) (bool, error) {
+				page = append(page, message.(*testpb.ExampleTable).U32)
+				return false, nil
+			}, append(opts, ormlist.Cursor(cursor))...)
+			assert.NilError(t, err)
+			pages = append(pages, page)
+			if cursor == nil {
+				return pages
+			}
+		}
+	}
+
+	assert.DeepEqual(t, [][]uint32{{0, 1}, {2, 3}, {4}}, listPages(2))
+	assert.DeepEqual(t, [][]uint32{{0, 1, 2, 3, 4}}, listPages(5))
+	assert.DeepEqual(t, [][]uint32{{0, 1, 2, 3, 4}}, listPages(0))
+	assert.DeepEqual(t, [][]uint32{{4, 3, 2}, {1, 0}}, listPages(3, ormlist.Reverse()))
+
+	// stopping early returns a cursor after the last visited entry
+	cursor, err := ormtable.ListPage(ctx, table, nil, 0, func(message proto.Message) (bool, error) {
+		return message.(*testpb.ExampleTable).U32 == 1, nil
+	})
+	assert.NilError(t, err)
+	it, err := table.List(ctx, nil, ormlist.Cursor(cursor))
+	assert.NilError(t, err)
+	assert.Assert(t, it.Next())
+	msg, err := it.GetMessage()
+	assert.NilError(t, err)
+	assert.Equal(t, uint32(2), msg.(*testpb.ExampleTable).U32)
+	it.Close()
+}
+
+func TestTimestampIndexOrdering(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTimestamp{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	store, err := testpb.NewExampleTimestampTable(table)
+	assert.NilError(t, err)
+
+	epoch := time.Unix(0, 0)
+	times := []time.Time{
+		epoch.Add(time.Hour),
+		epoch.Add(-time.Nanosecond),
+		epoch,
+		epoch.Add(-100 * 365 * 24 * time.Hour),
+		epoch.Add(time.Hour + time.Nanosecond),
+		epoch.Add(-time.Second - 1),
+		epoch.Add(time.Nanosecond),
+	}
+	for _, ts := range times {
+		assert.NilError(t, store.Insert(ctx, &testpb.ExampleTimestamp{Ts: timestamppb.New(ts)}))
+	}
+
+	sorted := append([]time.Time{}, times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	listTimes := func(opts ...ormlist.Option) []time.Time {
+		it, err := store.List(ctx, testpb.ExampleTimestampTsIndexKey{}, opts...)
+		assert.NilError(t, err)
+		defer it.Close()
+
+		var res []time.Time
+		for it.Next() {
+			v, err := it.Value()
+			assert.NilError(t, err)
+			res = append(res, v.Ts.AsTime())
+		}
+		return res
+	}
+
+	ascending := listTimes()
+	assert.Equal(t, len(sorted), len(ascending))
+	for i, ts := range ascending {
+		assert.Assert(t, sorted[i].Equal(ts), "expected %s, got %s", sorted[i], ts)
+	}
+
+	descending := listTimes(ormlist.Reverse())
+	assert.Equal(t, len(sorted), len(descending))
+	for i, ts := range descending {
+		assert.Assert(t, sorted[len(sorted)-1-i].Equal(ts), "expected %s, got %s", sorted[len(sorted)-1-i], ts)
+	}
+}
+
+func TestFilteredIndex(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.SimpleExample{}).ProtoReflect().Type(),
+		IndexFilters: map[string]func(proto.Message) bool{
+			"unique": func(message proto.Message) bool {
+				return message.(*testpb.SimpleExample).NotUnique == "active"
+			},
+		},
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	uniqueIdx := table.GetUniqueIndex("unique")
+
+	assertIndexed := func(unique string, expected bool) {
+		t.Helper()
+		found, err := uniqueIdx.Has(ctx, unique)
+		assert.NilError(t, err)
+		assert.Equal(t, expected, found)
+	}
+
+	// only matching messages are indexed, and unique constraints only apply
+	// to them
+	assert.NilError(t, table.Insert(ctx, &testpb.SimpleExample{Name: "a", Unique: "x", NotUnique: "active"}))
+	assert.NilError(t, table.Insert(ctx, &testpb.SimpleExample{Name: "b", Unique: "x", NotUnique: "inactive"}))
+	assert.NilError(t, table.Insert(ctx, &testpb.SimpleExample{Name: "c", Unique: "y", NotUnique: "inactive"}))
+	assert.ErrorIs(t, table.Insert(ctx, &testpb.SimpleExample{Name: "d", Unique: "x", NotUnique: "active"}), ormerrors.UniqueKeyViolation)
+	assertIndexed("x", true)
+	assertIndexed("y", false)
+
+	// matching to matching
+	assert.NilError(t, table.Update(ctx, &testpb.SimpleExample{Name: "a", Unique: "z", NotUnique: "active"}))
+	assertIndexed("x", false)
+	assertIndexed("z", true)
+
+	// matching to non-matching
+	assert.NilError(t, table.Update(ctx, &testpb.SimpleExample{Name: "a", Unique: "z", NotUnique: "inactive"}))
+	assertIndexed("z", false)
+
+	// non-matching to matching
+	assert.NilError(t, table.Update(ctx, &testpb.SimpleExample{Name: "c", Unique: "y", NotUnique: "active"}))
+	assertIndexed("y", true)
+	assert.ErrorIs(t, table.Update(ctx, &testpb.SimpleExample{Name: "b", Unique: "y", NotUnique: "active"}), ormerrors.UniqueKeyViolation)
+
+	// non-matching to non-matching
+	assert.NilError(t, table.Update(ctx, &testpb.SimpleExample{Name: "b", Unique: "w", NotUnique: "inactive"}))
+	assertIndexed("w", false)
+
+	assert.NilError(t, table.Delete(ctx, &testpb.SimpleExample{Name: "c"}))
+	assert.NilError(t, table.Delete(ctx, &testpb.SimpleExample{Name: "b"}))
+	count, err := uniqueIdx.Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), count)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.SimpleExample{}).ProtoReflect().Type(),
+		IndexFilters: map[string]func(proto.Message) bool{
+			"not_unique": func(proto.Message) bool { return true },
+		},
+	})
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+}
+
+func TestFirstLast(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := uint32(1); i <= 3; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, I64: 1, Str: "a", U64: uint64(i)}))
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, I64: 2, Str: "b", U64: uint64(i)}))
+	}
+
+	testCases := []struct {
+		name      string
+		index     ormtable.Index
+		prefix    []interface{}
+		expFirst  *testpb.ExampleTable
+		expLast   *testpb.ExampleTable
+		expAbsent bool
+	}{
+		{
+			"primary key",
+			table.PrimaryKey(), nil,
+			&testpb.ExampleTable{U32: 1, I64: 1, Str: "a", U64: 1},
+			&testpb.ExampleTable{U32: 3, I64: 2, Str: "b", U64: 3},
+			false,
+		},
+		{
+			"primary key prefix",
+			table.PrimaryKey(), []interface{}{uint32(2)},
+			&testpb.ExampleTable{U32: 2, I64: 1, Str: "a", U64: 2},
+			&testpb.ExampleTable{U32: 2, I64: 2, Str: "b", U64: 2},
+			false,
+		},
+		{
+			"index prefix",
+			table.GetIndex("str,u32"), []interface{}{"b"},
+			&testpb.ExampleTable{U32: 1, I64: 2, Str: "b", U64: 1},
+			&testpb.ExampleTable{U32: 3, I64: 2, Str: "b", U64: 3},
+			false,
+		},
+		{
+			"unique index prefix",
+			table.GetUniqueIndex("u64,str"), []interface{}{uint64(3)},
+			&testpb.ExampleTable{U32: 3, I64: 1, Str: "a", U64: 3},
+			&testpb.ExampleTable{U32: 3, I64: 2, Str: "b", U64: 3},
+			false,
+		},
+		{
+			"empty range",
+			table.GetIndex("str,u32"), []interface{}{"c"},
+			nil, nil,
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var first, last testpb.ExampleTable
+			found, err := tc.index.First(ctx, &first, tc.prefix...)
+			assert.NilError(t, err)
+			assert.Equal(t, !tc.expAbsent, found)
+			found, err = tc.index.Last(ctx, &last, tc.prefix...)
+			assert.NilError(t, err)
+			assert.Equal(t, !tc.expAbsent, found)
+
+			if !tc.expAbsent {
+				assert.DeepEqual(t, tc.expFirst, &first, protocmp.Transform())
+				assert.DeepEqual(t, tc.expLast, &last, protocmp.Transform())
+			}
+		})
+	}
+}
+
+func TestUniqueKeyViolationError(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 1, U64: 10, Str: "a"}))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 2, U64: 11, Str: "a"}))
+
+	assertViolation := func(err error) {
+		t.Helper()
+		assert.ErrorIs(t, err, ormerrors.UniqueKeyViolation)
+
+		var violation *ormtable.UniqueKeyViolationError
+		assert.Assert(t, errors.As(err, &violation))
+		assert.Equal(t, "u64,str", violation.Fields)
+		assert.DeepEqual(t, []interface{}{uint64(10), "a"}, protoValuesToInterfaces(violation.Values))
+	}
+
+	assertViolation(table.Insert(ctx, &testpb.ExampleTable{U32: 3, U64: 10, Str: "a"}))
+	assertViolation(table.Update(ctx, &testpb.ExampleTable{U32: 2, U64: 10, Str: "a"}))
+}
+
+func TestRebuildIndex(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	commitmentStore, indexStore := dbm.NewMemDB(), dbm.NewMemDB()
+	ctx := ormtable.WrapContextDefault(ormtable.NewBackend(ormtable.BackendOptions{
+		CommitmentStore: commitmentStore,
+		IndexStore:      indexStore,
+	}))
+	// writes through corruptCtx don't update the indexes
+	corruptCtx := ormtable.WrapContextDefault(ormtable.NewBackend(ormtable.BackendOptions{
+		CommitmentStore: commitmentStore,
+		IndexStore:      dbm.NewMemDB(),
+	}))
+
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 1, I64: 1, Str: "a", U64: 1}))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 2, I64: 1, Str: "b", U64: 2}))
+	// a missing entry
+	assert.NilError(t, table.Insert(corruptCtx, &testpb.ExampleTable{U32: 3, I64: 1, Str: "c", U64: 3}))
+	// a stale entry
+	assert.NilError(t, table.Delete(corruptCtx, &testpb.ExampleTable{U32: 2, I64: 1, Str: "b"}))
+
+	uniqueIdx := table.GetUniqueIndex("u64,str")
+	assertIndexes := func(expected bool) {
+		t.Helper()
+		found, err := uniqueIdx.Has(ctx, uint64(3), "c")
+		assert.NilError(t, err)
+		assert.Equal(t, expected, found)
+		found, err = uniqueIdx.Has(ctx, uint64(2), "b")
+		assert.NilError(t, err)
+		assert.Equal(t, !expected, found)
+	}
+	assertIndexes(false)
+
+	for i := 0; i < 2; i++ {
+		assert.NilError(t, ormtable.RebuildIndex(ctx, table, "u64,str"))
+		assertIndexes(true)
+
+		var msg testpb.ExampleTable
+		found, err := uniqueIdx.Get(ctx, &msg, uint64(3), "c")
+		assert.NilError(t, err)
+		assert.Assert(t, found)
+		assert.Equal(t, uint32(3), msg.U32)
+
+		count, err := uniqueIdx.Count(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, uint64(2), count)
+	}
+
+	// other indexes are unchanged
+	count, err := table.GetIndex("str,u32").Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), count)
+	count, err = table.GetIndex("str,u32").Count(ctx, "b")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	assert.NilError(t, ormtable.RebuildIndex(ctx, table, "str,u32"))
+	count, err = table.GetIndex("str,u32").Count(ctx, "c")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), count)
+	count, err = table.GetIndex("str,u32").Count(ctx, "b")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), count)
+
+	assert.ErrorIs(t, ormtable.RebuildIndex(ctx, table, "u32,i64,str"), ormerrors.CantFindIndex)
+	assert.ErrorIs(t, ormtable.RebuildIndex(ctx, table, "missing"), ormerrors.CantFindIndex)
+}
+
+func TestCursorEncoding(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := uint32(0); i < 4; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, Str: fmt.Sprintf("s%d", i), U64: uint64(i)}))
+	}
+
+	for _, index := range table.Indexes() {
+		t.Run(index.Fields(), func(t *testing.T) {
+			it, err := index.List(ctx, nil)
+			assert.NilError(t, err)
+			assert.Assert(t, it.Next())
+			cursor := it.Cursor()
+			it.Close()
+
+			bz, err := ormtable.EncodeCursor(index, cursor)
+			assert.NilError(t, err)
+			decoded, err := ormtable.DecodeCursor(index, bz)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, cursor, decoded)
+
+			// iteration resumes after the cursor
+			it, err = index.List(ctx, nil, ormlist.Cursor(decoded))
+			assert.NilError(t, err)
+			count := 0
+			for it.Next() {
+				count++
+			}
+			it.Close()
+			assert.Equal(t, 3, count)
+
+			// cursors of other indexes are rejected
+			for _, other := range table.Indexes() {
+				if other.Fields() == index.Fields() {
+					continue
+				}
+				_, err = ormtable.DecodeCursor(other, bz)
+				assert.ErrorIs(t, err, ormerrors.InvalidCursor)
+				_, err = ormtable.EncodeCursor(other, cursor)
+				assert.ErrorIs(t, err, ormerrors.InvalidCursor)
+			}
+		})
+	}
+
+	index := table.GetIndex("str,u32")
+	_, err = ormtable.DecodeCursor(index, []byte{2, 1, 2, 3})
+	assert.ErrorIs(t, err, ormerrors.InvalidCursor)
+	_, err = ormtable.DecodeCursor(index, []byte{1, 99, 2, 3})
+	assert.ErrorIs(t, err, ormerrors.InvalidCursor)
+
+	cursor, err := ormtable.DecodeCursor(index, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, cursor == nil)
+}
+
+func TestDescendingIndex(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType:       (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		DescendingIndexes: []string{"str,u32", "u64,str"},
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	data := []*testpb.ExampleTable{
+		{U32: 1, Str: "b", U64: 2},
+		{U32: 2, Str: "a", U64: 3},
+		{U32: 3, Str: "ab", U64: 1},
+		{U32: 4, Str: "", U64: 1},
+		{U32: 5, Str: "b", U64: 4},
+	}
+	for _, msg := range data {
+		assert.NilError(t, table.Insert(ctx, msg))
+	}
+
+	assertU32s := func(it ormtable.Iterator, err error, expected ...uint32) {
+		t.Helper()
+		assert.NilError(t, err)
+		var u32s []uint32
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			u32s = append(u32s, msg.(*testpb.ExampleTable).U32)
+		}
+		it.Close()
+		assert.DeepEqual(t, expected, u32s)
+	}
+
+	// forward iteration is in descending order
+	strIdx := table.GetIndex("str,u32")
+	it, err := strIdx.List(ctx, nil)
+	assertU32s(it, err, 5, 1, 3, 2, 4)
+	it, err = strIdx.List(ctx, nil, ormlist.Reverse())
+	assertU32s(it, err, 4, 2, 3, 1, 5)
+	it, err = strIdx.List(ctx, []interface{}{"b"})
+	assertU32s(it, err, 5, 1)
+	it, err = strIdx.List(ctx, []interface{}{"a"})
+	assertU32s(it, err, 2)
+
+	// ranges go from the greatest to the smallest key
+	it, err = strIdx.ListRange(ctx, []interface{}{"ab"}, []interface{}{""})
+	assertU32s(it, err, 3, 2, 4)
+	it, err = strIdx.ListRange(ctx, []interface{}{"b"}, []interface{}{"a"}, ormlist.ExclusiveEnd())
+	assertU32s(it, err, 5, 1, 3)
+
+	// unique constraints and lookups are unaffected
+	uniqueIdx := table.GetUniqueIndex("u64,str")
+	var msg testpb.ExampleTable
+	found, err := uniqueIdx.Get(ctx, &msg, uint64(1), "ab")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, uint32(3), msg.U32)
+	assert.ErrorIs(t, table.Insert(ctx, &testpb.ExampleTable{U32: 6, Str: "a", U64: 3}), ormerrors.UniqueKeyViolation)
+	it, err = uniqueIdx.List(ctx, []interface{}{uint64(1)})
+	assertU32s(it, err, 3, 4)
+
+	count, err := strIdx.Count(ctx, "b")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:       (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		DescendingIndexes: []string{"u32"},
+	})
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+}
+
+func TestRepeatedFieldIndex(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor: &ormv1alpha1.TableDescriptor{
+			Id:         1,
+			PrimaryKey: &ormv1alpha1.PrimaryKeyDescriptor{Fields: "u32,i64,str"},
+			Index:      []*ormv1alpha1.SecondaryIndexDescriptor{{Id: 1, Fields: "repeated"}},
+		},
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	idx := table.GetIndex("repeated")
+	assert.Assert(t, idx != nil)
+
+	assertTagged := func(element uint32, expected ...uint32) {
+		t.Helper()
+		it, err := idx.List(ctx, []interface{}{element})
+		assert.NilError(t, err)
+		var u32s []uint32
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			u32s = append(u32s, msg.(*testpb.ExampleTable).U32)
+		}
+		it.Close()
+		assert.DeepEqual(t, expected, u32s)
+	}
+
+	// duplicate elements only have one entry
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 1, Repeated: []uint32{10, 20, 10}}))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 2, Repeated: []uint32{20}}))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 3}))
+	assertTagged(10, 1)
+	assertTagged(20, 1, 2)
+	count, err := idx.Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	// updates remove the entries of removed elements and add the new ones
+	assert.NilError(t, table.Update(ctx, &testpb.ExampleTable{U32: 1, Repeated: []uint32{20, 30}}))
+	assertTagged(10)
+	assertTagged(20, 1, 2)
+	assertTagged(30, 1)
+	assert.NilError(t, table.Update(ctx, &testpb.ExampleTable{U32: 3, Repeated: []uint32{30, 30}}))
+	assertTagged(30, 1, 3)
+
+	// deletes remove all the entries
+	assert.NilError(t, table.Delete(ctx, &testpb.ExampleTable{U32: 1}))
+	assertTagged(20, 2)
+	assertTagged(30, 3)
+	count, err = idx.Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	// repeated fields can't be used in unique indexes
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor: &ormv1alpha1.TableDescriptor{
+			Id:         1,
+			PrimaryKey: &ormv1alpha1.PrimaryKeyDescriptor{Fields: "u32,i64,str"},
+			Index:      []*ormv1alpha1.SecondaryIndexDescriptor{{Id: 1, Fields: "repeated", Unique: true}},
+		},
+	})
+	assert.ErrorIs(t, err, ormerrors.UnsupportedKeyField)
+}
+
+// recordingWriteHooks is an ormtable.WriteHooks recording the names of the
+// inserted, updated and deleted SimpleExample messages, which panics with
+// panicValue after recording if it is set, or with the call if panics is true.
+type recordingWriteHooks struct {
+	calls      []string
+	panics     bool
+	panicValue interface{}
+}
+
+func (r *recordingWriteHooks) record(call string, message proto.Message) {
+	r.calls = append(r.calls, fmt.Sprintf("%s %s", call, message.(*testpb.SimpleExample).Name))
+	if r.panicValue != nil {
+		panic(r.panicValue)
+	}
+	if r.panics {
+		panic(call)
+	}
+}
+
+// ErrorOutOfGas mimics the out of gas panics of the SDK gas meters.
+type ErrorOutOfGas struct {
+	Descriptor string
+}
+
+func (r *recordingWriteHooks) OnInsert(_ context.Context, message proto.Message) {
+	r.record("insert", message)
+}
+
+func (r *recordingWriteHooks) OnUpdate(_ context.Context, _, new proto.Message) {
+	r.record("update", new)
+}
+
+func (r *recordingWriteHooks) OnDelete(_ context.Context, message proto.Message) {
+	r.record("delete", message)
+}
+
+func TestMultiWriteHooks(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.SimpleExample{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+
+	panicking := &recordingWriteHooks{panics: true}
+	recording := &recordingWriteHooks{}
+	var panics []interface{}
+	backend := ormtable.NewBackend(ormtable.BackendOptions{
+		CommitmentStore: dbm.NewMemDB(),
+		IndexStore:      dbm.NewMemDB(),
+		WriteHookPanicHandler: func(recovered interface{}) {
+			if _, ok := recovered.(ErrorOutOfGas); ok {
+				panic(recovered)
+			}
+			panics = append(panics, recovered)
+		},
+	})
+	ctx := ormtable.WrapContextDefault(backend.WithWriteHooks(ormtable.MultiWriteHooks(panicking, recording)))
+
+	// panics in hooks don't prevent other hooks from being called nor writes
+	// from being applied
+	assert.NilError(t, table.Insert(ctx, &testpb.SimpleExample{Name: "a", Unique: "x"}))
+	assert.NilError(t, table.InsertBatch(ctx, &testpb.SimpleExample{Name: "b", Unique: "y"}, &testpb.SimpleExample{Name: "c", Unique: "z"}))
+	assert.NilError(t, table.Update(ctx, &testpb.SimpleExample{Name: "a", Unique: "w"}))
+	assert.NilError(t, table.Delete(ctx, &testpb.SimpleExample{Name: "b"}))
+	expected := []string{"insert a", "insert b", "insert c", "update a", "delete b"}
+	assert.DeepEqual(t, expected, panicking.calls)
+	assert.DeepEqual(t, expected, recording.calls)
+
+	// recovered panics are reported to the handler of the backend
+	assert.DeepEqual(t, []interface{}{"insert", "insert", "insert", "update", "delete"}, panics)
+
+	found, err := table.GetUniqueIndex("unique").Has(ctx, "z")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	found, err = table.GetUniqueIndex("unique").Has(ctx, "w")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+
+	// hooks aren't called for failed writes
+	assert.ErrorIs(t, table.Insert(ctx, &testpb.SimpleExample{Name: "d", Unique: "z"}), ormerrors.UniqueKeyViolation)
+	assert.DeepEqual(t, expected, recording.calls)
+
+	// the handler can panic again to abort the transaction
+	outOfGas := &recordingWriteHooks{panicValue: ErrorOutOfGas{Descriptor: "WriteFlat"}}
+	recording.calls = nil
+	ctx = ormtable.WrapContextDefault(backend.WithWriteHooks(ormtable.MultiWriteHooks(outOfGas, recording)))
+	func() {
+		defer func() {
+			assert.Equal(t, ErrorOutOfGas{Descriptor: "WriteFlat"}, recover())
+		}()
+		_ = table.Insert(ctx, &testpb.SimpleExample{Name: "e", Unique: "v"})
+		t.Fatal("expected an out of gas panic")
+	}()
+	assert.Assert(t, recording.calls == nil)
+	assert.Equal(t, 5, len(panics))
+
+	// panics are ignored without a handler
+	ctx = ormtable.WrapContextDefault(testkv.NewSplitMemBackend().WithWriteHooks(ormtable.MultiWriteHooks(outOfGas, recording)))
+	assert.NilError(t, table.Insert(ctx, &testpb.SimpleExample{Name: "e", Unique: "v"}))
+	assert.DeepEqual(t, []string{"insert e"}, recording.calls)
+}
+
+func TestEstimateCount(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	n := ormtable.EstimateCountLimit + 5
+	messages := make([]proto.Message, 0, n)
+	for i := 0; i < n; i++ {
+		messages = append(messages, &testpb.ExampleTable{U32: uint32(i % 2), I64: int64(i), U64: uint64(i)})
+	}
+	assert.NilError(t, table.InsertBatch(ctx, messages...))
+
+	count, exact, err := table.EstimateCount(ctx)
+	assert.NilError(t, err)
+	assert.Assert(t, !exact)
+	assert.Equal(t, uint64(ormtable.EstimateCountLimit), count)
+
+	count, exact, err = table.GetUniqueIndex("u64,str").EstimateCount(ctx, uint64(3))
+	assert.NilError(t, err)
+	assert.Assert(t, exact)
+	assert.Equal(t, uint64(1), count)
+
+	count, exact, err = table.GetIndex("str,u32").EstimateCount(ctx, "", uint32(1))
+	assert.NilError(t, err)
+	assert.Assert(t, exact)
+	assert.Equal(t, uint64(n/2), count)
+}
+
+func TestBinaryExportImport(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	store := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := 0; i < 100; {
+		x := testutil.GenA.Example().(proto.Message)
+		err = table.Insert(store, x)
+		if sdkerrors.IsOf(err, ormerrors.PrimaryKeyConstraintViolation, ormerrors.UniqueKeyViolation) {
+			continue
+		} else {
+			assert.NilError(t, err)
+		}
+		i++
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NilError(t, ormtable.ExportTable(store, table, buf))
+
+	store2 := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	assert.NilError(t, ormtable.ImportTable(store2, table, bytes.NewReader(buf.Bytes())))
+	assertTablesEqual(t, table, store, store2)
+
+	// re-exporting produces the same bytes
+	buf2 := &bytes.Buffer{}
+	assert.NilError(t, ormtable.ExportTable(store2, table, buf2))
+	assert.DeepEqual(t, buf.Bytes(), buf2.Bytes())
+
+	// truncated input is rejected
+	store3 := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	err = ormtable.ImportTable(store3, table, bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// a huge length prefix is rejected without allocating it
+	hugeSize := make([]byte, binary.MaxVarintLen64)
+	hugeSize = hugeSize[:binary.PutUvarint(hugeSize, 1<<62)]
+	err = ormtable.ImportTable(store3, table, bytes.NewReader(append(hugeSize, 1, 2, 3)))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// importing entries which already exist fails
+	err = ormtable.ImportTable(store2, table, bytes.NewReader(buf.Bytes()))
+	assert.ErrorIs(t, err, ormerrors.AlreadyExists)
+}
+
+func TestBinaryExportImportAutoIncrement(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	autoTable := table.(ormtable.AutoIncrementTable)
+	store := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for _, x := range []string{"a", "b", "c"} {
+		assert.NilError(t, table.Insert(store, &testpb.ExampleAutoIncrementTable{X: x}))
+	}
+	assert.NilError(t, table.Delete(store, &testpb.ExampleAutoIncrementTable{Id: 3}))
+
+	buf := &bytes.Buffer{}
+	assert.NilError(t, ormtable.ExportTable(store, table, buf))
+
+	store2 := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	assert.NilError(t, ormtable.ImportTable(store2, table, bytes.NewReader(buf.Bytes())))
+	assertTablesEqual(t, table, store, store2)
+
+	// the sequence is imported, so deleted IDs aren't reused
+	id, err := autoTable.InsertReturningID(store2, &testpb.ExampleAutoIncrementTable{X: "d"})
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(4), id)
+}
+
+func TestForeignKey(t *testing.T) {
+	parents, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	children, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.SimpleExample{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+
+	newCtx := func(mode ormtable.ForeignKeyDeleteMode) context.Context {
+		fk, err := ormtable.NewForeignKey("child_parent", children, "unique", parents.GetUniqueIndex("x"), mode)
+		assert.NilError(t, err)
+		return ormtable.WrapContextDefault(testkv.NewSplitMemBackend().
+			WithValidateHooks(ormtable.MultiValidateHooks(fk)))
+	}
+	assertViolation := func(err error) {
+		t.Helper()
+		var fkErr *ormtable.ForeignKeyViolationError
+		assert.Assert(t, errors.As(err, &fkErr), err)
+		assert.Equal(t, "child_parent", fkErr.Constraint)
+		assert.ErrorIs(t, err, ormerrors.ForeignKeyViolation)
+	}
+
+	t.Run("restrict", func(t *testing.T) {
+		ctx := newCtx(ormtable.RestrictDelete)
+
+		assertViolation(children.Insert(ctx, &testpb.SimpleExample{Name: "a", Unique: "foo"}))
+		// unset references aren't checked
+		assert.NilError(t, children.Insert(ctx, &testpb.SimpleExample{Name: "b"}))
+
+		parent := &testpb.ExampleAutoIncrementTable{X: "foo"}
+		assert.NilError(t, parents.Insert(ctx, parent))
+		assert.NilError(t, children.Insert(ctx, &testpb.SimpleExample{Name: "a", Unique: "foo"}))
+		assertViolation(children.Update(ctx, &testpb.SimpleExample{Name: "a", Unique: "bar"}))
+
+		assertViolation(parents.Delete(ctx, parent))
+		assertViolation(parents.Update(ctx, &testpb.ExampleAutoIncrementTable{Id: parent.Id, X: "bar"}))
+		// updates not changing the referenced key are allowed
+		assert.NilError(t, parents.Update(ctx, &testpb.ExampleAutoIncrementTable{Id: parent.Id, X: "foo", Y: 1}))
+
+		assert.NilError(t, children.Delete(ctx, &testpb.SimpleExample{Name: "a"}))
+		assert.NilError(t, parents.Delete(ctx, parent))
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		ctx := newCtx(ormtable.CascadeDelete)
+
+		foo := &testpb.ExampleAutoIncrementTable{X: "foo"}
+		assert.NilError(t, parents.Insert(ctx, foo))
+		assert.NilError(t, parents.Insert(ctx, &testpb.ExampleAutoIncrementTable{X: "bar"}))
+		assert.NilError(t, parents.Insert(ctx, &testpb.ExampleAutoIncrementTable{X: "baz"}))
+		assert.NilError(t, children.Insert(ctx, &testpb.SimpleExample{Name: "a", Unique: "foo"}))
+		assert.NilError(t, children.Insert(ctx, &testpb.SimpleExample{Name: "b", Unique: "bar"}))
+		assert.NilError(t, children.Insert(ctx, &testpb.SimpleExample{Name: "c", Unique: "baz"}))
+
+		assert.NilError(t, parents.Delete(ctx, foo))
+		found, err := children.Has(ctx, &testpb.SimpleExample{Name: "a"})
+		assert.NilError(t, err)
+		assert.Assert(t, !found)
+		found, err = children.Has(ctx, &testpb.SimpleExample{Name: "b"})
+		assert.NilError(t, err)
+		assert.Assert(t, found)
+
+		assert.NilError(t, parents.DeleteBy(ctx))
+		count, err := children.Count(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, uint64(0), count)
+	})
+
+	_, err = ormtable.NewForeignKey("bad", children, "not_unique", parents.GetUniqueIndex("x"), ormtable.RestrictDelete)
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+	_, err = ormtable.NewForeignKey("bad", children, "unique", parents.PrimaryKey(), ormtable.RestrictDelete)
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+}
+
+func TestVersionField(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType:  (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		VersionField: "f64",
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	// inserts keep the provided version
+	ex := &testpb.ExampleTable{U32: 1, I64: 2, Str: "foo"}
+	assert.NilError(t, table.Insert(ctx, ex))
+	assert.Equal(t, uint64(0), ex.F64)
+
+	// updates with a matching version increment it
+	ex.U64 = 5
+	assert.NilError(t, table.Update(ctx, ex))
+	assert.Equal(t, uint64(1), ex.F64)
+	assert.NilError(t, table.Save(ctx, ex))
+	assert.Equal(t, uint64(2), ex.F64)
+
+	stored := &testpb.ExampleTable{U32: 1, I64: 2, Str: "foo"}
+	found, err := table.Get(ctx, stored)
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, uint64(2), stored.F64)
+	assert.Equal(t, uint64(5), stored.U64)
+
+	// updates with a stale version conflict and leave the entry unchanged
+	stale := &testpb.ExampleTable{U32: 1, I64: 2, Str: "foo", U64: 6, F64: 1}
+	assert.ErrorIs(t, table.Update(ctx, stale), ormerrors.VersionConflict)
+	assert.ErrorIs(t, table.Save(ctx, stale), ormerrors.VersionConflict)
+	assert.Equal(t, uint64(1), stale.F64)
+	found, err = table.Get(ctx, stored)
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, uint64(2), stored.F64)
+	assert.Equal(t, uint64(5), stored.U64)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:  (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		VersionField: "u32",
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:  (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		VersionField: "u64",
+	})
+	assert.NilError(t, err)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:  (&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+		VersionField: "id",
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:  (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		VersionField: "missing",
+	})
+	assert.ErrorIs(t, err, ormerrors.FieldNotFound)
+}
+
+func TestCachingUniqueIndex(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.SimpleExample{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+
+	cache, err := ormtable.NewCachingUniqueIndex(table.GetUniqueIndex("unique"), 2)
+	assert.NilError(t, err)
+	backend := testkv.NewSplitMemBackend()
+	ctx := ormtable.WrapContextDefault(backend.WithWriteHooks(cache))
+	// writes which aren't observed by the cache
+	unobservedCtx := ormtable.WrapContextDefault(backend)
+	// reads which use the cache
+	queryCtx := ormtable.EnableIndexCache(ctx)
+
+	assert.NilError(t, table.InsertBatch(ctx,
+		&testpb.SimpleExample{Name: "a", Unique: "x", NotUnique: "1"},
+		&testpb.SimpleExample{Name: "b", Unique: "y", NotUnique: "1"},
+		&testpb.SimpleExample{Name: "c", Unique: "z", NotUnique: "1"},
+	))
+
+	assertGet := func(ctx context.Context, unique, expected string) {
+		t.Helper()
+		var msg testpb.SimpleExample
+		found, err := cache.Get(ctx, &msg, unique)
+		assert.NilError(t, err)
+		assert.Assert(t, found)
+		assert.Equal(t, expected, msg.NotUnique)
+	}
+
+	assertGet(queryCtx, "x", "1")
+	assertGet(queryCtx, "y", "1")
+	assert.Equal(t, 2, cache.Len())
+	found, err := cache.Get(queryCtx, &testpb.SimpleExample{}, "missing")
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+	assert.Equal(t, 2, cache.Len())
+
+	// cached messages are returned even if the store changed behind the cache
+	assert.NilError(t, table.Update(unobservedCtx, &testpb.SimpleExample{Name: "a", Unique: "x", NotUnique: "2"}))
+	assertGet(queryCtx, "x", "1")
+	assertGet(queryCtx, "z", "1")
+	assert.Equal(t, 2, cache.Len())
+
+	// the cache is bypassed unless it is enabled for the context
+	assertGet(ctx, "x", "2")
+	assert.Equal(t, 2, cache.Len())
+
+	// the least recently used entry (y) was evicted
+	assert.NilError(t, table.Update(unobservedCtx, &testpb.SimpleExample{Name: "b", Unique: "y", NotUnique: "2"}))
+	assertGet(queryCtx, "y", "2")
+
+	// observed writes invalidate cache entries
+	assert.NilError(t, table.Update(ctx, &testpb.SimpleExample{Name: "a", Unique: "x", NotUnique: "3"}))
+	assertGet(queryCtx, "x", "3")
+	assert.NilError(t, table.Delete(ctx, &testpb.SimpleExample{Name: "c"}))
+	found, err = cache.Get(queryCtx, &testpb.SimpleExample{}, "z")
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+
+	cache.Purge()
+	assert.Equal(t, 0, cache.Len())
+
+	// caching can be disabled entirely
+	cache, err = ormtable.NewCachingUniqueIndex(table.PrimaryKey(), 0)
+	assert.NilError(t, err)
+	var msg testpb.SimpleExample
+	found, err = cache.Get(queryCtx, &msg, "a")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestProjection(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	assert.NilError(t, table.InsertBatch(ctx,
+		&testpb.ExampleTable{U32: 1, I64: 2, Str: "a", U64: 3, Bz: []byte("foo"), Repeated: []uint32{1, 2}},
+		&testpb.ExampleTable{U32: 2, I64: 3, Str: "b", U64: 4, Bz: []byte("bar"), Repeated: []uint32{3}},
+	))
+
+	fields := (&testpb.ExampleTable{}).ProtoReflect().Descriptor().Fields()
+	projection := ormlist.Projection(fields.ByName("u64"), fields.ByName("repeated"))
+	expected := []*testpb.ExampleTable{
+		{U32: 1, I64: 2, Str: "a", U64: 3, Repeated: []uint32{1, 2}},
+		{U32: 2, I64: 3, Str: "b", U64: 4, Repeated: []uint32{3}},
+	}
+
+	assertProjected := func(it ormtable.Iterator, err error) {
+		t.Helper()
+		assert.NilError(t, err)
+		var msgs []*testpb.ExampleTable
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			msgs = append(msgs, msg.(*testpb.ExampleTable))
+		}
+		it.Close()
+		assert.DeepEqual(t, expected, msgs, protocmp.Transform())
+	}
+
+	// only the projected fields and the primary key are decoded
+	assertProjected(table.List(ctx, nil, projection))
+	assertProjected(table.GetIndex("str,u32").List(ctx, nil, projection))
+	assertProjected(table.GetUniqueIndex("u64,str").ListRange(ctx, []interface{}{uint64(3)}, []interface{}{uint64(4)}, projection))
+
+	// fields of other messages can't be projected
+	_, err = table.List(ctx, nil, ormlist.Projection((&testpb.SimpleExample{}).ProtoReflect().Descriptor().Fields().ByName("name")))
+	assert.ErrorIs(t, err, ormerrors.InvalidListOptions)
+}
+
+func TestPurgeExpired(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType:      (&testpb.ExampleTimestamp{}).ProtoReflect().Type(),
+		ExpiryField:      "ts",
+		NullsLastIndexes: []string{"ts"},
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	now := time.Unix(1000, 0)
+	assert.NilError(t, table.InsertBatch(ctx,
+		&testpb.ExampleTimestamp{Name: "expired", Ts: timestamppb.New(now.Add(-time.Hour))},
+		&testpb.ExampleTimestamp{Name: "no expiry"},
+		&testpb.ExampleTimestamp{Name: "later", Ts: timestamppb.New(now.Add(time.Nanosecond))},
+		&testpb.ExampleTimestamp{Name: "now", Ts: timestamppb.New(now)},
+		&testpb.ExampleTimestamp{Name: "before epoch", Ts: timestamppb.New(time.Unix(-1, 0))},
+	))
+
+	// at most limit entries are purged per call
+	purged, err := ormtable.PurgeExpired(ctx, table, now, 2)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), purged)
+
+	purged, err = ormtable.PurgeExpired(ctx, table, now, 2)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), purged)
+
+	listNames := func(index ormtable.Index) []string {
+		it, err := index.List(ctx, nil)
+		assert.NilError(t, err)
+		defer it.Close()
+		var names []string
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			names = append(names, msg.(*testpb.ExampleTimestamp).Name)
+		}
+		return names
+	}
+	assert.DeepEqual(t, []string{"no expiry", "later"}, listNames(table))
+	assert.DeepEqual(t, []string{"later", "no expiry"}, listNames(table.GetIndex("ts")))
+
+	purged, err = ormtable.PurgeExpired(ctx, table, now, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), purged)
+
+	purged, err = ormtable.PurgeExpired(ctx, table, now.Add(time.Hour), 0)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), purged)
+	assert.DeepEqual(t, []string{"no expiry"}, listNames(table))
+
+	// tables need an expiry field
+	table, err = ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTimestamp{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	_, err = ormtable.PurgeExpired(ctx, table, now, 0)
+	assert.ErrorIs(t, err, ormerrors.UnsupportedOperation)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:      (&testpb.ExampleTimestamp{}).ProtoReflect().Type(),
+		ExpiryField:      "name",
+		NullsLastIndexes: []string{"ts"},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:       (&testpb.ExampleTimestamp{}).ProtoReflect().Type(),
+		ExpiryField:       "ts",
+		DescendingIndexes: []string{"ts"},
+		NullsLastIndexes:  []string{"ts"},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	// unset expiries must sort last in the expiry index
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTimestamp{}).ProtoReflect().Type(),
+		ExpiryField: "ts",
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+}
+
+func TestIndexNormalizers(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType:      (&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+		IndexNormalizers: map[string]func(string) string{"x": strings.ToLower},
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	alice := &testpb.ExampleAutoIncrementTable{X: "Alice", Y: 1}
+	assert.NilError(t, table.Insert(ctx, alice))
+
+	// "Alice" and "alice" collide under the unique index
+	assert.ErrorIs(t, table.Insert(ctx, &testpb.ExampleAutoIncrementTable{X: "alice"}), ormerrors.UniqueKeyViolation)
+
+	// queries are normalized, and the original value is stored in the table
+	index := table.GetUniqueIndex("x")
+	for _, x := range []string{"Alice", "alice", "ALICE"} {
+		found, err := index.Has(ctx, x)
+		assert.NilError(t, err)
+		assert.Assert(t, found, x)
+
+		var msg testpb.ExampleAutoIncrementTable
+		found, err = index.Get(ctx, &msg, x)
+		assert.NilError(t, err)
+		assert.Assert(t, found, x)
+		assert.Equal(t, "Alice", msg.X)
+	}
+
+	// changing only the case keeps the same index key
+	alice.X = "ALICE"
+	assert.NilError(t, table.Update(ctx, alice))
+	count, err := index.Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), count)
+	var msg testpb.ExampleAutoIncrementTable
+	found, err := index.Get(ctx, &msg, "alice")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, "ALICE", msg.X)
+
+	// changing the normalized value re-keys the entry
+	alice.X = "Bob"
+	assert.NilError(t, table.Update(ctx, alice))
+	found, err = index.Has(ctx, "alice")
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+	found, err = index.Has(ctx, "BOB")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleAutoIncrementTable{X: "alice"}))
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:      (&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+		IndexNormalizers: map[string]func(string) string{"y": strings.ToLower},
+	})
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+
+	// string primary key values can't be normalized
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:      (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		IndexNormalizers: map[string]func(string) string{"str,u32": strings.ToLower},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+}
+
+func TestIndexEntriesFor(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		IndexFilters: map[string]func(proto.Message) bool{
+			"bz,str": func(message proto.Message) bool {
+				return len(message.(*testpb.ExampleTable).Bz) != 0
+			},
+		},
+	})
+	assert.NilError(t, err)
+
+	for _, msg := range []*testpb.ExampleTable{
+		{U32: 1, I64: 2, Str: "a", U64: 3, Bz: []byte("foo")},
+		{U32: 2, I64: 3, Str: "b", U64: 4},
+	} {
+		backend := testkv.NewSplitMemBackend()
+		ctx := ormtable.WrapContextDefault(backend)
+		assert.NilError(t, table.Insert(ctx, msg))
+
+		// the keys are the keys of the index store entries written on insert
+		var written [][]byte
+		it, err := backend.IndexStoreReader().Iterator(nil, nil)
+		assert.NilError(t, err)
+		for ; it.Valid(); it.Next() {
+			written = append(written, it.Key())
+		}
+		assert.NilError(t, it.Close())
+
+		keys, err := ormtable.IndexEntriesFor(table, msg)
+		assert.NilError(t, err)
+		sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+		assert.DeepEqual(t, written, keys)
+	}
+
+	_, err = ormtable.IndexEntriesFor(table, &testpb.SimpleExample{})
+	assert.ErrorIs(t, err, ormerrors.UnexpectedError)
+}
+
+func TestListOffset(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := uint32(0); i < 5; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, U64: uint64(i)}))
+	}
+
+	listU32s := func(opts ...ormlist.Option) []uint32 {
+		it, err := table.List(ctx, nil, opts...)
+		assert.NilError(t, err)
+		defer it.Close()
+		var u32s []uint32
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			u32s = append(u32s, msg.(*testpb.ExampleTable).U32)
+		}
+		return u32s
+	}
+
+	assert.DeepEqual(t, []uint32{2, 3, 4}, listU32s(ormlist.Offset(2)))
+	assert.DeepEqual(t, []uint32{0, 1, 2, 3, 4}, listU32s(ormlist.Offset(0)))
+	assert.DeepEqual(t, []uint32(nil), listU32s(ormlist.Offset(5)))
+	assert.DeepEqual(t, []uint32(nil), listU32s(ormlist.Offset(10)))
+
+	// the offset is applied after reversing and filtering
+	assert.DeepEqual(t, []uint32{2, 1, 0}, listU32s(ormlist.Offset(2), ormlist.Reverse()))
+	assert.DeepEqual(t, []uint32{1}, listU32s(ormlist.Reverse(), ormlist.Offset(1), ormlist.Filter(func(message proto.Message) bool {
+		return message.(*testpb.ExampleTable).U32%2 == 1
+	})))
+
+	// offsets can be combined with limits
+	assert.DeepEqual(t, []uint32{1, 2}, listU32s(ormlist.Offset(1), ormlist.DefaultLimit(2)))
+
+	_, err = table.List(ctx, nil, ormlist.Offset(1), ormlist.Cursor([]byte{1}))
+	assert.ErrorContains(t, err, "cursor or offset")
+}
+
+func TestListBytesPrefix(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	values := [][]byte{{}, {1}, {1, 2}, {1, 2, 3}, {1, 2, 4, 5, 6}, {1, 3}, {2}, {2, 1}, bytes.Repeat([]byte{1}, 200)}
+	for i, bz := range values {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: uint32(i), U64: uint64(i), Bz: bz}))
+	}
+
+	index := table.GetIndex("bz,str")
+	assert.Assert(t, index != nil)
+	listU32s := func(prefix []byte, opts ...ormlist.Option) []uint32 {
+		it, err := index.List(ctx, []interface{}{prefix}, append(opts, ormlist.BytesPrefix())...)
+		assert.NilError(t, err)
+		defer it.Close()
+		var u32s []uint32
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			u32s = append(u32s, msg.(*testpb.ExampleTable).U32)
+		}
+		return u32s
+	}
+
+	// entries are listed in index order, i.e. by length first
+	assert.DeepEqual(t, []uint32{1, 2, 5, 3, 4, 8}, listU32s([]byte{1}))
+	assert.DeepEqual(t, []uint32{2, 3, 4}, listU32s([]byte{1, 2}))
+	assert.DeepEqual(t, []uint32{4}, listU32s([]byte{1, 2, 4}))
+	assert.DeepEqual(t, []uint32{6, 7}, listU32s([]byte{2}))
+	assert.DeepEqual(t, []uint32(nil), listU32s([]byte{3}))
+	assert.DeepEqual(t, []uint32{0, 1, 6, 2, 5, 7, 3, 4, 8}, listU32s([]byte{}))
+	assert.DeepEqual(t, []uint32{8, 4, 3, 5, 2, 1}, listU32s([]byte{1}, ormlist.Reverse()))
+
+	// without BytesPrefix, the whole value is matched
+	it, err := index.List(ctx, []interface{}{[]byte{1}})
+	assert.NilError(t, err)
+	assert.Assert(t, it.Next())
+	msg, err := it.GetMessage()
+	assert.NilError(t, err)
+	assert.Equal(t, uint32(1), msg.(*testpb.ExampleTable).U32)
+	assert.Assert(t, !it.Next())
+	it.Close()
+
+	// pagination restarts from the cursor across scanned lengths
+	for _, reverse := range []bool{false, true} {
+		var u32s []uint32
+		var nextKey []byte
+		for {
+			it, err := index.List(ctx, []interface{}{[]byte{1}}, ormlist.BytesPrefix(),
+				ormlist.Paginate(&queryv1beta1.PageRequest{Key: nextKey, Limit: 2, Reverse: reverse}))
+			assert.NilError(t, err)
+			for it.Next() {
+				msg, err := it.GetMessage()
+				assert.NilError(t, err)
+				u32s = append(u32s, msg.(*testpb.ExampleTable).U32)
+			}
+			nextKey = it.PageResponse().NextKey
+			it.Close()
+			if nextKey == nil {
+				break
+			}
+		}
+		if reverse {
+			assert.DeepEqual(t, []uint32{8, 4, 3, 5, 2, 1}, u32s)
+		} else {
+			assert.DeepEqual(t, []uint32{1, 2, 5, 3, 4, 8}, u32s)
+		}
+	}
+
+	_, err = index.List(ctx, []interface{}{[]byte{1}, "abc"}, ormlist.BytesPrefix())
+	assert.ErrorContains(t, err, "not a bytes field")
+	_, err = index.ListRange(ctx, []interface{}{[]byte{1}}, []interface{}{[]byte{2}}, ormlist.BytesPrefix())
+	assert.ErrorContains(t, err, "doesn't apply to ListRange")
+}
+
+func TestListEntries(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	backend := testkv.NewSplitMemBackend()
+	ctx := ormtable.WrapContextDefault(backend)
+
+	for i := uint32(0); i < 3; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, U64: uint64(i), Str: "abc"}))
+	}
+
+	listEntries := func() []ormtable.IndexEntry {
+		it, err := ormtable.ListEntries(ctx, table.GetIndex("str,u32"), []interface{}{"abc"})
+		assert.NilError(t, err)
+		defer it.Close()
+		var entries []ormtable.IndexEntry
+		for it.Next() {
+			entries = append(entries, it.Entry())
+		}
+		return entries
+	}
+
+	entries := listEntries()
+	assert.Equal(t, 3, len(entries))
+	for i, entry := range entries {
+		assert.NilError(t, entry.Err)
+		assert.Equal(t, "abc", entry.IndexKey[0].String())
+		assert.Equal(t, uint64(i), entry.IndexKey[1].Uint())
+		assert.Equal(t, 3, len(entry.PrimaryKey))
+		assert.Equal(t, uint64(i), entry.PrimaryKey[0].Uint())
+		assert.DeepEqual(t, &testpb.ExampleTable{U32: uint32(i), U64: uint64(i), Str: "abc"}, entry.Message, protocmp.Transform())
+	}
+
+	// corrupt the primary entry of the second message
+	var keys [][]byte
+	it, err := backend.CommitmentStoreReader().Iterator(nil, nil)
+	assert.NilError(t, err)
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	assert.NilError(t, it.Close())
+	assert.Equal(t, 3, len(keys))
+	assert.NilError(t, backend.CommitmentStore().Set(keys[1], []byte{0xff}))
+
+	// decode errors are reported per entry
+	entries = listEntries()
+	assert.Equal(t, 3, len(entries))
+	assert.NilError(t, entries[0].Err)
+	assert.Assert(t, entries[1].Err != nil)
+	assert.NilError(t, entries[2].Err)
+	assert.Equal(t, uint32(2), entries[2].Message.(*testpb.ExampleTable).U32)
+}
+
+func TestTypedIterator(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	backend := testkv.NewSplitMemBackend()
+	ctx := ormtable.WrapContextDefault(backend)
+
+	for i := uint32(0); i < 3; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, U64: uint64(i), Str: "abc"}))
+	}
+
+	listU32s := func(options ...ormlist.Option) ([]uint32, ormlist.CursorT) {
+		it, err := table.List(ctx, nil, options...)
+		assert.NilError(t, err)
+		typed := ormtable.NewTypedIterator[*testpb.ExampleTable](it)
+		defer typed.Close()
+		var u32s []uint32
+		var cursor ormlist.CursorT
+		for {
+			msg, ok, err := typed.Next()
+			assert.NilError(t, err)
+			if !ok {
+				break
+			}
+			u32s = append(u32s, msg.U32)
+			cursor = typed.Cursor()
+		}
+		return u32s, cursor
+	}
+
+	u32s, _ := listU32s()
+	assert.DeepEqual(t, []uint32{0, 1, 2}, u32s)
+	u32s, cursor := listU32s(ormlist.Reverse(), ormlist.Paginate(&queryv1beta1.PageRequest{Limit: 2}))
+	assert.DeepEqual(t, []uint32{2, 1}, u32s)
+	u32s, _ = listU32s(ormlist.Reverse(), ormlist.Cursor(cursor))
+	assert.DeepEqual(t, []uint32{0}, u32s)
+
+	// decode errors are returned instead of panicking
+	it, err := backend.CommitmentStoreReader().Iterator(nil, nil)
+	assert.NilError(t, err)
+	key := it.Key()
+	assert.NilError(t, it.Close())
+	assert.NilError(t, backend.CommitmentStore().Set(key, []byte{0xff}))
+	listIt, err := table.List(ctx, nil)
+	assert.NilError(t, err)
+	typed := ormtable.NewTypedIterator[*testpb.ExampleTable](listIt)
+	defer typed.Close()
+	_, ok, err := typed.Next()
+	assert.Assert(t, err != nil)
+	assert.Assert(t, !ok)
+}
+
+func TestOrderedEnumIndex(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor: &ormv1alpha1.TableDescriptor{
+			Id:         1,
+			PrimaryKey: &ormv1alpha1.PrimaryKeyDescriptor{Fields: "u32,i64,str"},
+			Index:      []*ormv1alpha1.SecondaryIndexDescriptor{{Id: 1, Fields: "e"}},
+		},
+		OrderedEnumIndexes: []string{"e"},
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	idx := table.GetIndex("e")
+	assert.Assert(t, idx != nil)
+
+	// the enum has gaps, a negative value, and values which aren't declared
+	for i, e := range []testpb.Enum{
+		testpb.Enum_ENUM_FIVE, testpb.Enum_ENUM_NEG_THREE, testpb.Enum(4), testpb.Enum_ENUM_ONE,
+		testpb.Enum_ENUM_UNSPECIFIED, testpb.Enum(-10), testpb.Enum_ENUM_TWO,
+	} {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: uint32(i), E: e}))
+	}
+
+	listEnums := func(it ormtable.Iterator, err error) []testpb.Enum {
+		assert.NilError(t, err)
+		defer it.Close()
+		var enums []testpb.Enum
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			enums = append(enums, msg.(*testpb.ExampleTable).E)
+		}
+		return enums
+	}
+
+	// iteration follows the numeric order of the enum values
+	assert.DeepEqual(t, []testpb.Enum{-10, -3, 0, 1, 2, 4, 5}, listEnums(idx.List(ctx, nil)))
+	assert.DeepEqual(t, []testpb.Enum{5, 4, 2, 1, 0, -3, -10}, listEnums(idx.List(ctx, nil, ormlist.Reverse())))
+	assert.DeepEqual(t, []testpb.Enum{-3, 0, 1}, listEnums(idx.ListRange(ctx,
+		[]interface{}{testpb.Enum_ENUM_NEG_THREE}, []interface{}{testpb.Enum_ENUM_ONE})))
+	assert.DeepEqual(t, []testpb.Enum{2, 4}, listEnums(idx.ListRange(ctx,
+		[]interface{}{testpb.Enum_ENUM_TWO}, []interface{}{testpb.Enum_ENUM_FIVE}, ormlist.ExclusiveEnd())))
+	assert.DeepEqual(t, []testpb.Enum{1}, listEnums(idx.List(ctx, []interface{}{testpb.Enum_ENUM_ONE})))
+
+	// enum indexes aren't ordered by default
+	table, err = ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor: &ormv1alpha1.TableDescriptor{
+			Id:         1,
+			PrimaryKey: &ormv1alpha1.PrimaryKeyDescriptor{Fields: "u32,i64,str"},
+			Index:      []*ormv1alpha1.SecondaryIndexDescriptor{{Id: 1, Fields: "e"}},
+		},
+	})
+	assert.NilError(t, err)
+	_, err = table.GetIndex("e").ListRange(ctx,
+		[]interface{}{testpb.Enum_ENUM_NEG_THREE}, []interface{}{testpb.Enum_ENUM_ONE})
+	assert.ErrorIs(t, err, ormerrors.InvalidRangeIterationKeys)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor: &ormv1alpha1.TableDescriptor{
+			Id:         1,
+			PrimaryKey: &ormv1alpha1.PrimaryKeyDescriptor{Fields: "u32,i64,str"},
+		},
+		OrderedEnumIndexes: []string{"e"},
+	})
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+}
+
+func TestIndexHashedFields(t *testing.T) {
+	tableDesc := &ormv1alpha1.TableDescriptor{
+		Id:         1,
+		PrimaryKey: &ormv1alpha1.PrimaryKeyDescriptor{Fields: "u32,i64,str"},
+		Index: []*ormv1alpha1.SecondaryIndexDescriptor{
+			{Id: 1, Fields: "bz", Unique: true},
+			{Id: 2, Fields: "bz,u64"},
+		},
+	}
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType:       (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor:   tableDesc,
+		IndexHashedFields: map[string][]string{"bz": {"bz"}, "bz,u64": {"bz"}},
+	})
+	assert.NilError(t, err)
+	backend := testkv.NewSplitMemBackend()
+	ctx := ormtable.WrapContextDefault(backend)
+
+	// values longer than 255 bytes can't be stored in keys unless hashed
+	payload1 := bytes.Repeat([]byte{1}, 1000)
+	payload2 := bytes.Repeat([]byte{2}, 500)
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 1, U64: 10, Bz: payload1}))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 2, U64: 20, Bz: payload2}))
+	err = table.Insert(ctx, &testpb.ExampleTable{U32: 3, Bz: payload1})
+	assert.ErrorIs(t, err, ormerrors.UniqueKeyViolation)
+
+	// index keys are small and of fixed length
+	it, err := backend.IndexStoreReader().Iterator(nil, nil)
+	assert.NilError(t, err)
+	for ; it.Valid(); it.Next() {
+		assert.Assert(t, len(it.Key()) < 64)
+	}
+	assert.NilError(t, it.Close())
+
+	// lookups hash the query values
+	uniqueIndex := table.GetUniqueIndex("bz")
+	assert.Assert(t, uniqueIndex != nil)
+	var msg testpb.ExampleTable
+	found, err := uniqueIndex.Get(ctx, &msg, payload2)
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, uint32(2), msg.U32)
+	has, err := uniqueIndex.Has(ctx, payload1)
+	assert.NilError(t, err)
+	assert.Assert(t, has)
+	has, err = uniqueIndex.Has(ctx, []byte("unknown"))
+	assert.NilError(t, err)
+	assert.Assert(t, !has)
+
+	index := table.GetIndex("bz,u64")
+	assert.Assert(t, index != nil)
+	it2, err := index.List(ctx, []interface{}{payload1})
+	assert.NilError(t, err)
+	assert.Assert(t, it2.Next())
+	listed, err := it2.GetMessage()
+	assert.NilError(t, err)
+	assert.Equal(t, uint32(1), listed.(*testpb.ExampleTable).U32)
+	assert.Assert(t, !it2.Next())
+	it2.Close()
+
+	// hashed fields can only be used for equality lookups
+	_, err = index.ListRange(ctx, []interface{}{payload1}, []interface{}{payload2})
+	assert.ErrorIs(t, err, ormerrors.InvalidRangeIterationKeys)
+	_, err = index.ListRange(ctx, []interface{}{payload1, uint64(0)}, []interface{}{payload1, uint64(100)})
+	assert.NilError(t, err)
+
+	// updates move the entries to the hashes of the new values
+	assert.NilError(t, table.Update(ctx, &testpb.ExampleTable{U32: 1, U64: 10, Bz: []byte("new")}))
+	has, err = uniqueIndex.Has(ctx, payload1)
+	assert.NilError(t, err)
+	assert.Assert(t, !has)
+	has, err = uniqueIndex.Has(ctx, []byte("new"))
+	assert.NilError(t, err)
+	assert.Assert(t, has)
+
+	// the hash function can be overridden, here with one that always collides
+	table, err = ormtable.Build(ormtable.Options{
+		MessageType:       (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor:   tableDesc,
+		IndexHashedFields: map[string][]string{"bz": {"bz"}},
+		KeyHash:           func([]byte) []byte { return []byte{0} },
+	})
+	assert.NilError(t, err)
+	ctx = ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 1, Bz: []byte("a")}))
+	err = table.Insert(ctx, &testpb.ExampleTable{U32: 2, Bz: []byte("b")})
+	assert.ErrorIs(t, err, ormerrors.UniqueKeyViolation)
+
+	invalid := []struct {
+		hashedFields map[string][]string
+		expErr       string
+	}{
+		{map[string][]string{"bz,u64": {"u64"}}, "can't hash non-bytes field"},
+		{map[string][]string{"bz": {"u64"}}, "isn't a field of index"},
+		{map[string][]string{"u64": {"u64"}}, "can't hash fields of index"},
+	}
+	for _, tc := range invalid {
+		_, err = ormtable.Build(ormtable.Options{
+			MessageType:       (&testpb.ExampleTable{}).ProtoReflect().Type(),
+			TableDescriptor:   tableDesc,
+			IndexHashedFields: tc.hashedFields,
+		})
+		assert.ErrorContains(t, err, tc.expErr)
+	}
+
+	// primary key fields can't be hashed
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor: &ormv1alpha1.TableDescriptor{
+			Id:         1,
+			PrimaryKey: &ormv1alpha1.PrimaryKeyDescriptor{Fields: "u32,bz"},
+			Index:      []*ormv1alpha1.SecondaryIndexDescriptor{{Id: 1, Fields: "u64,bz"}},
+		},
+		IndexHashedFields: map[string][]string{"u64,bz": {"bz"}},
+	})
+	assert.ErrorContains(t, err, "can't hash the primary key field")
+}
+
+func TestIndexDecimalFields(t *testing.T) {
+	tableDesc := &ormv1alpha1.TableDescriptor{
+		Id:         1,
+		PrimaryKey: &ormv1alpha1.PrimaryKeyDescriptor{Fields: "u32"},
+		Index: []*ormv1alpha1.SecondaryIndexDescriptor{
+			{Id: 1, Fields: "str"},
+			{Id: 2, Fields: "str,u64", Unique: true},
+		},
+	}
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType:        (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor:    tableDesc,
+		IndexDecimalFields: map[string][]string{"str": {"str"}, "str,u64": {"str"}},
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	// inserted out of order, lexicographic order would be -1.5, 0, 10, 10.25, 2
+	for i, str := range []string{"10", "-1.5", "10.25", "0", "2"} {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: uint32(i), Str: str}))
+	}
+
+	listStrs := func(it ormtable.Iterator) []string {
+		defer it.Close()
+		var strs []string
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			strs = append(strs, msg.(*testpb.ExampleTable).Str)
+		}
+		return strs
+	}
+
+	for _, fields := range []string{"str", "str,u64"} {
+		index := table.GetIndex(fields)
+		it, err := index.List(ctx, nil)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"-1.5", "0", "2", "10", "10.25"}, listStrs(it))
+
+		it, err = index.List(ctx, nil, ormlist.Reverse())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"10.25", "10", "2", "0", "-1.5"}, listStrs(it))
+
+		// range queries compare the values numerically, regardless of scale
+		it, err = index.ListRange(ctx, []interface{}{"0.000"}, []interface{}{"10.0"})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"0", "2", "10"}, listStrs(it))
+	}
+
+	// equal decimals with different scales conflict in unique indexes
+	err = table.Insert(ctx, &testpb.ExampleTable{U32: 10, Str: "2.00"})
+	assert.ErrorIs(t, err, ormerrors.UniqueKeyViolation)
+
+	err = table.Insert(ctx, &testpb.ExampleTable{U32: 10, Str: "abc"})
+	assert.ErrorIs(t, err, ormerrors.InvalidDecimal)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:        (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor:    tableDesc,
+		IndexDecimalFields: map[string][]string{"str": {"u64"}},
+	})
+	assert.ErrorIs(t, err, ormerrors.FieldNotFound)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:        (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor:    tableDesc,
+		IndexDecimalFields: map[string][]string{"str,u64": {"u64"}},
+	})
+	assert.ErrorIs(t, err, ormerrors.UnsupportedKeyField)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:        (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		IndexDecimalFields: map[string][]string{"str,u32": {"str"}},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:        (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		TableDescriptor:    tableDesc,
+		IndexDecimalFields: map[string][]string{"u64": {"u64"}},
+	})
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+}
+
+func TestWalk(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := uint32(0); i < 5; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, U64: uint64(i)}))
+	}
+
+	walkU32s := func(stopAt uint32, fnErr error, opts ...ormlist.Option) ([]uint32, error) {
+		var u32s []uint32
+		err := ormtable.Walk(ctx, table, nil, func(message proto.Message) (bool, error) {
+			u32 := message.(*testpb.ExampleTable).U32
+			if fnErr != nil && u32 == stopAt {
+				return false, fnErr
+			}
+			u32s = append(u32s, u32)
+			return u32 == stopAt, nil
+		}, opts...)
+		return u32s, err
+	}
+
+	u32s, err := walkU32s(10, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []uint32{0, 1, 2, 3, 4}, u32s)
+
+	// stop after the current entry
+	u32s, err = walkU32s(2, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []uint32{0, 1, 2}, u32s)
+
+	// options are applied
+	u32s, err = walkU32s(2, nil, ormlist.Reverse())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []uint32{4, 3, 2}, u32s)
+
+	it, err := table.List(ctx, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, it.Next())
+	cursor := it.Cursor()
+	it.Close()
+	u32s, err = walkU32s(10, nil, ormlist.Cursor(cursor))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []uint32{1, 2, 3, 4}, u32s)
+
+	// callback errors are returned unchanged
+	fnErr := fmt.Errorf("walk error")
+	u32s, err = walkU32s(3, fnErr)
+	assert.Equal(t, fnErr, err)
+	assert.DeepEqual(t, []uint32{0, 1, 2}, u32s)
+
+	_, err = walkU32s(10, nil, ormlist.Cursor([]byte{1}), ormlist.Offset(1))
+	assert.ErrorContains(t, err, "cursor or offset")
+}
+
+func TestWalkGroups(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	index := table.GetIndex("str,u32")
+
+	for i, str := range []string{"c", "a", "b", "c", "a", "c"} {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: uint32(i), U64: uint64(i), Str: str}))
+	}
+
+	type group struct {
+		Key  string
+		U32s []uint32
+	}
+	walkGroups := func(prefixKey []interface{}, groupByFields int, fnErr error, opts ...ormlist.Option) ([]group, error) {
+		var groups []group
+		err := ormtable.WalkGroups(ctx, index, prefixKey, groupByFields, func(groupKey []protoreflect.Value, entries []ormtable.IndexEntry) error {
+			assert.Equal(t, groupByFields, len(groupKey))
+			g := group{Key: groupKey[0].String()}
+			for _, entry := range entries {
+				msg := entry.Message.(*testpb.ExampleTable)
+				assert.Equal(t, g.Key, msg.Str)
+				g.U32s = append(g.U32s, msg.U32)
+			}
+			groups = append(groups, g)
+			if fnErr != nil && g.Key == "b" {
+				return fnErr
+			}
+			return nil
+		}, opts...)
+		return groups, err
+	}
+
+	// the last group is passed to fn too
+	groups, err := walkGroups(nil, 1, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []group{{"a", []uint32{1, 4}}, {"b", []uint32{2}}, {"c", []uint32{0, 3, 5}}}, groups)
+
+	groups, err = walkGroups(nil, 1, nil, ormlist.Reverse())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []group{{"c", []uint32{5, 3, 0}}, {"b", []uint32{2}}, {"a", []uint32{4, 1}}}, groups)
+
+	groups, err = walkGroups([]interface{}{"c"}, 1, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []group{{"c", []uint32{0, 3, 5}}}, groups)
+
+	// grouping by all the index fields yields one group per entry
+	groups, err = walkGroups(nil, 2, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, 6, len(groups))
+
+	groups, err = walkGroups([]interface{}{"d"}, 1, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(groups))
+
+	// callback errors are returned unchanged and stop the iteration
+	fnErr := fmt.Errorf("walk error")
+	groups, err = walkGroups(nil, 1, fnErr)
+	assert.Equal(t, fnErr, err)
+	assert.Equal(t, 2, len(groups))
+
+	_, err = walkGroups(nil, 0, nil)
+	assert.ErrorIs(t, err, ormerrors.IndexOutOfBounds)
+	_, err = walkGroups(nil, 3, nil)
+	assert.ErrorIs(t, err, ormerrors.IndexOutOfBounds)
+}
+
+var errInsertRejected = errors.New("insert rejected")
+
+// rejectingValidateHooks is an ormtable.ValidateHooks rejecting inserts with
+// errInsertRejected until accept is set.
+type rejectingValidateHooks struct {
+	accept bool
+}
+
+func (r *rejectingValidateHooks) ValidateInsert(context.Context, proto.Message) error {
+	if !r.accept {
+		return errInsertRejected
+	}
+	return nil
+}
+
+func (r *rejectingValidateHooks) ValidateUpdate(context.Context, proto.Message, proto.Message) error {
+	return nil
+}
+
+func (r *rejectingValidateHooks) ValidateDelete(context.Context, proto.Message) error {
+	return nil
+}
+
+func TestInsertionOrder(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType:         (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		InsertionOrderField: "u64",
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for _, u32 := range []uint32{3, 1, 2} {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: u32}))
+	}
+	assert.NilError(t, table.InsertBatch(ctx,
+		&testpb.ExampleTable{U32: 5},
+		&testpb.ExampleTable{U32: 4},
+	))
+
+	listU32s := func(ctx context.Context, opts ...ormlist.Option) []uint32 {
+		it, err := table.GetIndex("u64,str").List(ctx, nil, opts...)
+		assert.NilError(t, err)
+		defer it.Close()
+		var u32s []uint32
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			u32s = append(u32s, msg.(*testpb.ExampleTable).U32)
+		}
+		return u32s
+	}
+	assert.DeepEqual(t, []uint32{3, 1, 2, 5, 4}, listU32s(ctx))
+	assert.DeepEqual(t, []uint32{4, 5, 2, 1, 3}, listU32s(ctx, ormlist.Reverse()))
+
+	// updates keep the insertion position
+	msg := &testpb.ExampleTable{U32: 3}
+	found, err := table.Get(ctx, msg)
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, uint64(1), msg.U64)
+	msg.U64 = 100
+	msg.Bz = []byte("updated")
+	assert.NilError(t, table.Update(ctx, msg))
+	assert.Equal(t, uint64(1), msg.U64)
+	assert.DeepEqual(t, []uint32{3, 1, 2, 5, 4}, listU32s(ctx))
+
+	// positions of deleted entries aren't reused
+	assert.NilError(t, table.Delete(ctx, &testpb.ExampleTable{U32: 4}))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 6}))
+	msg = &testpb.ExampleTable{U32: 6}
+	found, err = table.Get(ctx, msg)
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, uint64(6), msg.U64)
+	assert.DeepEqual(t, []uint32{3, 1, 2, 5, 6}, listU32s(ctx))
+
+	// imports reproduce the order and continue the sequence
+	buf := &bytes.Buffer{}
+	assert.NilError(t, table.ExportJSON(ctx, buf))
+	ctx2 := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	assert.NilError(t, table.ImportJSON(ctx2, bytes.NewReader(buf.Bytes())))
+	assert.DeepEqual(t, []uint32{3, 1, 2, 5, 6}, listU32s(ctx2))
+	assert.NilError(t, table.Insert(ctx2, &testpb.ExampleTable{U32: 0}))
+	assert.DeepEqual(t, []uint32{3, 1, 2, 5, 6, 0}, listU32s(ctx2))
+	buf = &bytes.Buffer{}
+	assert.NilError(t, ormtable.ExportTable(ctx, table, buf))
+	ctx3 := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	assert.NilError(t, ormtable.ImportTable(ctx3, table, bytes.NewReader(buf.Bytes())))
+	assert.DeepEqual(t, []uint32{3, 1, 2, 5, 6}, listU32s(ctx3))
+
+	// regular inserts can't preset the position
+	err = table.Insert(ctx, &testpb.ExampleTable{U32: 7, U64: 100})
+	assert.ErrorIs(t, err, ormerrors.InsertionPositionAlreadySet)
+	err = table.Save(ctx, &testpb.ExampleTable{U32: 7, U64: 100})
+	assert.ErrorIs(t, err, ormerrors.InsertionPositionAlreadySet)
+	first, second := &testpb.ExampleTable{U32: 7}, &testpb.ExampleTable{U32: 8, U64: 100}
+	err = table.InsertBatch(ctx, first, second)
+	assert.ErrorIs(t, err, ormerrors.InsertionPositionAlreadySet)
+	assert.Equal(t, uint64(0), first.U64)
+	assert.Equal(t, uint64(100), second.U64)
+	second.U64 = 0
+	assert.NilError(t, table.InsertBatch(ctx, first, second))
+	assert.Equal(t, uint64(7), first.U64)
+	assert.Equal(t, uint64(8), second.U64)
+	assert.DeepEqual(t, []uint32{3, 1, 2, 5, 6, 7, 8}, listU32s(ctx))
+
+	// failed inserts leave the message without a position, so it can be retried
+	rejecting := &rejectingValidateHooks{}
+	ctx4 := ormtable.WrapContextDefault(testkv.NewSplitMemBackend().WithValidateHooks(rejecting))
+	msg = &testpb.ExampleTable{U32: 9}
+	err = table.Insert(ctx4, msg)
+	assert.ErrorIs(t, err, errInsertRejected)
+	assert.Equal(t, uint64(0), msg.U64)
+	rejecting.accept = true
+	assert.NilError(t, table.Insert(ctx4, msg))
+	assert.Equal(t, uint64(1), msg.U64)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:         (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		InsertionOrderField: "f64",
+	})
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:         (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		InsertionOrderField: "u64",
+		DescendingIndexes:   []string{"u64,str"},
+	})
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:         (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		InsertionOrderField: "str",
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:         (&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+		InsertionOrderField: "id",
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:         (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		InsertionOrderField: "missing",
+	})
+	assert.ErrorIs(t, err, ormerrors.FieldNotFound)
+}
+
+func TestIndexMaxKeyLen(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		IndexMaxKeyLen: map[string]int{
+			"str,u32": 32,
+			"u64,str": 0,
+		},
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	longStr := strings.Repeat("a", 64)
+
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 1, Str: "short"}))
+	err = table.Insert(ctx, &testpb.ExampleTable{U32: 2, Str: longStr})
+	assert.ErrorIs(t, err, ormerrors.KeyTooLong)
+	assert.ErrorContains(t, err, "max 32")
+	found, err := table.Has(ctx, &testpb.ExampleTable{U32: 2, Str: longStr})
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+
+	// indexes without a limit accept long keys
+	msg := &testpb.ExampleTable{U32: 1, Str: "short", Bz: []byte(longStr)}
+	assert.NilError(t, table.Update(ctx, msg))
+
+	// updates are checked too
+	table2, err := ormtable.Build(ormtable.Options{
+		MessageType:    (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		IndexMaxKeyLen: map[string]int{"bz,str": 32},
+	})
+	assert.NilError(t, err)
+	err = table2.Update(ctx, &testpb.ExampleTable{U32: 1, Str: "short", Bz: []byte(longStr + longStr)})
+	assert.ErrorIs(t, err, ormerrors.KeyTooLong)
+	found, err = table2.Get(ctx, msg)
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.DeepEqual(t, []byte(longStr), msg.Bz)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:    (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		IndexMaxKeyLen: map[string]int{"u64": 32},
+	})
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:    (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		IndexMaxKeyLen: map[string]int{"str,u32": -1},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+}
+
+func TestVerifyIndex(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType:    (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		IndexMaxKeyLen: map[string]int{"u64,str": 64},
+	})
+	assert.NilError(t, err)
+	commitmentStore, indexStore := dbm.NewMemDB(), dbm.NewMemDB()
+	ctx := ormtable.WrapContextDefault(ormtable.NewBackend(ormtable.BackendOptions{
+		CommitmentStore: commitmentStore,
+		IndexStore:      indexStore,
+	}))
+	// writes through corruptCtx don't update the indexes
+	corruptCtx := ormtable.WrapContextDefault(ormtable.NewBackend(ormtable.BackendOptions{
+		CommitmentStore: commitmentStore,
+		IndexStore:      dbm.NewMemDB(),
+	}))
+
+	msg1 := &testpb.ExampleTable{U32: 1, I64: 1, Str: "a", U64: 1}
+	msg2 := &testpb.ExampleTable{U32: 2, I64: 1, Str: "b", U64: 2}
+	msg3 := &testpb.ExampleTable{U32: 3, I64: 1, Str: "c", U64: 3}
+	assert.NilError(t, table.Insert(ctx, msg1))
+	assert.NilError(t, table.Insert(ctx, msg2))
+
+	report, err := ormtable.VerifyIndex(ctx, table, "u64,str")
+	assert.NilError(t, err)
+	assert.Assert(t, report.IsValid())
+
+	// a missing entry
+	assert.NilError(t, table.Insert(corruptCtx, msg3))
+	// an orphaned entry
+	assert.NilError(t, table.Delete(corruptCtx, msg2))
+
+	// the keys of the unique index come first, see IndexEntriesFor
+	indexKey := func(message proto.Message) []byte {
+		keys, err := ormtable.IndexEntriesFor(table, message)
+		assert.NilError(t, err)
+		return keys[0]
+	}
+
+	report, err = ormtable.VerifyIndex(ctx, table, "u64,str")
+	assert.NilError(t, err)
+	assert.Assert(t, !report.IsValid())
+	assert.DeepEqual(t, [][]byte{indexKey(msg3)}, report.Missing)
+	assert.DeepEqual(t, [][]byte{indexKey(msg2)}, report.Orphaned)
+
+	// other indexes are reported too
+	report, err = ormtable.VerifyIndex(ctx, table, "str,u32")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(report.Missing))
+	assert.Equal(t, 1, len(report.Orphaned))
+
+	// nothing is written
+	found, err := table.GetUniqueIndex("u64,str").Has(ctx, uint64(2), "b")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+
+	assert.NilError(t, ormtable.RebuildIndex(ctx, table, "u64,str"))
+	report, err = ormtable.VerifyIndex(ctx, table, "u64,str")
+	assert.NilError(t, err)
+	assert.Assert(t, report.IsValid())
+
+	_, err = ormtable.VerifyIndex(ctx, table, "u32,i64,str")
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+	_, err = ormtable.VerifyIndex(ctx, table, "bz")
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+}
+
+func TestSkipUnsetUniqueIndexes(t *testing.T) {
+	newTable := func(skipUnset bool) ormtable.AutoIncrementTable {
+		options := ormtable.Options{
+			MessageType: (&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+		}
+		if skipUnset {
+			options.SkipUnsetUniqueIndexes = []string{"x"}
+		}
+		table, err := ormtable.Build(options)
+		assert.NilError(t, err)
+		return table.(ormtable.AutoIncrementTable)
+	}
+
+	// by default, unset values collide
+	table := newTable(false)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleAutoIncrementTable{Y: 1}))
+	err := table.Insert(ctx, &testpb.ExampleAutoIncrementTable{Y: 2})
+	assert.ErrorIs(t, err, ormerrors.UniqueKeyViolation)
+
+	table = newTable(true)
+	ctx = ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	assert.NilError(t, table.InsertBatch(ctx,
+		&testpb.ExampleAutoIncrementTable{Y: 1},
+		&testpb.ExampleAutoIncrementTable{Y: 2},
+		&testpb.ExampleAutoIncrementTable{X: "foo", Y: 3},
+	))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleAutoIncrementTable{Y: 4}))
+	count, err := table.Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(4), count)
+
+	// set values still collide
+	err = table.Insert(ctx, &testpb.ExampleAutoIncrementTable{X: "foo", Y: 5})
+	assert.ErrorIs(t, err, ormerrors.UniqueKeyViolation)
+
+	// entries with unset values aren't indexed
+	uniqueIdx := table.GetUniqueIndex("x")
+	found, err := uniqueIdx.Has(ctx, "")
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+	idxCount, err := uniqueIdx.Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), idxCount)
+
+	// updates setting and clearing values maintain the index
+	assert.NilError(t, table.Update(ctx, &testpb.ExampleAutoIncrementTable{Id: 1, X: "bar", Y: 1}))
+	err = table.Update(ctx, &testpb.ExampleAutoIncrementTable{Id: 2, X: "bar", Y: 2})
+	assert.ErrorIs(t, err, ormerrors.UniqueKeyViolation)
+	assert.NilError(t, table.Update(ctx, &testpb.ExampleAutoIncrementTable{Id: 3, Y: 3}))
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleAutoIncrementTable{X: "foo", Y: 6}))
+	var msg testpb.ExampleAutoIncrementTable
+	found, err = uniqueIdx.Get(ctx, &msg, "bar")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, uint64(1), msg.Id)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:            (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		SkipUnsetUniqueIndexes: []string{"str,u32"},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:            (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		SkipUnsetUniqueIndexes: []string{"u64"},
+	})
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+}
+
+func TestStream(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := uint32(0); i < 10; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, U64: uint64(i)}))
+	}
+
+	// a complete scan
+	entries, errs := ormtable.Stream(ctx, table, nil, ormlist.Reverse())
+	var u32s []uint32
+	for entry := range entries {
+		assert.NilError(t, entry.Err)
+		u32s = append(u32s, entry.Message.(*testpb.ExampleTable).U32)
+	}
+	assert.NilError(t, <-errs)
+	assert.DeepEqual(t, []uint32{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, u32s)
+
+	// cancelling mid-stream
+	cancelCtx, cancel := context.WithCancel(ctx)
+	entries, errs = ormtable.Stream(cancelCtx, table.GetIndex("u64,str"), nil)
+	for i := uint64(0); i < 3; i++ {
+		entry := <-entries
+		assert.Equal(t, i, entry.Message.(*testpb.ExampleTable).U64)
+	}
+	cancel()
+	// at most one more entry could be sent before the cancellation was seen
+	received := 0
+	for range entries {
+		received++
+	}
+	assert.Assert(t, received <= 1)
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	_, ok := <-errs
+	assert.Assert(t, !ok)
+
+	// errors opening the iterator are reported
+	entries, errs = ormtable.Stream(ctx, table, nil, ormlist.Cursor([]byte{1}), ormlist.Offset(1))
+	_, ok = <-entries
+	assert.Assert(t, !ok)
+	assert.ErrorContains(t, <-errs, "cursor or offset")
+}
+
+func TestGetOrCreate(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleAutoIncrementTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	uniqueIdx := table.GetUniqueIndex("x")
+
+	calls := 0
+	create := func(x string, y int32) func() proto.Message {
+		return func() proto.Message {
+			calls++
+			return &testpb.ExampleAutoIncrementTable{X: x, Y: y}
+		}
+	}
+
+	var msg testpb.ExampleAutoIncrementTable
+	created, err := uniqueIdx.GetOrCreate(ctx, &msg, create("foo", 1), "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, created)
+	assert.Equal(t, 1, calls)
+	// the auto-increment ID assigned on insert is set
+	assert.Equal(t, uint64(1), msg.Id)
+	assert.Equal(t, int32(1), msg.Y)
+
+	// the existing message is retrieved
+	msg.Reset()
+	created, err = uniqueIdx.GetOrCreate(ctx, &msg, create("foo", 2), "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, !created)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, uint64(1), msg.Id)
+	assert.Equal(t, int32(1), msg.Y)
+
+	// the created message must have the key values
+	_, err = uniqueIdx.GetOrCreate(ctx, &msg, create("bar", 3), "baz")
+	assert.ErrorContains(t, err, "doesn't have the key values")
+	count, err := table.Count(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	// secondary indexes are updated when creating with the primary key
+	exampleTable, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	var example testpb.ExampleTable
+	created, err = exampleTable.GetUniqueIndex("u32,i64,str").GetOrCreate(ctx, &example, func() proto.Message {
+		return &testpb.ExampleTable{U32: 1, I64: 2, Str: "a", U64: 3}
+	}, uint32(1), int64(2), "a")
+	assert.NilError(t, err)
+	assert.Assert(t, created)
+	found, err := exampleTable.GetUniqueIndex("u64,str").Has(ctx, uint64(3), "a")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	idxCount, err := exampleTable.GetIndex("str,u32").Count(ctx, "a")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), idxCount)
+}
+
+// checkAggregateOps runs random saves and deletes of ExampleTable entries on
+// table, which must have an aggregate with the group fields str,b, and calls
+// check with the remaining entries whose values start with prefixKey, for
+// each prefix of these fields.
+func checkAggregateOps(t *testing.T, table ormtable.Table, check func(t *rapid.T, ctx context.Context, entries []*testpb.ExampleTable, prefixKey ...interface{})) {
+	type modelKey struct {
+		u32 uint32
+		str string
+	}
+
+	rapid.Check(t, func(t *rapid.T) {
+		backend := testkv.NewSplitMemBackend()
+		ctx := ormtable.WrapContextDefault(backend)
+		model := map[modelKey]*testpb.ExampleTable{}
+		strs := []string{"a", "b", "c"}
+
+		for i := rapid.IntRange(1, 50).Draw(t, "ops").(int); i > 0; i-- {
+			key := modelKey{
+				u32: rapid.Uint32Range(0, 5).Draw(t, "u32").(uint32),
+				str: rapid.SampledFrom(strs).Draw(t, "str").(string),
+			}
+			switch rapid.IntRange(0, 3).Draw(t, "op").(int) {
+			case 0, 1:
+				msg := &testpb.ExampleTable{
+					U32: key.u32,
+					Str: key.str,
+					U64: uint64(key.u32),
+					B:   rapid.Bool().Draw(t, "b").(bool),
+					I32: rapid.Int32Range(-100, 100).Draw(t, "i32").(int32),
+				}
+				assert.NilError(t, table.Save(ctx, msg))
+				model[key] = msg
+			case 2:
+				assert.NilError(t, table.Delete(ctx, &testpb.ExampleTable{U32: key.u32, Str: key.str}))
+				delete(model, key)
+			case 3:
+				// deletes several entries in a single batch
+				assert.NilError(t, table.GetIndex("str,u32").DeleteBy(ctx, key.str))
+				for k := range model {
+					if k.str == key.str {
+						delete(model, k)
+					}
+				}
+			}
+		}
+
+		entries := func(filter func(msg *testpb.ExampleTable) bool) []*testpb.ExampleTable {
+			var res []*testpb.ExampleTable
+			for _, msg := range model {
+				if filter(msg) {
+					res = append(res, msg)
+				}
+			}
+			return res
+		}
+
+		check(t, ctx, entries(func(*testpb.ExampleTable) bool { return true }))
+		for _, str := range strs {
+			check(t, ctx, entries(func(msg *testpb.ExampleTable) bool { return msg.Str == str }), str)
+			for _, b := range []bool{false, true} {
+				check(t, ctx, entries(func(msg *testpb.ExampleTable) bool { return msg.Str == str && msg.B == b }), str, b)
+			}
+		}
+
+		checkEncodeDecodeEntries(t, table, backend.IndexStoreReader())
+	})
+}
+
+func TestSumAggregates(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType:   (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		SumAggregates: map[string]string{"str,b": "i32"},
+	})
+	assert.NilError(t, err)
+
+	checkAggregateOps(t, table, func(t *rapid.T, ctx context.Context, entries []*testpb.ExampleTable, prefixKey ...interface{}) {
+		var expSum int64
+		for _, msg := range entries {
+			expSum += int64(msg.I32)
+		}
+
+		sum, err := ormtable.Sum(ctx, table, "str,b", prefixKey...)
+		assert.NilError(t, err)
+		assert.Equal(t, expSum, sum)
+	})
+
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	_, err = ormtable.Sum(ctx, table, "str")
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:   (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		SumAggregates: map[string]string{"str": "u64"},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:   (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		SumAggregates: map[string]string{"str": "missing"},
+	})
+	assert.ErrorIs(t, err, ormerrors.FieldNotFound)
+}
+
+func TestMinMaxAggregates(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType:      (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		MinMaxAggregates: map[string]string{"str,b": "i32"},
+	})
+	assert.NilError(t, err)
+
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+	_, _, found, err := ormtable.MinMax(ctx, table, "str,b")
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+
+	for i, i32 := range []int32{5, -3, 7, 7, 0} {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: uint32(i), Str: "a", U64: uint64(i), I32: i32}))
+	}
+	assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: 10, Str: "b", U64: 10, I32: 100}))
+
+	assertMinMax := func(expMin, expMax int64, prefixKey ...interface{}) {
+		min, max, found, err := ormtable.MinMax(ctx, table, "str,b", prefixKey...)
+		assert.NilError(t, err)
+		assert.Assert(t, found)
+		assert.Equal(t, expMin, min)
+		assert.Equal(t, expMax, max)
+	}
+	assertMinMax(-3, 100)
+	assertMinMax(-3, 7, "a")
+
+	// deleting the minimum finds the next one
+	assert.NilError(t, table.Delete(ctx, &testpb.ExampleTable{U32: 1, Str: "a"}))
+	assertMinMax(0, 7, "a")
+
+	// the maximum is kept while another entry has the same value
+	assert.NilError(t, table.Delete(ctx, &testpb.ExampleTable{U32: 2, Str: "a"}))
+	assertMinMax(0, 7, "a")
+	assert.NilError(t, table.Delete(ctx, &testpb.ExampleTable{U32: 3, Str: "a"}))
+	assertMinMax(0, 5, "a")
+
+	// updating the maximum away finds the next one
+	assert.NilError(t, table.Update(ctx, &testpb.ExampleTable{U32: 10, Str: "b", U64: 10, I32: -10}))
+	assertMinMax(-10, 5)
+	assertMinMax(-10, -10, "b", false)
+
+	assert.NilError(t, table.Delete(ctx, &testpb.ExampleTable{U32: 10, Str: "b"}))
+	_, _, found, err = ormtable.MinMax(ctx, table, "str,b", "b")
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+	assertMinMax(0, 5)
+
+	_, _, _, err = ormtable.MinMax(ctx, table, "str,b", "a", false, int32(1))
+	assert.ErrorIs(t, err, ormerrors.IndexOutOfBounds)
+	_, _, _, err = ormtable.MinMax(ctx, table, "str")
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+
+	checkAggregateOps(t, table, func(t *rapid.T, ctx context.Context, entries []*testpb.ExampleTable, prefixKey ...interface{}) {
+		var expMin, expMax int64
+		expFound := false
+		for _, msg := range entries {
+			v := int64(msg.I32)
+			if !expFound || v < expMin {
+				expMin = v
+			}
+			if !expFound || v > expMax {
+				expMax = v
+			}
+			expFound = true
+		}
+
+		min, max, found, err := ormtable.MinMax(ctx, table, "str,b", prefixKey...)
+		assert.NilError(t, err)
+		assert.Equal(t, expFound, found)
+		assert.Equal(t, expMin, min)
+		assert.Equal(t, expMax, max)
+	})
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:      (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		MinMaxAggregates: map[string]string{"str": "u64"},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:      (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		MinMaxAggregates: map[string]string{"str,i32": "i32"},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+}
+
+func TestReversePaginationWithCursor(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := uint32(0); i < 4; i++ {
+		for _, str := range []string{"a", "b", "c"} {
+			assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, U64: uint64(i), Str: str}))
+		}
+	}
+
+	index := table.GetUniqueIndex("u64,str")
+	assert.Assert(t, index != nil)
+
+	// entries are listed as "u64/str"
+	type entry = string
+	listAll := func(list func(opts ...ormlist.Option) (ormtable.Iterator, error), opts ...ormlist.Option) (entries []entry, cursor ormlist.CursorT) {
+		it, err := list(opts...)
+		assert.NilError(t, err)
+		defer it.Close()
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			entries = append(entries, fmt.Sprintf("%d/%s", msg.(*testpb.ExampleTable).U64, msg.(*testpb.ExampleTable).Str))
+			cursor = it.Cursor()
+		}
+		return entries, cursor
+	}
+	paginate := func(list func(opts ...ormlist.Option) (ormtable.Iterator, error), limit int, opts ...ormlist.Option) (entries []entry) {
+		var cursor ormlist.CursorT
+		for {
+			it, err := list(append(opts, ormlist.Cursor(cursor))...)
+			assert.NilError(t, err)
+			n := 0
+			for ; n < limit && it.Next(); n++ {
+				msg, err := it.GetMessage()
+				assert.NilError(t, err)
+				entries = append(entries, fmt.Sprintf("%d/%s", msg.(*testpb.ExampleTable).U64, msg.(*testpb.ExampleTable).Str))
+				cursor = it.Cursor()
+			}
+			it.Close()
+			if n < limit {
+				return entries
+			}
+		}
+	}
+	reversed := func(entries []entry) []entry {
+		res := make([]entry, len(entries))
+		for i, e := range entries {
+			res[len(entries)-1-i] = e
+		}
+		return res
+	}
+
+	lists := map[string]func(opts ...ormlist.Option) (ormtable.Iterator, error){
+		"all": func(opts ...ormlist.Option) (ormtable.Iterator, error) {
+			return index.List(ctx, nil, opts...)
+		},
+		"prefix": func(opts ...ormlist.Option) (ormtable.Iterator, error) {
+			return index.List(ctx, []interface{}{uint64(1)}, opts...)
+		},
+		"range": func(opts ...ormlist.Option) (ormtable.Iterator, error) {
+			return index.ListRange(ctx, []interface{}{uint64(1), "b"}, []interface{}{uint64(2), "b"}, opts...)
+		},
+		"exclusive range": func(opts ...ormlist.Option) (ormtable.Iterator, error) {
+			return index.ListRange(ctx, []interface{}{uint64(1), "b"}, []interface{}{uint64(2), "b"}, append(opts, ormlist.ExclusiveEnd())...)
+		},
+	}
+	for name, list := range lists {
+		expected, _ := listAll(list)
+		assert.Assert(t, len(expected) > 1, name)
+		for limit := 1; limit <= len(expected)+1; limit++ {
+			assert.DeepEqual(t, expected, paginate(list, limit))
+			assert.DeepEqual(t, reversed(expected), paginate(list, limit, ormlist.Reverse()))
+		}
+	}
+
+	// cursors outside of the listed keys don't widen the iteration
+	_, lastCursor := listAll(lists["all"])
+	_, firstCursor := listAll(lists["all"], ormlist.Reverse())
+	for name, list := range lists {
+		expected, _ := listAll(list)
+		if name == "all" {
+			// the cursors are the first and last listed keys
+			expected = expected[1 : len(expected)-1]
+		}
+		entries, _ := listAll(list, ormlist.Cursor(firstCursor))
+		if name == "all" {
+			entries = entries[:len(entries)-1]
+		}
+		assert.DeepEqual(t, expected, entries)
+		entries, _ = listAll(list, ormlist.Cursor(lastCursor), ormlist.Reverse())
+		if name == "all" {
+			entries = entries[:len(entries)-1]
+		}
+		assert.DeepEqual(t, reversed(expected), entries)
+
+		if name != "all" {
+			// nothing is left after or before out-of-range cursors
+			entries, _ = listAll(list, ormlist.Cursor(lastCursor))
+			assert.Equal(t, 0, len(entries))
+			entries, _ = listAll(list, ormlist.Cursor(firstCursor), ormlist.Reverse())
+			assert.Equal(t, 0, len(entries))
+		}
+	}
+}
+
+func TestListLimit(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for i := uint32(0); i < 5; i++ {
+		assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{U32: i, U64: uint64(i)}))
+	}
+
+	list := func(opts ...ormlist.Option) (res []uint32, pageRes *queryv1beta1.PageResponse) {
+		it, err := table.List(ctx, nil, opts...)
+		assert.NilError(t, err)
+		defer it.Close()
+		for it.Next() {
+			msg, err := it.GetMessage()
+			assert.NilError(t, err)
+			res = append(res, msg.(*testpb.ExampleTable).U32)
+		}
+		return res, it.PageResponse()
+	}
+
+	res, pageRes := list(ormlist.Limit(2))
+	assert.DeepEqual(t, []uint32{0, 1}, res)
+	assert.Assert(t, pageRes != nil && pageRes.NextKey != nil)
+	res, _ = list(ormlist.Limit(2), ormlist.Reverse())
+	assert.DeepEqual(t, []uint32{4, 3}, res)
+	res, _ = list(ormlist.Limit(2), ormlist.Cursor(pageRes.NextKey))
+	assert.DeepEqual(t, []uint32{2, 3}, res)
+	res, _ = list(ormlist.Limit(2), ormlist.Filter(func(message proto.Message) bool {
+		return message.(*testpb.ExampleTable).U32%2 == 1
+	}))
+	assert.DeepEqual(t, []uint32{1, 3}, res)
+	res, _ = list(ormlist.Limit(2), ormlist.Offset(2))
+	assert.DeepEqual(t, []uint32{2, 3}, res)
+
+	// the iterator keeps reporting exhaustion once the limit is reached
+	it, err := table.List(ctx, nil, ormlist.Limit(1))
+	assert.NilError(t, err)
+	assert.Assert(t, it.Next())
+	assert.Assert(t, !it.Next())
+	assert.Assert(t, !it.Next())
+	it.Close()
+
+	// a limit greater than the number of entries lists all of them
+	res, pageRes = list(ormlist.Limit(10))
+	assert.DeepEqual(t, []uint32{0, 1, 2, 3, 4}, res)
+	assert.Assert(t, pageRes.NextKey == nil)
+
+	// a limit of 0 means no limit
+	res, _ = list(ormlist.Limit(0))
+	assert.DeepEqual(t, []uint32{0, 1, 2, 3, 4}, res)
+}
+
+func TestPrefixValuesFromMessage(t *testing.T) {
+	table, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+	for _, msg := range []*testpb.ExampleTable{
+		{U32: 1, I64: 1, Str: "a"},
+		{U32: 1, I64: 2, Str: "a", U64: 1},
+		{U32: 1, I64: 2, Str: "b", U64: 2},
+		{U32: 2, I64: 2, Str: "a", U64: 3},
+	} {
+		assert.NilError(t, table.Insert(ctx, msg))
+	}
+
+	testCases := []struct {
+		name      string
+		msg       *testpb.ExampleTable
+		expPrefix []interface{}
+		expCount  uint64
+	}{
+		{"all fields", &testpb.ExampleTable{U32: 1, I64: 2, Str: "b"}, []interface{}{uint32(1), int64(2), "b"}, 1},
+		{"first fields", &testpb.ExampleTable{U32: 1, I64: 2}, []interface{}{uint32(1), int64(2)}, 2},
+		{"gap between set fields", &testpb.ExampleTable{U32: 1, Str: "b"}, []interface{}{uint32(1)}, 3},
+		{"first field unset", &testpb.ExampleTable{I64: 2, Str: "a"}, nil, 4},
+		{"non index fields", &testpb.ExampleTable{U32: 2, U64: 7, B: true}, []interface{}{uint32(2)}, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prefix, err := ormtable.PrefixValuesFromMessage(table.PrimaryKey(), tc.msg)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expPrefix, prefix)
+
+			n, err := table.PrimaryKey().Count(ctx, prefix...)
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expCount, n)
+		})
+	}
+
+	_, err = ormtable.PrefixValuesFromMessage(table.PrimaryKey(), &testpb.ExampleTimestamp{})
+	assert.ErrorIs(t, err, ormerrors.UnexpectedError)
+
+	// message fields are returned as values which can be listed
+	tsTable, err := ormtable.Build(ormtable.Options{
+		MessageType: (&testpb.ExampleTimestamp{}).ProtoReflect().Type(),
+	})
+	assert.NilError(t, err)
+	ts := timestamppb.New(time.Unix(1000, 0))
+	assert.NilError(t, tsTable.Insert(ctx, &testpb.ExampleTimestamp{Name: "foo", Ts: ts}))
+	index := tsTable.GetIndex("ts")
+	prefix, err := ormtable.PrefixValuesFromMessage(index, &testpb.ExampleTimestamp{Ts: ts})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(prefix))
+	found, err := index.First(ctx, &testpb.ExampleTimestamp{}, prefix...)
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+}
+
+func TestNullsLastIndexes(t *testing.T) {
+	for _, descending := range []bool{false, true} {
+		t.Run(fmt.Sprintf("descending %t", descending), func(t *testing.T) {
+			options := ormtable.Options{
+				MessageType:      (&testpb.ExampleTimestamp{}).ProtoReflect().Type(),
+				NullsLastIndexes: []string{"ts"},
+			}
+			if descending {
+				options.DescendingIndexes = []string{"ts"}
+			}
+			table, err := ormtable.Build(options)
+			assert.NilError(t, err)
+			ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+			for _, msg := range []*testpb.ExampleTimestamp{
+				{Name: "unset1"},
+				{Name: "t2", Ts: timestamppb.New(time.Unix(2, 0))},
+				{Name: "unset2"},
+				{Name: "epoch", Ts: timestamppb.New(time.Unix(0, 0))},
+				{Name: "t1", Ts: timestamppb.New(time.Unix(1, 0))},
+				{Name: "before epoch", Ts: timestamppb.New(time.Unix(-1, 0))},
+			} {
+				assert.NilError(t, table.Insert(ctx, msg))
+			}
+
+			index := table.GetIndex("ts")
+			list := func(prefix []interface{}, opts ...ormlist.Option) (names []string) {
+				it, err := index.List(ctx, prefix, opts...)
+				assert.NilError(t, err)
+				defer it.Close()
+				for it.Next() {
+					msg, err := it.GetMessage()
+					assert.NilError(t, err)
+					names = append(names, msg.(*testpb.ExampleTimestamp).Name)
+				}
+				return names
+			}
+
+			ascending := []string{"before epoch", "epoch", "t1", "t2", "unset1", "unset2"}
+			reversed := []string{"unset2", "unset1", "t2", "t1", "epoch", "before epoch"}
+			if descending {
+				ascending, reversed = reversed, ascending
+			}
+			assert.DeepEqual(t, ascending, list(nil))
+			assert.DeepEqual(t, reversed, list(nil, ormlist.Reverse()))
+
+			// unset values are queried with nil values
+			nulls := []string{"unset1", "unset2"}
+			if descending {
+				// primary key values are descending too
+				nulls = []string{"unset2", "unset1"}
+			}
+			assert.DeepEqual(t, nulls, list([]interface{}{nil}))
+			assert.DeepEqual(t, []string{"epoch"}, list([]interface{}{timestamppb.New(time.Unix(0, 0))}))
+
+			// unsetting a value moves the entry to the nulls
+			msg := &testpb.ExampleTimestamp{}
+			found, err := table.PrimaryKey().Get(ctx, msg, uint64(5))
+			assert.NilError(t, err)
+			assert.Assert(t, found)
+			assert.Equal(t, "t1", msg.Name)
+			msg.Ts = nil
+			assert.NilError(t, table.Update(ctx, msg))
+			assert.Equal(t, 3, len(list([]interface{}{nil})))
+
+			// decoded index values of unset fields are invalid
+			it, err := index.List(ctx, []interface{}{nil})
+			assert.NilError(t, err)
+			assert.Assert(t, it.Next())
+			indexValues, _, err := it.Keys()
+			assert.NilError(t, err)
+			assert.Assert(t, !indexValues[0].IsValid())
+			it.Close()
+		})
+	}
+
+	// the index must have an optional non-primary key field
+	_, err := ormtable.Build(ormtable.Options{
+		MessageType:      (&testpb.ExampleTable{}).ProtoReflect().Type(),
+		NullsLastIndexes: []string{"str,u32"},
+	})
+	assert.ErrorIs(t, err, ormerrors.InvalidTableDefinition)
+
+	_, err = ormtable.Build(ormtable.Options{
+		MessageType:      (&testpb.ExampleTimestamp{}).ProtoReflect().Type(),
+		NullsLastIndexes: []string{"name"},
+	})
+	assert.ErrorIs(t, err, ormerrors.CantFindIndex)
+}
+
+func TestLatestForPrefix(t *testing.T) {
+	for _, descending := range []bool{false, true} {
+		t.Run(fmt.Sprintf("descending %t", descending), func(t *testing.T) {
+			options := ormtable.Options{
+				MessageType: (&testpb.ExampleTable{}).ProtoReflect().Type(),
+			}
+			if descending {
+				options.DescendingIndexes = []string{"str,u32"}
+			}
+			table, err := ormtable.Build(options)
+			assert.NilError(t, err)
+			ctx := ormtable.WrapContextDefault(testkv.NewSplitMemBackend())
+
+			// versions of documents "a" and "b", as str and u32
+			for i, version := range []uint32{1, 10, 9, 2} {
+				assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{Str: "a", U32: version, U64: uint64(i)}))
+			}
+			assert.NilError(t, table.Insert(ctx, &testpb.ExampleTable{Str: "b", U32: 3, U64: 10}))
+
+			index := table.GetIndex("str,u32")
+			var msg testpb.ExampleTable
+			found, err := ormtable.LatestForPrefix(ctx, index, &msg, "a")
+			assert.NilError(t, err)
+			assert.Assert(t, found)
+			assert.Equal(t, uint32(10), msg.U32)
+
+			found, err = ormtable.LatestForPrefix(ctx, index, &msg, "b")
+			assert.NilError(t, err)
+			assert.Assert(t, found)
+			assert.Equal(t, uint32(3), msg.U32)
+
+			// the latest of all the entries
+			found, err = ormtable.LatestForPrefix(ctx, index, &msg)
+			assert.NilError(t, err)
+			assert.Assert(t, found)
+			assert.Equal(t, "b", msg.Str)
+
+			found, err = ormtable.LatestForPrefix(ctx, index, &msg, "c")
+			assert.NilError(t, err)
+			assert.Assert(t, !found)
+
+			// the primary key is "u32,i64,str"
+			found, err = ormtable.LatestForPrefix(ctx, table, &msg, uint32(9))
+			assert.NilError(t, err)
+			assert.Assert(t, found)
+			assert.Equal(t, "a", msg.Str)
+
+			_, err = ormtable.LatestForPrefix(ctx, table.GetUniqueIndex("u64,str"), &msg, uint64(1), "a")
+			assert.ErrorIs(t, err, ormerrors.IndexOutOfBounds)
+		})
+	}
+}
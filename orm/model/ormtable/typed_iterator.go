@@ -0,0 +1,59 @@
+package ormtable
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	queryv1beta1 "github.com/cosmos/cosmos-sdk/api/cosmos/base/query/v1beta1"
+	"github.com/cosmos/cosmos-sdk/orm/model/ormlist"
+)
+
+// TypedIterator wraps an Iterator to return the entries it iterates over
+// already decoded into the concrete message type T of the table, for instance
+// *foo.Bar. Cursors, pagination and reverse iteration are those of the wrapped
+// iterator, which must be created with the corresponding list options.
+type TypedIterator[T proto.Message] struct {
+	it Iterator
+}
+
+// NewTypedIterator returns a TypedIterator decoding the entries of it into
+// messages of type T.
+func NewTypedIterator[T proto.Message](it Iterator) *TypedIterator[T] {
+	return &TypedIterator[T]{it: it}
+}
+
+// Next advances the iterator and returns the decoded message of the next
+// entry, or false if there are no more entries. Decoding errors are returned
+// instead of the message.
+func (t *TypedIterator[T]) Next() (msg T, ok bool, err error) {
+	var zero T
+	if !t.it.Next() {
+		return zero, false, nil
+	}
+
+	// the zero value of generated message pointer types still reflects their
+	// type
+	msg = zero.ProtoReflect().Type().New().Interface().(T)
+	if err = t.it.UnmarshalMessage(msg); err != nil {
+		return zero, false, err
+	}
+
+	return msg, true, nil
+}
+
+// Cursor returns the cursor referencing the current iteration position, see
+// Iterator.Cursor.
+func (t *TypedIterator[T]) Cursor() ormlist.CursorT {
+	return t.it.Cursor()
+}
+
+// PageResponse returns the page response of the wrapped iterator, see
+// Iterator.PageResponse.
+func (t *TypedIterator[T]) PageResponse() *queryv1beta1.PageResponse {
+	return t.it.PageResponse()
+}
+
+// Close closes the wrapped iterator and must always be called when done
+// using the iterator.
+func (t *TypedIterator[T]) Close() {
+	t.it.Close()
+}
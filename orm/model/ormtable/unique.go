@@ -43,6 +43,32 @@ func (u uniqueKeyIndex) ListRange(ctx context.Context, from, to []interface{}, o
 	return rangeIterator(backend.IndexStoreReader(), backend, u, u.GetKeyCodec(), from, to, options)
 }
 
+func (u uniqueKeyIndex) First(ctx context.Context, message proto.Message, prefixKey ...interface{}) (found bool, err error) {
+	return getFirst(ctx, u, message, prefixKey)
+}
+
+func (u uniqueKeyIndex) Last(ctx context.Context, message proto.Message, prefixKey ...interface{}) (found bool, err error) {
+	return getFirst(ctx, u, message, prefixKey, ormlist.Reverse())
+}
+
+func (u uniqueKeyIndex) Count(ctx context.Context, prefixKey ...interface{}) (uint64, error) {
+	backend, err := u.getReadBackend(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return countPrefix(backend.IndexStoreReader(), u.GetKeyCodec(), prefixKey, 0)
+}
+
+func (u uniqueKeyIndex) EstimateCount(ctx context.Context, prefixKey ...interface{}) (count uint64, exact bool, err error) {
+	backend, err := u.getReadBackend(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return estimateCount(backend.IndexStoreReader(), u.GetKeyCodec(), prefixKey)
+}
+
 func (u uniqueKeyIndex) doNotImplement() {}
 
 func (u uniqueKeyIndex) Has(ctx context.Context, values ...interface{}) (found bool, err error) {
@@ -59,6 +85,15 @@ func (u uniqueKeyIndex) Has(ctx context.Context, values ...interface{}) (found b
 	return backend.IndexStoreReader().Has(key)
 }
 
+func (u uniqueKeyIndex) HasPrefix(ctx context.Context, prefixKey ...interface{}) (found bool, err error) {
+	backend, err := u.getReadBackend(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return hasPrefix(backend.IndexStoreReader(), u.GetKeyCodec(), prefixKey)
+}
+
 func (u uniqueKeyIndex) Get(ctx context.Context, message proto.Message, keyValues ...interface{}) (found bool, err error) {
 	backend, err := u.getReadBackend(ctx)
 	if err != nil {
@@ -88,10 +123,19 @@ func (u uniqueKeyIndex) Get(ctx context.Context, message proto.Message, keyValue
 	return u.primaryKey.get(backend, message, pk)
 }
 
+func (u uniqueKeyIndex) GetOrCreate(ctx context.Context, message proto.Message, create func() proto.Message, keyValues ...interface{}) (created bool, err error) {
+	return getOrCreate(ctx, u, u.GetKeyCodec(), u.primaryKey.insert, message, create, keyValues)
+}
+
 func (u uniqueKeyIndex) DeleteBy(ctx context.Context, keyValues ...interface{}) error {
+	_, err := u.deleteByPrefix(ctx, keyValues)
+	return err
+}
+
+func (u uniqueKeyIndex) deleteByPrefix(ctx context.Context, keyValues []interface{}) (uint64, error) {
 	it, err := u.List(ctx, keyValues)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	return u.primaryKey.deleteByIterator(ctx, it)
@@ -103,7 +147,17 @@ func (u uniqueKeyIndex) DeleteRange(ctx context.Context, from, to []interface{})
 		return err
 	}
 
-	return u.primaryKey.deleteByIterator(ctx, it)
+	_, err = u.primaryKey.deleteByIterator(ctx, it)
+	return err
+}
+
+func (u uniqueKeyIndex) indexKeys(message protoreflect.Message) ([][]byte, error) {
+	_, k, err := u.GetKeyCodec().EncodeKeyFromMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]byte{k}, nil
 }
 
 func (u uniqueKeyIndex) onInsert(store kv.Store, message protoreflect.Message) error {
@@ -118,7 +172,7 @@ func (u uniqueKeyIndex) onInsert(store kv.Store, message protoreflect.Message) e
 	}
 
 	if has {
-		return ormerrors.UniqueKeyViolation.Wrapf("%q", u.fields)
+		return &UniqueKeyViolationError{Fields: u.fields.String(), Values: u.GetKeyCodec().GetKeyValues(message)}
 	}
 
 	return store.Set(k, v)
@@ -143,7 +197,7 @@ func (u uniqueKeyIndex) onUpdate(store kv.Store, new, existing protoreflect.Mess
 	}
 
 	if has {
-		return ormerrors.UniqueKeyViolation.Wrapf("%q", u.fields)
+		return &UniqueKeyViolationError{Fields: u.fields.String(), Values: newValues}
 	}
 
 	existingKey, err := keyCodec.EncodeKey(existingValues)
@@ -186,6 +240,19 @@ func (u uniqueKeyIndex) readValueFromIndexKey(store ReadBackend, primaryKey []pr
 	return nil
 }
 
+func (u uniqueKeyIndex) readProjectedValueFromIndexKey(backend ReadBackend, primaryKey []protoreflect.Value, _ []byte, message proto.Message, fields []protoreflect.FieldDescriptor) error {
+	found, err := u.primaryKey.getProjected(backend, message, primaryKey, fields)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return ormerrors.UnexpectedError.Wrapf("can't find primary key")
+	}
+
+	return nil
+}
+
 func (u uniqueKeyIndex) Fields() string {
 	return u.fields.String()
 }
@@ -0,0 +1,110 @@
+package ormtable
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// IndexVerificationReport lists the discrepancies found by VerifyIndex between
+// a secondary index and the entries of its table, as encoded index keys
+// sorted in byte order.
+type IndexVerificationReport struct {
+	// Missing are the keys which the table's entries should have in the index
+	// but which aren't stored.
+	Missing [][]byte
+
+	// Orphaned are the keys stored in the index which don't belong to any of
+	// the table's entries.
+	Orphaned [][]byte
+}
+
+// IsValid returns true if the report lists no discrepancies.
+func (r IndexVerificationReport) IsValid() bool {
+	return len(r.Missing) == 0 && len(r.Orphaned) == 0
+}
+
+// VerifyIndex recomputes the entries of the secondary index of the table with
+// the provided fields from the table's entries, following the current index
+// configuration of the table, and compares them to the stored ones. It
+// doesn't write to any store, and is the read-only diagnostic complement of
+// RebuildIndex, which can fix the reported discrepancies. The expected index
+// keys are kept in memory, so it is meant for debugging rather than for
+// regular use on large tables.
+func VerifyIndex(ctx context.Context, table Table, fields string) (IndexVerificationReport, error) {
+	verifier, ok := table.(interface {
+		verifyIndex(ctx context.Context, fields string) (IndexVerificationReport, error)
+	})
+	if !ok {
+		return IndexVerificationReport{}, ormerrors.UnsupportedOperation.Wrapf("can't verify indexes of %T", table)
+	}
+
+	return verifier.verifyIndex(ctx, fields)
+}
+
+func (t tableImpl) verifyIndex(ctx context.Context, fields string) (IndexVerificationReport, error) {
+	var report IndexVerificationReport
+
+	backend, err := t.getBackend(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	index, idxIndexer, err := t.secondaryIndexer(fields)
+	if err != nil {
+		return report, err
+	}
+
+	prefix, err := indexPrefix(index)
+	if err != nil {
+		return report, err
+	}
+
+	// compute the expected keys from the table's entries
+	expected := map[string]bool{}
+	tableIt, err := t.List(ctx, nil)
+	if err != nil {
+		return report, err
+	}
+	defer tableIt.Close()
+	for tableIt.Next() {
+		msg, err := tableIt.GetMessage()
+		if err != nil {
+			return report, err
+		}
+
+		keys, err := idxIndexer.indexKeys(msg.ProtoReflect())
+		if err != nil {
+			return report, err
+		}
+		for _, k := range keys {
+			expected[string(k)] = true
+		}
+	}
+
+	// compare them to the stored keys
+	it, err := backend.IndexStoreReader().Iterator(prefix, prefixEndBytes(prefix))
+	if err != nil {
+		return report, err
+	}
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		k := it.Key()
+		if !expected[string(k)] {
+			report.Orphaned = append(report.Orphaned, append([]byte(nil), k...))
+			continue
+		}
+		delete(expected, string(k))
+	}
+
+	for k := range expected {
+		report.Missing = append(report.Missing, []byte(k))
+	}
+	sort.Slice(report.Missing, func(i, j int) bool {
+		return bytes.Compare(report.Missing[i], report.Missing[j]) < 0
+	})
+
+	return report, nil
+}
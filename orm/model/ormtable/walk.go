@@ -0,0 +1,101 @@
+package ormtable
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosmos/cosmos-sdk/orm/internal/fieldnames"
+	"github.com/cosmos/cosmos-sdk/orm/model/ormlist"
+	"github.com/cosmos/cosmos-sdk/orm/types/ormerrors"
+)
+
+// Walk lists the entries of index with the provided prefix key and options, as
+// Index.List does, and calls fn with each listed message until fn returns
+// stop or an error, which is returned unchanged. The iterator is always
+// closed, so that callers don't need to manage its lifecycle.
+//
+// The same restrictions as for iterators apply: the table generally shouldn't
+// be mutated by fn.
+func Walk(ctx context.Context, index Index, prefixKey []interface{}, fn func(message proto.Message) (stop bool, err error), options ...ormlist.Option) error {
+	it, err := index.List(ctx, prefixKey, options...)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for it.Next() {
+		msg, err := it.GetMessage()
+		if err != nil {
+			return err
+		}
+
+		stop, err := fn(msg)
+		if err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// WalkGroups lists the entries of index with the provided prefix key and
+// options, as ListEntries does, and calls fn once for each group of
+// consecutive entries whose first groupByFields index key values are equal,
+// with these values and the entries of the group in iteration order, like a
+// GROUP BY over a prefix of the index fields. Since index iteration follows
+// key order, the entries of a group are always contiguous. groupByFields must
+// be between 1 and the number of fields of the index. Iteration stops at the
+// first error decoding an entry or returned by fn, which is returned
+// unchanged.
+//
+// The entries of a group are kept in memory until fn is called, and the same
+// restrictions as for iterators apply: the table generally shouldn't be
+// mutated by fn.
+func WalkGroups(ctx context.Context, index Index, prefixKey []interface{}, groupByFields int, fn func(groupKey []protoreflect.Value, entries []IndexEntry) error, options ...ormlist.Option) error {
+	keyCodec, err := indexKeyCodec(index)
+	if err != nil {
+		return err
+	}
+
+	if n := len(fieldnames.CommaSeparatedFieldNames(index.Fields()).Names()); groupByFields < 1 || groupByFields > n {
+		return ormerrors.IndexOutOfBounds.Wrapf("can't group by %d of the %d fields of index %s", groupByFields, n, index.Fields())
+	}
+
+	it, err := ListEntries(ctx, index, prefixKey, options...)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	var groupKey []protoreflect.Value
+	var group []IndexEntry
+	for it.Next() {
+		entry := it.Entry()
+		if entry.Err != nil {
+			return entry.Err
+		}
+
+		key := entry.IndexKey[:groupByFields]
+		if group != nil && keyCodec.CompareKeys(groupKey, key) != 0 {
+			if err := fn(groupKey, group); err != nil {
+				return err
+			}
+			group = nil
+		}
+		if group == nil {
+			groupKey = key
+		}
+		group = append(group, entry)
+	}
+
+	if group != nil {
+		return fn(groupKey, group)
+	}
+
+	return nil
+}
@@ -44,4 +44,10 @@ var (
 	ReadOnly                      = errors.New(codespace, 30, "database is read-only")
 	AlreadyExists                 = errors.RegisterWithGRPCCode(codespace, 31, codes.AlreadyExists, "already exists")
 	ConstraintViolation           = errors.RegisterWithGRPCCode(codespace, 32, codes.FailedPrecondition, "failed precondition")
+	InvalidCursor                 = errors.RegisterWithGRPCCode(codespace, 33, codes.InvalidArgument, "invalid cursor")
+	ForeignKeyViolation           = errors.RegisterWithGRPCCode(codespace, 34, codes.FailedPrecondition, "foreign key violation")
+	VersionConflict               = errors.RegisterWithGRPCCode(codespace, 35, codes.Aborted, "version conflict")
+	KeyTooLong                    = errors.RegisterWithGRPCCode(codespace, 36, codes.InvalidArgument, "index key too long")
+	InvalidDecimal                = errors.RegisterWithGRPCCode(codespace, 37, codes.InvalidArgument, "invalid decimal")
+	InsertionPositionAlreadySet   = errors.RegisterWithGRPCCode(codespace, 38, codes.InvalidArgument, "can't insert with insertion position already set")
 )
@@ -16,6 +16,16 @@ import (
 // metrics emitted using the telemetry package function wrappers.
 var globalLabels = []metrics.Label{}
 
+// globalTelemetryEnabled is set to true once telemetry has been successfully
+// initialized with New.
+var globalTelemetryEnabled = false
+
+// IsTelemetryEnabled returns true if application telemetry is enabled, i.e.
+// metrics are being collected.
+func IsTelemetryEnabled() bool {
+	return globalTelemetryEnabled
+}
+
 // Metrics supported format types.
 const (
 	FormatDefault    = ""
@@ -113,6 +123,8 @@ func New(cfg Config) (*Metrics, error) {
 		return nil, err
 	}
 
+	globalTelemetryEnabled = true
+
 	return m, nil
 }
 
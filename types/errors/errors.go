@@ -162,6 +162,26 @@ var (
 	// ErrAppConfig defines an error occurred if min-gas-prices field in BaseConfig is empty.
 	ErrAppConfig = Register(RootCodespace, 40, "error in app.toml")
 
+	// ErrTxTimeout defines an ABCI typed error for when a tx is rejected because
+	// the block time is past its timeout timestamp.
+	ErrTxTimeout = Register(RootCodespace, 41, "tx timeout")
+
+	// ErrMemoTooShort defines an ABCI typed error for when a tx memo is shorter
+	// than the required minimum length.
+	ErrMemoTooShort = Register(RootCodespace, 42, "memo too short")
+
+	// ErrTooManyRequests defines an ABCI typed error for when a client sent too
+	// many requests in a given amount of time.
+	ErrTooManyRequests = Register(RootCodespace, 43, "too many requests")
+
+	// ErrInvalidGasLimit defines an ABCI typed error for when the gas limit of
+	// a tx is out of the accepted bounds.
+	ErrInvalidGasLimit = Register(RootCodespace, 44, "invalid gas limit")
+
+	// ErrNoMessages defines an ABCI typed error for when a tx contains no
+	// messages.
+	ErrNoMessages = Register(RootCodespace, 45, "no messages")
+
 	// ErrPanic is only set when we recover from a panic, so we know to
 	// redact potentially sensitive system info
 	ErrPanic = errorsmod.ErrPanic
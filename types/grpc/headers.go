@@ -3,4 +3,9 @@ package grpc
 const (
 	// GRPCBlockHeightHeader is the gRPC header for block height.
 	GRPCBlockHeightHeader = "x-cosmos-block-height"
+
+	// GRPCValidateOnlySimulateHeader is the gRPC header which, when set to
+	// "true" on a Simulate request, only validates the tx without executing
+	// its messages, and reports zero gas used.
+	GRPCValidateOnlySimulateHeader = "x-cosmos-validate-only-simulate"
 )
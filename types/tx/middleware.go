@@ -9,6 +9,22 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+type validateOnlySimulateKey struct{}
+
+// WithValidateOnlySimulate returns a context for which SimulateTx only runs
+// the validation of the tx, and skips the execution of its messages. The
+// handler then reports zero gas used.
+func WithValidateOnlySimulate(ctx sdk.Context) sdk.Context {
+	return ctx.WithValue(validateOnlySimulateKey{}, true)
+}
+
+// IsValidateOnlySimulate returns true if the context was created with
+// WithValidateOnlySimulate.
+func IsValidateOnlySimulate(ctx sdk.Context) bool {
+	validateOnly, _ := ctx.Value(validateOnlySimulateKey{}).(bool)
+	return validateOnly
+}
+
 // RequestSimulateTx is the request type for the tx.Handler.RequestSimulateTx
 // method.
 type RequestSimulateTx struct {
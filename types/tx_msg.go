@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	"github.com/gogo/protobuf/proto"
 
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
@@ -67,6 +69,14 @@ type (
 
 		GetTimeoutHeight() uint64
 	}
+
+	// TxWithTimeoutTimestamp extends the Tx interface by allowing a transaction
+	// to set a block time timeout.
+	TxWithTimeoutTimestamp interface {
+		Tx
+
+		GetTimeoutTimestamp() time.Time
+	}
 )
 
 // TxDecoder unmarshals transaction bytes
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type addressPrefixTxHandler struct {
+	// allowedPrefixes are the bech32 prefixes derived from the expected
+	// account address prefix.
+	allowedPrefixes map[string]bool
+	extractors      map[string]func(msg sdk.Msg) []string
+	next            tx.Handler
+}
+
+// AddressPrefixMiddleware defines a middleware that rejects in CheckTx and
+// DeliverTx the txs with a message referencing a bech32 address which doesn't
+// use expectedPrefix, the account address prefix of the chain, with an
+// ErrInvalidAddress error. This catches txs built for another network before
+// the message handlers fail on them, or GetSigners panics. Validator,
+// consensus and public key addresses derived from expectedPrefix, such as
+// expectedPrefix+"valoper", are accepted too.
+//
+// The signers of every message are checked through GetSigners, which decodes
+// them with the account address prefix of the sdk.Config, expected to be
+// expectedPrefix: signers which GetSigners returns empty, or panics on, are
+// invalid.
+// Other addresses are only checked if an extractor returning them is
+// registered in extractors for the type URL of the message, since messages
+// can legitimately reference addresses of other chains, like the receiver of
+// an IBC transfer. SimulateTx is passed through.
+func AddressPrefixMiddleware(expectedPrefix string, extractors map[string]func(msg sdk.Msg) []string) tx.Middleware {
+	allowedPrefixes := map[string]bool{}
+	for _, suffix := range []string{
+		"",
+		sdk.PrefixPublic,
+		sdk.PrefixValidator + sdk.PrefixOperator,
+		sdk.PrefixValidator + sdk.PrefixOperator + sdk.PrefixPublic,
+		sdk.PrefixValidator + sdk.PrefixConsensus,
+		sdk.PrefixValidator + sdk.PrefixConsensus + sdk.PrefixPublic,
+	} {
+		allowedPrefixes[expectedPrefix+suffix] = true
+	}
+
+	return func(txh tx.Handler) tx.Handler {
+		return addressPrefixTxHandler{
+			allowedPrefixes: allowedPrefixes,
+			extractors:      extractors,
+			next:            txh,
+		}
+	}
+}
+
+var _ tx.Handler = addressPrefixTxHandler{}
+
+func (txh addressPrefixTxHandler) checkAddressPrefixes(sdkTx sdk.Tx) error {
+	for _, msg := range sdkTx.GetMsgs() {
+		if err := checkSigners(msg); err != nil {
+			return err
+		}
+
+		extractor, ok := txh.extractors[sdk.MsgTypeURL(msg)]
+		if !ok {
+			continue
+		}
+
+		for _, addr := range extractor(msg) {
+			prefix, _, err := bech32.DecodeAndConvert(addr)
+			if err != nil {
+				return sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid address %s in %s: %s", addr, sdk.MsgTypeURL(msg), err)
+			}
+			if !txh.allowedPrefixes[prefix] {
+				return sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "address %s in %s has an unexpected bech32 prefix %s", addr, sdk.MsgTypeURL(msg), prefix)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSigners checks that GetSigners neither panics on msg nor returns empty
+// signers, which it does when the signer addresses are invalid or have
+// another bech32 prefix.
+func checkSigners(msg sdk.Msg) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid signers of %s: %s", sdk.MsgTypeURL(msg), fmt.Sprint(r))
+		}
+	}()
+
+	for _, signer := range msg.GetSigners() {
+		if signer.Empty() {
+			return sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid signer of %s", sdk.MsgTypeURL(msg))
+		}
+	}
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh addressPrefixTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkAddressPrefixes(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh addressPrefixTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkAddressPrefixes(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh addressPrefixTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+func (s *MWTestSuite) TestAddressPrefixMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+	_, _, addr2 := testdata.KeyTestPubAddr()
+	otherNetAddr, err := bech32.ConvertAndEncode("osmo", addr2)
+	s.Require().NoError(err)
+	valAddr := sdk.ValAddress(addr2).String()
+
+	coins := sdk.NewCoins(sdk.NewInt64Coin("atom", 10))
+	msgSendURL := sdk.MsgTypeURL(&banktypes.MsgSend{})
+
+	testCases := []struct {
+		name       string
+		msgs       []sdk.Msg
+		extractors map[string]func(msg sdk.Msg) []string
+		expErr     bool
+	}{
+		{"expected prefix", []sdk.Msg{banktypes.NewMsgSend(addr1, addr2, coins)}, nil, false},
+		{"unexpected prefix of a signer", []sdk.Msg{&banktypes.MsgSend{FromAddress: otherNetAddr, ToAddress: addr2.String(), Amount: coins}}, nil, true},
+		{"unexpected prefix in signer slice", []sdk.Msg{&testdata.TestMsg{Signers: []string{addr1.String(), otherNetAddr}}}, nil, true},
+		{"unexpected prefix in a later msg", []sdk.Msg{testdata.NewTestMsg(addr1), &testdata.TestMsg{Signers: []string{otherNetAddr}}}, nil, true},
+		{"unexpected prefix of a non-signer without extractor", []sdk.Msg{&banktypes.MsgSend{FromAddress: addr1.String(), ToAddress: otherNetAddr, Amount: coins}}, nil, false},
+		{
+			"extractor returning the unexpected prefix",
+			[]sdk.Msg{&banktypes.MsgSend{FromAddress: addr1.String(), ToAddress: otherNetAddr, Amount: coins}},
+			map[string]func(msg sdk.Msg) []string{msgSendURL: func(msg sdk.Msg) []string {
+				return []string{msg.(*banktypes.MsgSend).ToAddress}
+			}},
+			true,
+		},
+		{
+			"extractor returning a validator prefix",
+			[]sdk.Msg{banktypes.NewMsgSend(addr1, addr2, coins)},
+			map[string]func(msg sdk.Msg) []string{msgSendURL: func(sdk.Msg) []string {
+				return []string{valAddr}
+			}},
+			false,
+		},
+		{
+			"extractor returning an invalid address",
+			[]sdk.Msg{banktypes.NewMsgSend(addr1, addr2, coins)},
+			map[string]func(msg sdk.Msg) []string{msgSendURL: func(sdk.Msg) []string {
+				return []string{"invalid"}
+			}},
+			true,
+		},
+		{
+			"extractor not returning the unexpected prefix of a signer",
+			[]sdk.Msg{&banktypes.MsgSend{FromAddress: otherNetAddr, ToAddress: addr2.String(), Amount: coins}},
+			map[string]func(msg sdk.Msg) []string{msgSendURL: func(msg sdk.Msg) []string {
+				return []string{msg.(*banktypes.MsgSend).ToAddress}
+			}},
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			req := tx.Request{Tx: msgsTx(tc.msgs)}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.AddressPrefixMiddleware(sdk.Bech32MainPrefix, tc.extractors))
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrInvalidAddress)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+
+			// SimulateTx is passed through
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+}
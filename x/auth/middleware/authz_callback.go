@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type authzCallbackTxHandler struct {
+	authorize    func(ctx context.Context, msg sdk.Msg, signers []sdk.AccAddress) error
+	checkCheckTx bool
+	next         tx.Handler
+}
+
+// AuthzCallbackMiddleware defines a middleware that calls authorize for each
+// message of the tx, in tx order, with the signers of the message, and rejects
+// the tx with the first error returned by authorize. It centralizes
+// authorization rules which go beyond signature verification, e.g. restricting
+// a message type to a set of addresses. The error is returned unchanged, so it
+// should be a registered error to get a meaningful ABCI code.
+//
+// Messages are always authorized in DeliverTx, and in CheckTx too if
+// checkCheckTx is true. SimulateTx is passed through.
+func AuthzCallbackMiddleware(authorize func(ctx context.Context, msg sdk.Msg, signers []sdk.AccAddress) error, checkCheckTx bool) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return authzCallbackTxHandler{
+			authorize:    authorize,
+			checkCheckTx: checkCheckTx,
+			next:         txh,
+		}
+	}
+}
+
+var _ tx.Handler = authzCallbackTxHandler{}
+
+func (txh authzCallbackTxHandler) authorizeMsgs(ctx context.Context, sdkTx sdk.Tx) error {
+	for _, msg := range sdkTx.GetMsgs() {
+		if err := txh.authorize(ctx, msg, msg.GetSigners()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh authzCallbackTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if txh.checkCheckTx {
+		if err := txh.authorizeMsgs(ctx, req.Tx); err != nil {
+			return tx.Response{}, tx.ResponseCheckTx{}, err
+		}
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh authzCallbackTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.authorizeMsgs(ctx, req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh authzCallbackTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
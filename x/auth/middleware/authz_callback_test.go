@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestAuthzCallbackMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, admin := testdata.KeyTestPubAddr()
+	_, _, addr1 := testdata.KeyTestPubAddr()
+
+	// only admin may send TestMsg, other messages are unrestricted
+	var calls int
+	authorize := func(_ context.Context, msg sdk.Msg, signers []sdk.AccAddress) error {
+		calls++
+		if _, ok := msg.(*testdata.TestMsg); !ok {
+			return nil
+		}
+		for _, signer := range signers {
+			if !signer.Equals(admin) {
+				return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "%s is not allowed to send %T", signer, msg)
+			}
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		name         string
+		tx           sdk.Tx
+		checkCheckTx bool
+		expErr       bool
+		expCalls     int
+	}{
+		{"authorized signer", msgsTx{testdata.NewTestMsg(admin)}, true, false, 1},
+		{"unauthorized signer", msgsTx{testdata.NewTestMsg(addr1)}, true, true, 1},
+		{"unrestricted msg", msgsTx{&testdata.MsgCreateDog{}}, true, false, 1},
+		{"stops at the first unauthorized msg", msgsTx{&testdata.MsgCreateDog{}, testdata.NewTestMsg(admin, addr1), testdata.NewTestMsg(admin)}, true, true, 2},
+		{"unauthorized signer, DeliverTx only", msgsTx{testdata.NewTestMsg(addr1)}, false, true, 1},
+		{"no messages", txTest{}, true, false, 0},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			req := tx.Request{Tx: tc.tx}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.AuthzCallbackMiddleware(authorize, tc.checkCheckTx))
+
+			calls = 0
+			_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			if !tc.checkCheckTx {
+				s.Require().NoError(err)
+				s.Require().Zero(calls)
+			} else if tc.expErr {
+				s.Require().ErrorIs(err, sdkerrors.ErrUnauthorized)
+				s.Require().Contains(err.Error(), addr1.String())
+			} else {
+				s.Require().NoError(err)
+			}
+
+			calls = 0
+			_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			if tc.expErr {
+				s.Require().ErrorIs(err, sdkerrors.ErrUnauthorized)
+			} else {
+				s.Require().NoError(err)
+			}
+			s.Require().Equal(tc.expCalls, calls)
+
+			// SimulateTx is passed through
+			calls = 0
+			_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+			s.Require().Zero(calls)
+		})
+	}
+}
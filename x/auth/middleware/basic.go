@@ -2,6 +2,10 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/cosmos/cosmos-sdk/codec/legacy"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
@@ -17,6 +21,40 @@ import (
 
 type validateBasicTxHandler struct {
 	next tx.Handler
+	opts validateBasicOptions
+}
+
+// validateBasicOptions defines the optional behaviors of the validate basic
+// middleware.
+type validateBasicOptions struct {
+	aggregateErrors bool
+	runOnRecheck    bool
+	// skipMsgTypes is the set of msg type URLs on which msg.ValidateBasic is
+	// not called.
+	skipMsgTypes map[string]bool
+}
+
+// ValidateBasicOption configures the middleware returned by
+// ValidateBasicMiddlewareWithOptions.
+type ValidateBasicOption func(*validateBasicOptions)
+
+// AggregateErrors makes the validate basic middleware run msg.ValidateBasic on
+// every message of a tx, and return all failures combined in a single error,
+// instead of returning on the first failing message.
+func AggregateErrors(aggregate bool) ValidateBasicOption {
+	return func(opts *validateBasicOptions) {
+		opts.aggregateErrors = aggregate
+	}
+}
+
+// RunOnRecheck makes the validate basic middleware also run on ReCheckTx, for
+// apps whose msg.ValidateBasic depends on values which might become stale.
+// Note that enabling it increases the CPU cost of ReCheckTx, which is
+// otherwise skipped by this middleware.
+func RunOnRecheck(run bool) ValidateBasicOption {
+	return func(opts *validateBasicOptions) {
+		opts.runOnRecheck = run
+	}
 }
 
 // ValidateBasicMiddleware will call tx.ValidateBasic, msg.ValidateBasic(for each msg inside tx)
@@ -30,32 +68,89 @@ func ValidateBasicMiddleware(txh tx.Handler) tx.Handler {
 	}
 }
 
+// ValidateBasicMiddlewareWithOptions returns a validate basic middleware, as
+// defined in ValidateBasicMiddleware, configured with the given options.
+func ValidateBasicMiddlewareWithOptions(opts ...ValidateBasicOption) tx.Middleware {
+	var options validateBasicOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return func(txh tx.Handler) tx.Handler {
+		return validateBasicTxHandler{
+			next: txh,
+			opts: options,
+		}
+	}
+}
+
+// ValidateBasicMiddlewareWithSkip returns a validate basic middleware, as
+// defined in ValidateBasicMiddleware, which doesn't call msg.ValidateBasic on
+// messages whose type URL is in the skip set. tx.ValidateBasic is still called
+// on all txs. Type URLs in the skip set that don't match any message are
+// ignored.
+func ValidateBasicMiddlewareWithSkip(skip map[string]bool) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return validateBasicTxHandler{
+			next: txh,
+			opts: validateBasicOptions{skipMsgTypes: skip},
+		}
+	}
+}
+
 var _ tx.Handler = validateBasicTxHandler{}
 
 // validateBasicTxMsgs executes basic validator calls for messages.
-func validateBasicTxMsgs(msgs []sdk.Msg) error {
+func validateBasicTxMsgs(msgs []sdk.Msg, opts validateBasicOptions) error {
 	if len(msgs) == 0 {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "must contain at least one message")
 	}
 
-	for _, msg := range msgs {
+	var (
+		firstErr error
+		msgErrs  []string
+	)
+	for i, msg := range msgs {
+		if opts.skipMsgTypes[sdk.MsgTypeURL(msg)] {
+			continue
+		}
+
 		err := msg.ValidateBasic()
-		if err != nil {
+		if err == nil {
+			continue
+		}
+		if !opts.aggregateErrors {
 			return err
 		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+		msgErrs = append(msgErrs, fmt.Sprintf("msg %d: %s", i, err))
 	}
 
-	return nil
+	if firstErr == nil {
+		return nil
+	}
+
+	// Wrap the registered error of the first failure, so that the aggregated
+	// error keeps its codespace and code.
+	var sdkErr *sdkerrors.Error
+	if errors.As(firstErr, &sdkErr) {
+		firstErr = sdkErr
+	}
+
+	return sdkerrors.Wrap(firstErr, strings.Join(msgErrs, "; "))
 }
 
 // CheckTx implements tx.Handler.CheckTx.
 func (txh validateBasicTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
 	// no need to validate basic on recheck tx, call next middleware
-	if checkReq.Type == abci.CheckTxType_Recheck {
+	if checkReq.Type == abci.CheckTxType_Recheck && !txh.opts.runOnRecheck {
 		return txh.next.CheckTx(ctx, req, checkReq)
 	}
 
-	if err := validateBasicTxMsgs(req.Tx.GetMsgs()); err != nil {
+	if err := validateBasicTxMsgs(req.Tx.GetMsgs(), txh.opts); err != nil {
 		return tx.Response{}, tx.ResponseCheckTx{}, err
 	}
 
@@ -72,7 +167,7 @@ func (txh validateBasicTxHandler) DeliverTx(ctx context.Context, req tx.Request)
 		return tx.Response{}, err
 	}
 
-	if err := validateBasicTxMsgs(req.Tx.GetMsgs()); err != nil {
+	if err := validateBasicTxMsgs(req.Tx.GetMsgs(), txh.opts); err != nil {
 		return tx.Response{}, err
 	}
 
@@ -85,7 +180,72 @@ func (txh validateBasicTxHandler) SimulateTx(ctx context.Context, req tx.Request
 		return tx.Response{}, err
 	}
 
-	if err := validateBasicTxMsgs(req.Tx.GetMsgs()); err != nil {
+	if err := validateBasicTxMsgs(req.Tx.GetMsgs(), txh.opts); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.SimulateTx(ctx, req)
+}
+
+var _ tx.Handler = validateMsgCountTxHandler{}
+
+type validateMsgCountTxHandler struct {
+	maxMsgs int
+	next    tx.Handler
+}
+
+// ValidateMsgCountMiddleware defines a middleware that rejects txs containing
+// more than maxMsgs messages. A maxMsgs of 0 means no limit. Like
+// ValidateBasicMiddleware, it is not executed on ReCheckTx since it is not
+// dependent on application state.
+func ValidateMsgCountMiddleware(maxMsgs int) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return validateMsgCountTxHandler{
+			maxMsgs: maxMsgs,
+			next:    txh,
+		}
+	}
+}
+
+func (txh validateMsgCountTxHandler) checkMsgCount(tx sdk.Tx) error {
+	if txh.maxMsgs <= 0 {
+		return nil
+	}
+
+	if numMsgs := len(tx.GetMsgs()); numMsgs > txh.maxMsgs {
+		return sdkerrors.Wrapf(
+			sdkerrors.ErrInvalidRequest, "too many messages: got %d, maximum is %d", numMsgs, txh.maxMsgs,
+		)
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx.
+func (txh validateMsgCountTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if checkReq.Type == abci.CheckTxType_Recheck {
+		return txh.next.CheckTx(ctx, req, checkReq)
+	}
+
+	if err := txh.checkMsgCount(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx.
+func (txh validateMsgCountTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkMsgCount(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx.
+func (txh validateMsgCountTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkMsgCount(req.Tx); err != nil {
 		return tx.Response{}, err
 	}
 
@@ -95,7 +255,8 @@ func (txh validateBasicTxHandler) SimulateTx(ctx context.Context, req tx.Request
 var _ tx.Handler = txTimeoutHeightTxHandler{}
 
 type txTimeoutHeightTxHandler struct {
-	next tx.Handler
+	next        tx.Handler
+	gracePeriod uint64
 }
 
 // TxTimeoutHeightMiddleware defines a middleware that checks for a
@@ -106,7 +267,20 @@ func TxTimeoutHeightMiddleware(txh tx.Handler) tx.Handler {
 	}
 }
 
-func checkTimeout(ctx context.Context, tx sdk.Tx) error {
+// TxTimeoutHeightMiddlewareWithGracePeriod defines a middleware that checks for
+// a tx height timeout like TxTimeoutHeightMiddleware, but which extends the
+// timeout height by gracePeriod blocks in CheckTx. DeliverTx and SimulateTx
+// enforce the exact timeout height.
+func TxTimeoutHeightMiddlewareWithGracePeriod(gracePeriod uint64) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return txTimeoutHeightTxHandler{
+			next:        txh,
+			gracePeriod: gracePeriod,
+		}
+	}
+}
+
+func checkTimeout(ctx context.Context, tx sdk.Tx, gracePeriod uint64) error {
 	sdkCtx := sdk.UnwrapSDKContext(ctx)
 	timeoutTx, ok := tx.(sdk.TxWithTimeoutHeight)
 	if !ok {
@@ -114,7 +288,8 @@ func checkTimeout(ctx context.Context, tx sdk.Tx) error {
 	}
 
 	timeoutHeight := timeoutTx.GetTimeoutHeight()
-	if timeoutHeight > 0 && uint64(sdkCtx.BlockHeight()) > timeoutHeight {
+	blockHeight := uint64(sdkCtx.BlockHeight())
+	if timeoutHeight > 0 && blockHeight > timeoutHeight && blockHeight-timeoutHeight > gracePeriod {
 		return sdkerrors.Wrapf(
 			sdkerrors.ErrTxTimeoutHeight, "block height: %d, timeout height: %d", sdkCtx.BlockHeight(), timeoutHeight,
 		)
@@ -125,7 +300,7 @@ func checkTimeout(ctx context.Context, tx sdk.Tx) error {
 
 // CheckTx implements tx.Handler.CheckTx.
 func (txh txTimeoutHeightTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
-	if err := checkTimeout(ctx, req.Tx); err != nil {
+	if err := checkTimeout(ctx, req.Tx, txh.gracePeriod); err != nil {
 		return tx.Response{}, tx.ResponseCheckTx{}, err
 	}
 
@@ -134,7 +309,7 @@ func (txh txTimeoutHeightTxHandler) CheckTx(ctx context.Context, req tx.Request,
 
 // DeliverTx implements tx.Handler.DeliverTx.
 func (txh txTimeoutHeightTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
-	if err := checkTimeout(ctx, req.Tx); err != nil {
+	if err := checkTimeout(ctx, req.Tx, 0); err != nil {
 		return tx.Response{}, err
 	}
 
@@ -143,7 +318,70 @@ func (txh txTimeoutHeightTxHandler) DeliverTx(ctx context.Context, req tx.Reques
 
 // SimulateTx implements tx.Handler.SimulateTx.
 func (txh txTimeoutHeightTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
-	if err := checkTimeout(ctx, req.Tx); err != nil {
+	if err := checkTimeout(ctx, req.Tx, 0); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.SimulateTx(ctx, req)
+}
+
+var _ tx.Handler = txTimeoutTimestampTxHandler{}
+
+type txTimeoutTimestampTxHandler struct {
+	next tx.Handler
+}
+
+// TxTimeoutTimestampMiddleware defines a middleware that checks for a tx
+// timestamp timeout against the block time. It is not executed on ReCheckTx.
+// CONTRACT: Tx must implement TxWithTimeoutTimestamp interface
+func TxTimeoutTimestampMiddleware(txh tx.Handler) tx.Handler {
+	return txTimeoutTimestampTxHandler{
+		next: txh,
+	}
+}
+
+func checkTimeoutTimestamp(ctx context.Context, tx sdk.Tx) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	timeoutTx, ok := tx.(sdk.TxWithTimeoutTimestamp)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "expected tx to implement TxWithTimeoutTimestamp")
+	}
+
+	timeoutTimestamp := timeoutTx.GetTimeoutTimestamp()
+	if !timeoutTimestamp.IsZero() && sdkCtx.BlockTime().After(timeoutTimestamp) {
+		return sdkerrors.Wrapf(
+			sdkerrors.ErrTxTimeout, "block time: %s, timeout timestamp: %s", sdkCtx.BlockTime(), timeoutTimestamp,
+		)
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx.
+func (txh txTimeoutTimestampTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if checkReq.Type == abci.CheckTxType_Recheck {
+		return txh.next.CheckTx(ctx, req, checkReq)
+	}
+
+	if err := checkTimeoutTimestamp(ctx, req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx.
+func (txh txTimeoutTimestampTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := checkTimeoutTimestamp(ctx, req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx.
+func (txh txTimeoutTimestampTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := checkTimeoutTimestamp(ctx, req.Tx); err != nil {
 		return tx.Response{}, err
 	}
 
@@ -153,6 +391,8 @@ func (txh txTimeoutHeightTxHandler) SimulateTx(ctx context.Context, req tx.Reque
 type validateMemoTxHandler struct {
 	ak   AccountKeeper
 	next tx.Handler
+	// minMemoChars is the minimum memo length, only enforced when positive.
+	minMemoChars uint64
 }
 
 // ValidateMemoMiddleware will validate memo given the parameters passed in
@@ -167,6 +407,20 @@ func ValidateMemoMiddleware(ak AccountKeeper) tx.Middleware {
 	}
 }
 
+// ValidateMemoMiddlewareWithMin will validate memo like ValidateMemoMiddleware,
+// and additionally reject txs whose memo is shorter than minChars characters.
+// A minChars of 0 disables the minimum length check.
+// CONTRACT: Tx must implement TxWithMemo interface
+func ValidateMemoMiddlewareWithMin(ak AccountKeeper, minChars uint64) tx.Middleware {
+	return func(txHandler tx.Handler) tx.Handler {
+		return validateMemoTxHandler{
+			ak:           ak,
+			next:         txHandler,
+			minMemoChars: minChars,
+		}
+	}
+}
+
 var _ tx.Handler = validateMemoTxHandler{}
 
 func (vmm validateMemoTxHandler) checkForValidMemo(ctx context.Context, tx sdk.Tx) error {
@@ -186,6 +440,13 @@ func (vmm validateMemoTxHandler) checkForValidMemo(ctx context.Context, tx sdk.T
 		)
 	}
 
+	if vmm.minMemoChars > 0 && uint64(memoLength) < vmm.minMemoChars {
+		return sdkerrors.Wrapf(sdkerrors.ErrMemoTooShort,
+			"minimum number of characters is %d but received %d characters",
+			vmm.minMemoChars, memoLength,
+		)
+	}
+
 	return nil
 }
 
@@ -216,11 +477,111 @@ func (vmm validateMemoTxHandler) SimulateTx(ctx context.Context, req tx.Request)
 	return vmm.next.SimulateTx(ctx, req)
 }
 
+type validateMemoFormatTxHandler struct {
+	pattern *regexp.Regexp
+	next    tx.Handler
+}
+
+// ValidateMemoFormatMiddleware will reject txs whose memo doesn't match the
+// given pattern. Empty memos are only accepted if the pattern matches the
+// empty string.
+// CONTRACT: Tx must implement TxWithMemo interface
+func ValidateMemoFormatMiddleware(pattern *regexp.Regexp) tx.Middleware {
+	return func(txHandler tx.Handler) tx.Handler {
+		return validateMemoFormatTxHandler{
+			pattern: pattern,
+			next:    txHandler,
+		}
+	}
+}
+
+var _ tx.Handler = validateMemoFormatTxHandler{}
+
+func (vmf validateMemoFormatTxHandler) checkMemoFormat(tx sdk.Tx) error {
+	memoTx, ok := tx.(sdk.TxWithMemo)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "invalid transaction type")
+	}
+
+	// The memo itself is not included in the error, as it may contain
+	// sensitive information.
+	memo := memoTx.GetMemo()
+	if !vmf.pattern.MatchString(memo) {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest,
+			"memo of %d characters doesn't match the required format", len(memo),
+		)
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (vmf validateMemoFormatTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := vmf.checkMemoFormat(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return vmf.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (vmf validateMemoFormatTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := vmf.checkMemoFormat(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return vmf.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (vmf validateMemoFormatTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := vmf.checkMemoFormat(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return vmf.next.SimulateTx(ctx, req)
+}
+
 var _ tx.Handler = consumeTxSizeGasTxHandler{}
 
 type consumeTxSizeGasTxHandler struct {
 	ak   AccountKeeper
 	next tx.Handler
+	opts consumeTxSizeGasOptions
+}
+
+// consumeTxSizeGasOptions defines the optional behaviors of the consume tx size
+// gas middleware.
+type consumeTxSizeGasOptions struct {
+	deterministic bool
+	// sigCost, if set, returns the estimated size of a signature made by the
+	// given pubkey in simulate mode.
+	sigCost func(pubkey cryptotypes.PubKey) sdk.Gas
+}
+
+// ConsumeTxSizeGasOption configures the middleware returned by
+// ConsumeTxSizeGasMiddlewareWithOptions.
+type ConsumeTxSizeGasOption func(*consumeTxSizeGasOptions)
+
+// DeterministicMode makes the consume tx size gas middleware always estimate
+// missing signatures in simulate mode as secp256k1 signatures, instead of
+// using the pubkeys of the signing accounts when they exist in state. This
+// makes simulated gas independent of the state of the signing accounts.
+func DeterministicMode(deterministic bool) ConsumeTxSizeGasOption {
+	return func(opts *consumeTxSizeGasOptions) {
+		opts.deterministic = deterministic
+	}
+}
+
+// WithSigCost makes the consume tx size gas middleware use sigCost to estimate
+// the size of each missing signature in simulate mode, instead of the size of
+// an amino-encoded secp256k1 signature. If sigCost is nil, the default
+// estimation is used. Multisig pubkeys are still estimated for the maximum
+// number of signers, i.e. sigCost is multiplied by params.TxSigLimit.
+func WithSigCost(sigCost func(pubkey cryptotypes.PubKey) sdk.Gas) ConsumeTxSizeGasOption {
+	return func(opts *consumeTxSizeGasOptions) {
+		opts.sigCost = sigCost
+	}
 }
 
 // ConsumeTxSizeGasMiddleware will take in parameters and consume gas proportional
@@ -241,6 +602,30 @@ func ConsumeTxSizeGasMiddleware(ak AccountKeeper) tx.Middleware {
 	}
 }
 
+// ConsumeTxSizeGasMiddlewareWithOptions returns a ConsumeTxSizeGasMiddleware
+// configured with the given options.
+func ConsumeTxSizeGasMiddlewareWithOptions(ak AccountKeeper, opts ...ConsumeTxSizeGasOption) tx.Middleware {
+	var options consumeTxSizeGasOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return func(txHandler tx.Handler) tx.Handler {
+		return consumeTxSizeGasTxHandler{
+			ak:   ak,
+			next: txHandler,
+			opts: options,
+		}
+	}
+}
+
+// ConsumeTxSizeGasMiddlewareWithSigCost returns a ConsumeTxSizeGasMiddleware
+// which uses sigCost to estimate the size of each missing signature in simulate
+// mode, see WithSigCost.
+func ConsumeTxSizeGasMiddlewareWithSigCost(ak AccountKeeper, sigCost func(pubkey cryptotypes.PubKey) sdk.Gas) tx.Middleware {
+	return ConsumeTxSizeGasMiddlewareWithOptions(ak, WithSigCost(sigCost))
+}
+
 func (cgts consumeTxSizeGasTxHandler) simulateSigGasCost(ctx context.Context, tx sdk.Tx) error {
 	sdkCtx := sdk.UnwrapSDKContext(ctx)
 	params := cgts.ak.GetParams(sdkCtx)
@@ -263,26 +648,30 @@ func (cgts consumeTxSizeGasTxHandler) simulateSigGasCost(ctx context.Context, tx
 			continue
 		}
 
-		var pubkey cryptotypes.PubKey
-
-		acc := cgts.ak.GetAccount(sdkCtx, signer)
+		// use placeholder simSecp256k1Pubkey if sig is nil, or in deterministic
+		// mode
+		var pubkey cryptotypes.PubKey = simSecp256k1Pubkey
+		if !cgts.opts.deterministic {
+			acc := cgts.ak.GetAccount(sdkCtx, signer)
+			if acc != nil && acc.GetPubKey() != nil {
+				pubkey = acc.GetPubKey()
+			}
+		}
 
-		// use placeholder simSecp256k1Pubkey if sig is nil
-		if acc == nil || acc.GetPubKey() == nil {
-			pubkey = simSecp256k1Pubkey
+		var cost sdk.Gas
+		if cgts.opts.sigCost != nil {
+			cost = cgts.opts.sigCost(pubkey)
 		} else {
-			pubkey = acc.GetPubKey()
-		}
+			// use stdsignature to mock the size of a full signature
+			simSig := legacytx.StdSignature{ //nolint:staticcheck // this will be removed when proto is ready
+				Signature: simSecp256k1Sig[:],
+				PubKey:    pubkey,
+			}
 
-		// use stdsignature to mock the size of a full signature
-		simSig := legacytx.StdSignature{ //nolint:staticcheck // this will be removed when proto is ready
-			Signature: simSecp256k1Sig[:],
-			PubKey:    pubkey,
+			sigBz := legacy.Cdc.MustMarshal(simSig)
+			cost = sdk.Gas(len(sigBz) + 6)
 		}
 
-		sigBz := legacy.Cdc.MustMarshal(simSig)
-		cost := sdk.Gas(len(sigBz) + 6)
-
 		// If the pubkey is a multi-signature pubkey, then we estimate for the maximum
 		// number of signers.
 		if _, ok := pubkey.(*multisig.LegacyAminoPubKey); ok {
@@ -1,12 +1,19 @@
 package middleware_test
 
 import (
+	"regexp"
 	"strings"
+	"time"
 
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
 	"github.com/cosmos/cosmos-sdk/testutil/testdata"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
@@ -51,6 +58,162 @@ func (s *MWTestSuite) TestValidateBasic() {
 	s.Require().Nil(err, "ValidateBasicMiddleware ran on ReCheck")
 }
 
+func (s *MWTestSuite) TestValidateBasicAggregateErrors() {
+	ctx := s.SetupTest(true) // setup
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+
+	// keys and addresses
+	_, _, addr1 := testdata.KeyTestPubAddr()
+
+	// msgs, with the first and the last failing ValidateBasic
+	msgs := []sdk.Msg{
+		&testdata.TestMsg{Signers: []string{"invalid1"}},
+		testdata.NewTestMsg(addr1),
+		&testdata.TestMsg{Signers: []string{"invalid2"}},
+	}
+	s.Require().NoError(txBuilder.SetMsgs(msgs...))
+	txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+	txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+
+	testTx, _, err := s.createTestTx(txBuilder, []cryptotypes.PrivKey{}, []uint64{}, []uint64{}, ctx.ChainID())
+	s.Require().NoError(err)
+
+	// by default, the middleware returns on the first failing message
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ValidateBasicMiddlewareWithOptions())
+	_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{})
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidAddress)
+	s.Require().NotContains(err.Error(), "invalid2")
+
+	txHandler = middleware.ComposeMiddlewares(noopTxHandler, middleware.ValidateBasicMiddlewareWithOptions(middleware.AggregateErrors(true)))
+	_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{})
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidAddress)
+	s.Require().Contains(err.Error(), "msg 0")
+	s.Require().Contains(err.Error(), "msg 2")
+	s.Require().NotContains(err.Error(), "msg 1")
+
+	codespace, code, _ := sdkerrors.ABCIInfo(err, false)
+	s.Require().Equal(sdkerrors.ErrInvalidAddress.Codespace(), codespace)
+	s.Require().Equal(sdkerrors.ErrInvalidAddress.ABCICode(), code)
+}
+
+func (s *MWTestSuite) TestValidateBasicRunOnRecheck() {
+	ctx := s.SetupTest(true) // setup
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+
+	// msg failing ValidateBasic
+	s.Require().NoError(txBuilder.SetMsgs(&testdata.TestMsg{Signers: []string{"invalid"}}))
+	txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+	txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+
+	invalidTx, _, err := s.createTestTx(txBuilder, []cryptotypes.PrivKey{}, []uint64{}, []uint64{}, ctx.ChainID())
+	s.Require().NoError(err)
+
+	recheckReq := tx.RequestCheckTx{Type: abci.CheckTxType_Recheck}
+
+	// by default, the middleware is skipped on recheck
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ValidateBasicMiddlewareWithOptions())
+	_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: invalidTx}, recheckReq)
+	s.Require().NoError(err)
+
+	txHandler = middleware.ComposeMiddlewares(noopTxHandler, middleware.ValidateBasicMiddlewareWithOptions(middleware.RunOnRecheck(true)))
+	_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: invalidTx}, recheckReq)
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidAddress)
+}
+
+func (s *MWTestSuite) TestValidateBasicWithSkip() {
+	ctx := s.SetupTest(true) // setup
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+
+	// msg failing ValidateBasic
+	msg := &testdata.TestMsg{Signers: []string{"invalid"}}
+	s.Require().NoError(txBuilder.SetMsgs(msg))
+	txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+	txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+
+	// the tx has no signatures, so tx.ValidateBasic fails too
+	testTx, _, err := s.createTestTx(txBuilder, []cryptotypes.PrivKey{}, []uint64{}, []uint64{}, ctx.ChainID())
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name   string
+		skip   map[string]bool
+		expErr error
+	}{
+		{"nil skip set", nil, sdkerrors.ErrInvalidAddress},
+		{"unknown type url", map[string]bool{"/unknown.Msg": true}, sdkerrors.ErrInvalidAddress},
+		{"skipped type url", map[string]bool{sdk.MsgTypeURL(msg): true}, sdkerrors.ErrNoSignatures},
+		{"skipped type url set to false", map[string]bool{sdk.MsgTypeURL(msg): false}, sdkerrors.ErrInvalidAddress},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ValidateBasicMiddlewareWithSkip(tc.skip))
+
+			_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{})
+			s.Require().ErrorIs(err, tc.expErr)
+
+			_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx})
+			s.Require().Error(err)
+
+			_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx})
+			s.Require().Error(err)
+		})
+	}
+}
+
+func (s *MWTestSuite) TestValidateMsgCount() {
+	ctx := s.SetupTest(true) // setup
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+
+	// keys and addresses
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+
+	// tx with 3 msgs
+	msg := testdata.NewTestMsg(addr1)
+	s.Require().NoError(txBuilder.SetMsgs(msg, msg, msg))
+	txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+	txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+
+	privs, accNums, accSeqs := []cryptotypes.PrivKey{priv1}, []uint64{0}, []uint64{0}
+	testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name      string
+		maxMsgs   int
+		checkType abci.CheckTxType
+		expectErr bool
+	}{
+		{"no limit", 0, abci.CheckTxType_New, false},
+		{"limit above msg count", 4, abci.CheckTxType_New, false},
+		{"limit equal to msg count", 3, abci.CheckTxType_New, false},
+		{"limit below msg count", 2, abci.CheckTxType_New, true},
+		{"limit below msg count on recheck", 2, abci.CheckTxType_Recheck, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ValidateMsgCountMiddleware(tc.maxMsgs))
+
+			_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{Type: tc.checkType})
+			s.Require().Equal(tc.expectErr, err != nil, err)
+
+			if tc.checkType == abci.CheckTxType_New {
+				_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx})
+				s.Require().Equal(tc.expectErr, err != nil, err)
+				if tc.expectErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+					s.Require().Contains(err.Error(), "got 3, maximum is 2")
+				}
+			}
+		})
+	}
+}
+
 func (s *MWTestSuite) TestValidateMemo() {
 	ctx := s.SetupTest(true) // setup
 	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
@@ -86,6 +249,114 @@ func (s *MWTestSuite) TestValidateMemo() {
 	s.Require().Nil(err, "ValidateBasicMiddleware returned error on valid tx. err: %v", err)
 }
 
+func (s *MWTestSuite) TestValidateMemoWithMin() {
+	ctx := s.SetupTest(true) // setup
+
+	// keys and addresses
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+
+	// msg and signatures
+	msg := testdata.NewTestMsg(addr1)
+	privs, accNums, accSeqs := []cryptotypes.PrivKey{priv1}, []uint64{0}, []uint64{0}
+
+	testCases := []struct {
+		name     string
+		minChars uint64
+		memo     string
+		expErr   error
+	}{
+		{"no minimum, empty memo", 0, "", nil},
+		{"empty memo", 5, "", sdkerrors.ErrMemoTooShort},
+		{"memo too short", 5, "1234", sdkerrors.ErrMemoTooShort},
+		{"memo of minimum length", 5, "12345", nil},
+		{"memo too large", 5, strings.Repeat("01234567890", 500), sdkerrors.ErrMemoTooLarge},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ValidateMemoMiddlewareWithMin(s.app.AccountKeeper, tc.minChars))
+
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(msg))
+			txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+			txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+			txBuilder.SetMemo(tc.memo)
+			testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+			s.Require().NoError(err)
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx})
+			_, simulateErr := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx})
+			for _, err := range []error{checkErr, deliverErr, simulateErr} {
+				if tc.expErr == nil {
+					s.Require().NoError(err)
+				} else {
+					s.Require().ErrorIs(err, tc.expErr)
+				}
+			}
+		})
+	}
+}
+
+func (s *MWTestSuite) TestValidateMemoFormat() {
+	ctx := s.SetupTest(true) // setup
+
+	// keys and addresses
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+
+	// msg and signatures
+	msg := testdata.NewTestMsg(addr1)
+	privs, accNums, accSeqs := []cryptotypes.PrivKey{priv1}, []uint64{0}, []uint64{0}
+
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	optionalUUIDPattern := regexp.MustCompile(`^([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})?$`)
+	validUUID := "0b7e3b0e-6d8f-4a3c-9c1e-2f5d6a7b8c9d"
+
+	testCases := []struct {
+		name      string
+		pattern   *regexp.Regexp
+		memo      string
+		expectErr bool
+	}{
+		{"matching memo", uuidPattern, validUUID, false},
+		{"non matching memo", uuidPattern, "not-a-uuid", true},
+		{"empty memo not allowed by pattern", uuidPattern, "", true},
+		{"empty memo allowed by pattern", optionalUUIDPattern, "", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ValidateMemoFormatMiddleware(tc.pattern))
+
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(msg))
+			txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+			txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+			txBuilder.SetMemo(tc.memo)
+			testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+			s.Require().NoError(err)
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx})
+			_, simulateErr := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx})
+			for _, err := range []error{checkErr, deliverErr, simulateErr} {
+				if tc.expectErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+					if tc.memo != "" {
+						s.Require().NotContains(err.Error(), tc.memo)
+					}
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+		})
+	}
+}
+
 func (s *MWTestSuite) TestConsumeGasForTxSize() {
 	ctx := s.SetupTest(true) // setup
 	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
@@ -171,6 +442,98 @@ func (s *MWTestSuite) TestConsumeGasForTxSize() {
 	}
 }
 
+func (s *MWTestSuite) TestConsumeGasForTxSizeWithSigCost() {
+	ctx := s.SetupTest(true) // setup
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+
+	// keys and addresses
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+
+	// msg and nil signature, as in simulate mode
+	msg := testdata.NewTestMsg(addr1)
+	s.Require().NoError(txBuilder.SetMsgs(msg))
+	txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+	txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+	s.Require().NoError(txBuilder.SetSignatures(signing.SignatureV2{PubKey: priv1.PubKey()}))
+	testTx := txBuilder.GetTx()
+	txBytes, err := s.clientCtx.TxConfig.TxEncoder()(testTx)
+	s.Require().NoError(err)
+
+	simulateGas := func(txHandler tx.Handler) sdk.Gas {
+		ctx := ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+		_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx, TxBytes: txBytes})
+		s.Require().NoError(err)
+
+		return ctx.GasMeter().GasConsumed()
+	}
+	sigCost := func(cost sdk.Gas) func(cryptotypes.PubKey) sdk.Gas {
+		return func(pubkey cryptotypes.PubKey) sdk.Gas {
+			// the signer account doesn't exist, so the placeholder secp256k1 pubkey is used
+			s.Require().IsType(&secp256k1.PubKey{}, pubkey)
+			return cost
+		}
+	}
+
+	defaultGas := simulateGas(middleware.ComposeMiddlewares(noopTxHandler, middleware.ConsumeTxSizeGasMiddleware(s.app.AccountKeeper)))
+	nilSigCostGas := simulateGas(middleware.ComposeMiddlewares(noopTxHandler, middleware.ConsumeTxSizeGasMiddlewareWithSigCost(s.app.AccountKeeper, nil)))
+	s.Require().Equal(defaultGas, nilSigCostGas)
+
+	gas100 := simulateGas(middleware.ComposeMiddlewares(noopTxHandler, middleware.ConsumeTxSizeGasMiddlewareWithSigCost(s.app.AccountKeeper, sigCost(100))))
+	gas200 := simulateGas(middleware.ComposeMiddlewares(noopTxHandler, middleware.ConsumeTxSizeGasMiddlewareWithSigCost(s.app.AccountKeeper, sigCost(200))))
+	params := s.app.AccountKeeper.GetParams(ctx)
+	s.Require().Equal(100*params.TxSizeCostPerByte, gas200-gas100)
+
+	// the sig cost can also be set as an option
+	optionGas := simulateGas(middleware.ComposeMiddlewares(noopTxHandler, middleware.ConsumeTxSizeGasMiddlewareWithOptions(s.app.AccountKeeper, middleware.WithSigCost(sigCost(100)))))
+	s.Require().Equal(gas100, optionGas)
+}
+
+func (s *MWTestSuite) TestConsumeGasForTxSizeDeterministicMode() {
+	ctx := s.SetupTest(true) // setup
+
+	// signing account with a multisig pubkey in state
+	accounts := s.createTestAccounts(ctx, 1, sdk.NewCoins())
+	acc := accounts[0].acc
+	priv2, priv3 := secp256k1.GenPrivKey(), secp256k1.GenPrivKey()
+	multisigKey := kmultisig.NewLegacyAminoPubKey(2, []cryptotypes.PubKey{priv2.PubKey(), priv3.PubKey()})
+	s.Require().NoError(acc.SetPubKey(multisigKey))
+	s.app.AccountKeeper.SetAccount(ctx, acc)
+
+	createSimTx := func(addr sdk.AccAddress) (sdk.Tx, []byte) {
+		txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+		s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(addr)))
+		txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+		txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+		s.Require().NoError(txBuilder.SetSignatures(signing.SignatureV2{PubKey: accounts[0].priv.PubKey()}))
+		txBytes, err := s.clientCtx.TxConfig.TxEncoder()(txBuilder.GetTx())
+		s.Require().NoError(err)
+
+		return txBuilder.GetTx(), txBytes
+	}
+	simulateGas := func(txHandler tx.Handler, testTx sdk.Tx, txBytes []byte) sdk.Gas {
+		ctx := ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+		_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx, TxBytes: txBytes})
+		s.Require().NoError(err)
+
+		return ctx.GasMeter().GasConsumed()
+	}
+
+	stateAware := middleware.ComposeMiddlewares(noopTxHandler, middleware.ConsumeTxSizeGasMiddlewareWithOptions(s.app.AccountKeeper))
+	deterministic := middleware.ComposeMiddlewares(noopTxHandler, middleware.ConsumeTxSizeGasMiddlewareWithOptions(s.app.AccountKeeper, middleware.DeterministicMode(true)))
+
+	// the multisig pubkey in state is only used in state-aware mode
+	knownTx, knownTxBytes := createSimTx(acc.GetAddress())
+	deterministicGas := simulateGas(deterministic, knownTx, knownTxBytes)
+	s.Require().Greater(simulateGas(stateAware, knownTx, knownTxBytes), deterministicGas)
+
+	// in deterministic mode, a tx of the same size signed by an unknown account
+	// is estimated the same
+	_, _, unknownAddr := testdata.KeyTestPubAddr()
+	unknownTx, unknownTxBytes := createSimTx(unknownAddr)
+	s.Require().Equal(len(knownTxBytes), len(unknownTxBytes))
+	s.Require().Equal(deterministicGas, simulateGas(deterministic, unknownTx, unknownTxBytes))
+}
+
 func (s *MWTestSuite) TestTxHeightTimeoutMiddleware() {
 	ctx := s.SetupTest(true)
 
@@ -219,3 +582,126 @@ func (s *MWTestSuite) TestTxHeightTimeoutMiddleware() {
 		})
 	}
 }
+
+func (s *MWTestSuite) TestTxHeightTimeoutGracePeriod() {
+	ctx := s.SetupTest(true)
+
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.TxTimeoutHeightMiddlewareWithGracePeriod(2))
+
+	// keys and addresses
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+	s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(addr1)))
+	txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+	txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+	txBuilder.SetTimeoutHeight(10)
+
+	privs, accNums, accSeqs := []cryptotypes.PrivKey{priv1}, []uint64{0}, []uint64{0}
+	testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name             string
+		height           int64
+		expectCheckErr   bool
+		expectDeliverErr bool
+	}{
+		{"before timeout", 10, false, false},
+		{"within grace period", 12, false, true},
+		{"after grace period", 13, true, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			ctx := ctx.WithBlockHeight(tc.height)
+			req := tx.Request{Tx: testTx}
+
+			_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			if tc.expectCheckErr {
+				s.Require().ErrorIs(err, sdkerrors.ErrTxTimeoutHeight)
+			} else {
+				s.Require().NoError(err)
+			}
+
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			_, simErr := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{deliverErr, simErr} {
+				if tc.expectDeliverErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrTxTimeoutHeight)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+		})
+	}
+}
+
+// timeoutTimestampTx is a test tx implementing sdk.TxWithTimeoutTimestamp.
+type timeoutTimestampTx struct {
+	sdk.Tx
+	timeout time.Time
+}
+
+func (t timeoutTimestampTx) GetTimeoutTimestamp() time.Time { return t.timeout }
+
+func (s *MWTestSuite) TestTxTimeoutTimestampMiddleware() {
+	ctx := s.SetupTest(true)
+
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.TxTimeoutTimestampMiddleware)
+
+	// keys and addresses
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+
+	// msg and signatures
+	msg := testdata.NewTestMsg(addr1)
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+	s.Require().NoError(txBuilder.SetMsgs(msg))
+	txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+	txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+
+	privs, accNums, accSeqs := []cryptotypes.PrivKey{priv1}, []uint64{0}, []uint64{0}
+	testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+	s.Require().NoError(err)
+
+	// txs not implementing TxWithTimeoutTimestamp are rejected
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx})
+	s.Require().ErrorIs(err, sdkerrors.ErrTxDecode)
+
+	blockTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		timeout   time.Time
+		checkType abci.CheckTxType
+		expectErr bool
+	}{
+		{"default value", time.Time{}, abci.CheckTxType_New, false},
+		{"no timeout (later timestamp)", blockTime.Add(time.Second), abci.CheckTxType_New, false},
+		{"no timeout (same timestamp)", blockTime, abci.CheckTxType_New, false},
+		{"timeout (earlier timestamp)", blockTime.Add(-time.Second), abci.CheckTxType_New, true},
+		{"timeout skipped on recheck", blockTime.Add(-time.Second), abci.CheckTxType_Recheck, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			ctx := ctx.WithBlockTime(blockTime)
+			req := tx.Request{Tx: timeoutTimestampTx{Tx: testTx, timeout: tc.timeout}}
+
+			_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{Type: tc.checkType})
+			s.Require().Equal(tc.expectErr, err != nil, err)
+
+			if tc.checkType == abci.CheckTxType_New {
+				_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+				s.Require().Equal(tc.expectErr, err != nil, err)
+				if tc.expectErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrTxTimeout)
+				}
+			}
+		})
+	}
+}
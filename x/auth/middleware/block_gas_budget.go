@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type blockGasBudgetTxHandler struct {
+	maxBlockGas uint64
+	next        tx.Handler
+}
+
+// BlockGasBudgetMiddleware defines a middleware that rejects in DeliverTx the
+// txs of a block once maxBlockGas gas was consumed by its previous txs, to
+// keep block execution time bounded. CheckTx and SimulateTx are passed
+// through. A maxBlockGas of 0 means no budget.
+//
+// The gas consumed in the block is read from the block gas meter of the
+// sdk.Context, which is reset by BaseApp at each BeginBlock and filled by
+// ConsumeBlockGasMiddleware. The block gas meter itself is limited by the
+// Block.MaxGas consensus parameter, and Tendermint doesn't propose blocks
+// whose txs want more gas than that, so the budget is a soft cap which only
+// has an effect when it is below Block.MaxGas, or when Block.MaxGas is
+// unlimited. Since the gas used by a tx is only known once it ran, the tx
+// crossing the budget is still executed, and only the following txs are
+// rejected.
+// CONTRACT: ConsumeBlockGasMiddleware must be part of the middleware stack.
+func BlockGasBudgetMiddleware(maxBlockGas uint64) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return blockGasBudgetTxHandler{
+			maxBlockGas: maxBlockGas,
+			next:        txh,
+		}
+	}
+}
+
+var _ tx.Handler = blockGasBudgetTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh blockGasBudgetTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh blockGasBudgetTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if txh.maxBlockGas != 0 {
+		consumed := sdk.UnwrapSDKContext(ctx).BlockGasMeter().GasConsumed()
+		if consumed >= txh.maxBlockGas {
+			return tx.Response{}, sdkerrors.Wrapf(sdkerrors.ErrOutOfGas, "block gas budget exhausted: %d consumed, budget %d", consumed, txh.maxBlockGas)
+		}
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh blockGasBudgetTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestBlockGasBudgetMiddleware() {
+	testTx, _, ctx, _ := s.setupGasTx()
+	req := tx.Request{Tx: testTx}
+
+	consumeGasTxHandler := customTxHandler{func(ctx context.Context, _ tx.Request) (tx.Response, error) {
+		sdk.UnwrapSDKContext(ctx).GasMeter().ConsumeGas(1000, "execution")
+		return tx.Response{}, nil
+	}}
+
+	testCases := []struct {
+		name        string
+		maxBlockGas uint64
+		expTxs      int
+	}{
+		{"txs until the budget is exhausted", 2500, 3},
+		{"budget reached exactly", 2000, 2},
+		{"no budget", 0, 5},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(consumeGasTxHandler,
+				middleware.GasTxMiddleware,
+				middleware.BlockGasBudgetMiddleware(tc.maxBlockGas),
+				middleware.ConsumeBlockGasMiddleware,
+			)
+
+			deliverTxs := func(ctx sdk.Context) int {
+				for i := 0; i < 5; i++ {
+					_, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+					if err != nil {
+						s.Require().ErrorIs(err, sdkerrors.ErrOutOfGas)
+						return i
+					}
+				}
+				return 5
+			}
+
+			blockCtx := ctx.WithBlockGasMeter(sdk.NewInfiniteGasMeter())
+			s.Require().Equal(tc.expTxs, deliverTxs(blockCtx))
+
+			// CheckTx and SimulateTx are passed through
+			_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(blockCtx), req, tx.RequestCheckTx{})
+			s.Require().NoError(err)
+			_, err = txHandler.SimulateTx(sdk.WrapSDKContext(blockCtx), req)
+			s.Require().NoError(err)
+
+			// the budget is reset in the next block
+			nextBlockCtx := ctx.WithBlockHeight(ctx.BlockHeight() + 1).WithBlockGasMeter(sdk.NewInfiniteGasMeter())
+			s.Require().Equal(tc.expTxs, deliverTxs(nextBlockCtx))
+		})
+	}
+}
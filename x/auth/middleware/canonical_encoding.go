@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type canonicalEncodingTxHandler struct {
+	txConfig client.TxConfig
+	next     tx.Handler
+}
+
+// CanonicalEncodingMiddleware defines a middleware that rejects in CheckTx and
+// DeliverTx the txs whose bytes, as set in the request or returned by
+// sdk.Context.TxBytes, differ from the encoding of the decoded tx with the
+// TxEncoder of txConfig, with an ErrTxDecode error. Txs thus have a single
+// byte representation, so that their hashes can be used to deduplicate and
+// index them. Txs without bytes are rejected too. SimulateTx is passed
+// through.
+//
+// The check costs an extra marshal of every tx, proportional to its size, and
+// the allocation of its encoding. With the default protobuf TxConfig, only the
+// outer TxRaw is re-encoded, since the decoded tx keeps the signed body and
+// auth info bytes as they were received.
+func CanonicalEncodingMiddleware(txConfig client.TxConfig) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return canonicalEncodingTxHandler{
+			txConfig: txConfig,
+			next:     txh,
+		}
+	}
+}
+
+var _ tx.Handler = canonicalEncodingTxHandler{}
+
+func (txh canonicalEncodingTxHandler) checkCanonicalEncoding(ctx context.Context, req tx.Request) error {
+	txBytes := req.TxBytes
+	if len(txBytes) == 0 {
+		txBytes = sdk.UnwrapSDKContext(ctx).TxBytes()
+	}
+	if len(txBytes) == 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "missing tx bytes")
+	}
+
+	canonical, err := txh.txConfig.TxEncoder()(req.Tx)
+	if err != nil {
+		return sdkerrors.Wrapf(sdkerrors.ErrTxDecode, "can't re-encode tx: %s", err)
+	}
+
+	if !bytes.Equal(txBytes, canonical) {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "tx bytes are not the canonical encoding of the tx")
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh canonicalEncodingTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkCanonicalEncoding(ctx, req); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh canonicalEncodingTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkCanonicalEncoding(ctx, req); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh canonicalEncodingTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
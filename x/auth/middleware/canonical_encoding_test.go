@@ -0,0 +1,75 @@
+package middleware_test
+
+import (
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestCanonicalEncodingMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+	s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(addr1)))
+	txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+	testTx := txBuilder.GetTx()
+	txBytes, err := s.clientCtx.TxConfig.TxEncoder()(testTx)
+	s.Require().NoError(err)
+
+	// repeating the body bytes field decodes to the same tx, and keeps the
+	// fields ordered as required by ADR-027
+	var raw tx.TxRaw
+	s.Require().NoError(raw.Unmarshal(txBytes))
+	bodyField, err := (&tx.TxRaw{BodyBytes: raw.BodyBytes}).Marshal()
+	s.Require().NoError(err)
+	nonCanonical := append(bodyField, txBytes...)
+	decoded, err := s.clientCtx.TxConfig.TxDecoder()(nonCanonical)
+	s.Require().NoError(err)
+
+	otherTxBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+	s.Require().NoError(otherTxBuilder.SetMsgs(testdata.NewTestMsg(addr1)))
+	otherTxBuilder.SetMemo("other")
+	otherTxBytes, err := s.clientCtx.TxConfig.TxEncoder()(otherTxBuilder.GetTx())
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name    string
+		tx      sdk.Tx
+		txBytes []byte
+		expErr  string
+	}{
+		{"canonical bytes", testTx, txBytes, ""},
+		{"non-canonical bytes", decoded, nonCanonical, "tx bytes are not the canonical encoding of the tx"},
+		{"bytes of another tx", testTx, otherTxBytes, "tx bytes are not the canonical encoding of the tx"},
+		{"missing bytes", testTx, nil, "missing tx bytes"},
+		{"tx which can't be encoded", txTest{}, txBytes, "can't re-encode tx"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.CanonicalEncodingMiddleware(s.clientCtx.TxConfig))
+			req := tx.Request{Tx: tc.tx}
+			sdkCtx := ctx.WithTxBytes(tc.txBytes)
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(sdkCtx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(sdkCtx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expErr != "" {
+					s.Require().ErrorIs(err, sdkerrors.ErrTxDecode)
+					s.Require().Contains(err.Error(), tc.expErr)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+
+			// SimulateTx is passed through
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(sdkCtx), req)
+			s.Require().NoError(err)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type chainIDTxHandler struct {
+	expected string
+	next     tx.Handler
+}
+
+// ChainIDMiddleware defines a middleware that rejects all txs in CheckTx and
+// DeliverTx if the chain-id of the context isn't expected, as a guard against
+// a node misconfigured with the genesis of another chain. Sign bytes already
+// include the chain-id, so this check is only a cheap safety net: it doesn't
+// read the tx. SimulateTx is passed through.
+func ChainIDMiddleware(expected string) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return chainIDTxHandler{
+			expected: expected,
+			next:     txh,
+		}
+	}
+}
+
+var _ tx.Handler = chainIDTxHandler{}
+
+func (txh chainIDTxHandler) checkChainID(ctx context.Context) error {
+	chainID := sdk.UnwrapSDKContext(ctx).ChainID()
+	if chainID != txh.expected {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidChainID, "got %q, expected %q", chainID, txh.expected)
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh chainIDTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkChainID(ctx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh chainIDTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkChainID(ctx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh chainIDTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
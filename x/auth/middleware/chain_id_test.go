@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestChainIDMiddleware() {
+	ctx := s.SetupTest(true).WithChainID("test-chain")
+	req := tx.Request{Tx: txTest{}}
+
+	testCases := []struct {
+		name     string
+		expected string
+		expErr   bool
+	}{
+		{"matching chain-id", "test-chain", false},
+		{"other chain-id", "other-chain", true},
+		{"empty chain-id", "", true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ChainIDMiddleware(tc.expected))
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrInvalidChainID)
+					s.Require().Contains(err.Error(), "test-chain")
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+
+			// SimulateTx is passed through
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+
+	"github.com/gogo/protobuf/proto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type dedupMsgTxHandler struct {
+	allowEmpty bool
+	next       tx.Handler
+}
+
+// DedupMsgMiddleware defines a middleware that rejects txs containing two
+// identical messages, i.e. messages with the same type URL and the same
+// marshaled bytes. The error includes the index of the first duplicate
+// message. Txs without any message are rejected too, unless allowEmpty is
+// true. It runs in CheckTx, DeliverTx and SimulateTx.
+func DedupMsgMiddleware(allowEmpty bool) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return dedupMsgTxHandler{
+			allowEmpty: allowEmpty,
+			next:       txh,
+		}
+	}
+}
+
+var _ tx.Handler = dedupMsgTxHandler{}
+
+func (txh dedupMsgTxHandler) checkMsgs(sdkTx sdk.Tx) error {
+	msgs := sdkTx.GetMsgs()
+	if len(msgs) == 0 {
+		if txh.allowEmpty {
+			return nil
+		}
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "tx must contain at least one message")
+	}
+
+	seen := make(map[[sha256.Size]byte]int, len(msgs))
+	for i, msg := range msgs {
+		bz, err := proto.Marshal(msg)
+		if err != nil {
+			return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "can't marshal message %d: %s", i, err)
+		}
+
+		// the type URL is hashed too, since messages of different types can
+		// have the same bytes
+		h := sha256.New()
+		h.Write([]byte(sdk.MsgTypeURL(msg)))
+		h.Write([]byte{0})
+		h.Write(bz)
+		var hash [sha256.Size]byte
+		copy(hash[:], h.Sum(nil))
+
+		if first, ok := seen[hash]; ok {
+			return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "message %d is a duplicate of message %d", i, first)
+		}
+		seen[hash] = i
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh dedupMsgTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkMsgs(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh dedupMsgTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkMsgs(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh dedupMsgTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkMsgs(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.SimulateTx(ctx, req)
+}
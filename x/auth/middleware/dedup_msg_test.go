@@ -0,0 +1,56 @@
+package middleware_test
+
+import (
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+// msgsTx is a dummy tx with the given messages.
+type msgsTx []sdk.Msg
+
+var _ sdk.Tx = msgsTx{}
+
+func (t msgsTx) GetMsgs() []sdk.Msg   { return t }
+func (t msgsTx) ValidateBasic() error { return nil }
+
+func (s *MWTestSuite) TestDedupMsgMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+	_, _, addr2 := testdata.KeyTestPubAddr()
+
+	testCases := []struct {
+		name       string
+		tx         sdk.Tx
+		allowEmpty bool
+		expErr     string
+	}{
+		{"distinct messages", msgsTx{testdata.NewTestMsg(addr1), testdata.NewTestMsg(addr2)}, false, ""},
+		{"same bytes with different types", msgsTx{&testdata.TestMsg{}, &testdata.MsgCreateDog{}}, false, ""},
+		{"duplicate messages", msgsTx{testdata.NewTestMsg(addr1), testdata.NewTestMsg(addr2), testdata.NewTestMsg(addr1)}, false, "message 2 is a duplicate of message 0"},
+		{"empty tx", txTest{}, false, "at least one message"},
+		{"allowed empty tx", txTest{}, true, ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.DedupMsgMiddleware(tc.allowEmpty))
+			req := tx.Request{Tx: tc.tx}
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			_, simulateErr := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr, simulateErr} {
+				if tc.expErr != "" {
+					s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+					s.Require().Contains(err.Error(), tc.expErr)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+		})
+	}
+}
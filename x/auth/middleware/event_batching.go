@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type eventBufferKey struct{}
+
+// EventBuffer returns the event manager buffering the events of the tx being
+// delivered, as set by EventBatchingMiddleware, or the event manager of the
+// context outside of DeliverTx or without EventBatchingMiddleware, so that
+// modules can always emit their events to it.
+func EventBuffer(ctx sdk.Context) *sdk.EventManager {
+	if buf, ok := ctx.Value(eventBufferKey{}).(*sdk.EventManager); ok {
+		return buf
+	}
+	return ctx.EventManager()
+}
+
+type eventBatchingTxHandler struct {
+	next tx.Handler
+}
+
+// EventBatchingMiddleware defines a middleware that sets in the context of
+// DeliverTx an event manager which can be read with EventBuffer, and buffers
+// the events emitted to it until the next handler returns. If the tx
+// succeeds, the buffered events are appended in a single batch, in emission
+// order, after the events of the response. If it fails, they are dropped,
+// like the state changes of the tx. Events emitted directly to the event
+// manager of the context are not affected. CheckTx and SimulateTx are passed
+// through.
+func EventBatchingMiddleware(txh tx.Handler) tx.Handler {
+	return eventBatchingTxHandler{next: txh}
+}
+
+var _ tx.Handler = eventBatchingTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh eventBatchingTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh eventBatchingTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	buf := sdk.NewEventManager()
+	sdkCtx := sdk.UnwrapSDKContext(ctx).WithValue(eventBufferKey{}, buf)
+
+	res, err := txh.next.DeliverTx(sdk.WrapSDKContext(sdkCtx), req)
+	if err != nil {
+		return res, err
+	}
+
+	res.Events = append(res.Events, buf.ABCIEvents()...)
+	return res, nil
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh eventBatchingTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,92 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+// emitEventsTxHandler returns a test middleware emitting n events to the event
+// buffer and returning the events of the event manager of the context in its
+// response, like runMsgs, or failing if fail is set.
+func emitEventsTxHandler(n int, fail bool) customTxHandler {
+	return customTxHandler{func(ctx context.Context, _ tx.Request) (tx.Response, error) {
+		sdkCtx := sdk.UnwrapSDKContext(ctx)
+		sdkCtx.EventManager().EmitEvent(sdk.NewEvent("direct"))
+		buf := middleware.EventBuffer(sdkCtx)
+		for i := 0; i < n; i++ {
+			buf.EmitEvent(sdk.NewEvent("buffered", sdk.NewAttribute("index", fmt.Sprint(i))))
+		}
+		if fail {
+			return tx.Response{}, errors.New("tx failed")
+		}
+
+		return tx.Response{Events: sdkCtx.EventManager().ABCIEvents()}, nil
+	}}
+}
+
+func (s *MWTestSuite) TestEventBatchingMiddleware() {
+	ctx := s.SetupTest(true)
+	req := tx.Request{Tx: txTest{}}
+
+	// buffered events are appended in order after the response events
+	txHandler := middleware.ComposeMiddlewares(emitEventsTxHandler(3, false), middleware.EventBatchingMiddleware)
+	res, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx.WithEventManager(sdk.NewEventManager())), req)
+	s.Require().NoError(err)
+	s.Require().Len(res.Events, 4)
+	s.Require().Equal("direct", res.Events[0].Type)
+	for i, event := range res.Events[1:] {
+		s.Require().Equal("buffered", event.Type)
+		s.Require().Equal(fmt.Sprint(i), string(event.Attributes[0].Value))
+	}
+
+	// buffered events are dropped when the tx fails
+	em := sdk.NewEventManager()
+	txHandler = middleware.ComposeMiddlewares(emitEventsTxHandler(3, true), middleware.EventBatchingMiddleware)
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx.WithEventManager(em)), req)
+	s.Require().EqualError(err, "tx failed")
+	s.Require().Len(em.Events(), 1)
+
+	// outside of DeliverTx, events are emitted to the event manager of the context
+	res, err = middleware.ComposeMiddlewares(emitEventsTxHandler(2, false), middleware.EventBatchingMiddleware).
+		SimulateTx(sdk.WrapSDKContext(ctx.WithEventManager(sdk.NewEventManager())), req)
+	s.Require().NoError(err)
+	s.Require().Len(res.Events, 3)
+}
+
+func BenchmarkEventBatchingMiddleware(b *testing.B) {
+	sdkCtx := sdk.NewContext(nil, tmproto.Header{}, false, log.NewNopLogger())
+	req := tx.Request{Tx: txTest{}}
+
+	for _, numEvents := range []int{1, 10, 100} {
+		for _, bc := range []struct {
+			name      string
+			txHandler tx.Handler
+		}{
+			{"unbuffered", emitEventsTxHandler(numEvents, false)},
+			{"buffered", middleware.ComposeMiddlewares(emitEventsTxHandler(numEvents, false), middleware.EventBatchingMiddleware)},
+		} {
+			bc := bc
+			b.Run(fmt.Sprintf("%s/%d events", bc.name, numEvents), func(b *testing.B) {
+				b.ReportAllocs()
+				var res tx.Response
+				var err error
+				for i := 0; i < b.N; i++ {
+					ctx := sdk.WrapSDKContext(sdkCtx.WithEventManager(sdk.NewEventManager()))
+					res, err = bc.txHandler.DeliverTx(ctx, req)
+					require.NoError(b, err)
+				}
+				require.Len(b, res.Events, numEvents+1)
+			})
+		}
+	}
+}
@@ -18,3 +18,9 @@ type AccountKeeper interface {
 type FeegrantKeeper interface {
 	UseGrantedFees(ctx sdk.Context, granter, grantee sdk.AccAddress, fee sdk.Coins, msgs []sdk.Msg) error
 }
+
+// FeeAllowanceKeeper defines the expected feegrant keeper of
+// FeePayerMiddleware, which checks allowances without using them.
+type FeeAllowanceKeeper interface {
+	CheckGrantedFees(ctx sdk.Context, granter, grantee sdk.AccAddress, fee sdk.Coins, msgs []sdk.Msg) error
+}
@@ -67,3 +67,70 @@ func (txh rejectExtensionOptionsTxHandler) SimulateTx(ctx context.Context, req t
 
 	return txh.next.SimulateTx(ctx, req)
 }
+
+type extensionOptionsTxHandler struct {
+	allowed map[string]bool
+	next    tx.Handler
+}
+
+// ExtensionOptionsMiddleware defines a middleware that rejects the txs
+// carrying an extension option, critical or non-critical, whose type URL isn't
+// allowed, with an ErrUnknownExtensionOptions error reporting the type URL. An
+// empty allowed map rejects all extension options, like
+// RejectExtensionOptionsMiddleware. This middleware only filters extension
+// options, a middleware handling the allowed ones must still be added to the
+// chain. Txs which don't implement HasExtensionOptionsTx are passed through.
+func ExtensionOptionsMiddleware(allowed map[string]bool) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return extensionOptionsTxHandler{
+			allowed: allowed,
+			next:    txh,
+		}
+	}
+}
+
+var _ tx.Handler = extensionOptionsTxHandler{}
+
+func (txh extensionOptionsTxHandler) checkExtOpts(sdkTx sdk.Tx) error {
+	hasExtOptsTx, ok := sdkTx.(HasExtensionOptionsTx)
+	if !ok {
+		return nil
+	}
+
+	for _, opts := range [][]*codectypes.Any{hasExtOptsTx.GetExtensionOptions(), hasExtOptsTx.GetNonCriticalExtensionOptions()} {
+		for _, opt := range opts {
+			if !txh.allowed[opt.TypeUrl] {
+				return sdkerrors.Wrapf(sdkerrors.ErrUnknownExtensionOptions, "extension option %s is not allowed", opt.TypeUrl)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh extensionOptionsTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkExtOpts(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh extensionOptionsTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkExtOpts(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh extensionOptionsTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkExtOpts(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec/types"
 	"github.com/cosmos/cosmos-sdk/testutil/testdata"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	typestx "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
 	"github.com/cosmos/cosmos-sdk/x/auth/tx"
@@ -34,3 +35,55 @@ func (s *MWTestSuite) TestRejectExtensionOptionsMiddleware() {
 	_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), typestx.Request{Tx: theTx}, typestx.RequestCheckTx{})
 	s.Require().EqualError(err, "unknown extension options")
 }
+
+func (s *MWTestSuite) TestExtensionOptionsMiddleware() {
+	ctx := s.SetupTest(true) // setup
+
+	allowedOpt, err := types.NewAnyWithValue(testdata.NewTestMsg())
+	s.Require().NoError(err)
+	otherOpt, err := types.NewAnyWithValue(&testdata.Dog{})
+	s.Require().NoError(err)
+	allowed := map[string]bool{allowedOpt.TypeUrl: true}
+
+	testCases := []struct {
+		name           string
+		allowed        map[string]bool
+		extOpts        []*types.Any
+		nonCritOpts    []*types.Any
+		expRejectedOpt string
+	}{
+		{"no extension options", allowed, nil, nil, ""},
+		{"allowed extension option", allowed, []*types.Any{allowedOpt}, nil, ""},
+		{"allowed non-critical extension option", allowed, nil, []*types.Any{allowedOpt}, ""},
+		{"disallowed extension option", allowed, []*types.Any{allowedOpt, otherOpt}, nil, otherOpt.TypeUrl},
+		{"disallowed non-critical extension option", allowed, nil, []*types.Any{otherOpt}, otherOpt.TypeUrl},
+		{"empty allowlist", nil, []*types.Any{allowedOpt}, nil, allowedOpt.TypeUrl},
+		{"empty allowlist without extension options", nil, nil, nil, ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			extOptsTxBldr, ok := txBuilder.(tx.ExtensionOptionsTxBuilder)
+			s.Require().True(ok)
+			extOptsTxBldr.SetExtensionOptions(tc.extOpts...)
+			extOptsTxBldr.SetNonCriticalExtensionOptions(tc.nonCritOpts...)
+			req := typestx.Request{Tx: txBuilder.GetTx()}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ExtensionOptionsMiddleware(tc.allowed))
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, typestx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			_, simulateErr := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr, simulateErr} {
+				if tc.expRejectedOpt != "" {
+					s.Require().ErrorIs(err, sdkerrors.ErrUnknownExtensionOptions)
+					s.Require().Contains(err.Error(), tc.expRejectedOpt)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type feeDenomWhitelistTxHandler struct {
+	allowed map[string]bool
+	next    tx.Handler
+}
+
+// FeeDenomWhitelistMiddleware defines a middleware that rejects txs paying
+// fees in a denom which isn't in allowed, with an error naming the first such
+// denom. It runs in CheckTx, DeliverTx and SimulateTx. An empty allowed set
+// accepts fees in any denom.
+// CONTRACT: Tx must implement FeeTx to use FeeDenomWhitelistMiddleware
+func FeeDenomWhitelistMiddleware(allowed map[string]bool) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return feeDenomWhitelistTxHandler{
+			allowed: allowed,
+			next:    txh,
+		}
+	}
+}
+
+var _ tx.Handler = feeDenomWhitelistTxHandler{}
+
+func (txh feeDenomWhitelistTxHandler) checkFeeDenoms(sdkTx sdk.Tx) error {
+	if len(txh.allowed) == 0 {
+		return nil
+	}
+
+	feeTx, ok := sdkTx.(sdk.FeeTx)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be a FeeTx")
+	}
+
+	for _, coin := range feeTx.GetFee() {
+		if !txh.allowed[coin.Denom] {
+			return sdkerrors.Wrapf(sdkerrors.ErrInvalidCoins, "fee denom %s is not allowed", coin.Denom)
+		}
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh feeDenomWhitelistTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkFeeDenoms(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh feeDenomWhitelistTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkFeeDenoms(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh feeDenomWhitelistTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkFeeDenoms(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.SimulateTx(ctx, req)
+}
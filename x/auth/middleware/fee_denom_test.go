@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestFeeDenomWhitelistMiddleware() {
+	ctx := s.SetupTest(true)
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+
+	testCases := []struct {
+		name       string
+		allowed    map[string]bool
+		fee        sdk.Coins
+		invalidFee string
+	}{
+		{"empty allowlist accepts any denom", nil, sdk.NewCoins(sdk.NewInt64Coin("foo", 1)), ""},
+		{"allowed denom", map[string]bool{"atom": true}, sdk.NewCoins(sdk.NewInt64Coin("atom", 150)), ""},
+		{"no fee", map[string]bool{"atom": true}, nil, ""},
+		{"allowed denoms", map[string]bool{"atom": true, "stake": true}, sdk.NewCoins(sdk.NewInt64Coin("atom", 1), sdk.NewInt64Coin("stake", 1)), ""},
+		{"denom not allowed", map[string]bool{"atom": true}, sdk.NewCoins(sdk.NewInt64Coin("foo", 1)), "foo"},
+		{"some denoms not allowed", map[string]bool{"stake": true}, sdk.NewCoins(sdk.NewInt64Coin("atom", 1), sdk.NewInt64Coin("bar", 1), sdk.NewInt64Coin("stake", 1)), "atom"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(addr1)))
+			txBuilder.SetFeeAmount(tc.fee)
+			txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+			privs, accNums, accSeqs := []cryptotypes.PrivKey{priv1}, []uint64{0}, []uint64{0}
+			testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+			s.Require().NoError(err)
+
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.FeeDenomWhitelistMiddleware(tc.allowed))
+			req := tx.Request{Tx: testTx}
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			_, simulateErr := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr, simulateErr} {
+				if tc.invalidFee != "" {
+					s.Require().ErrorIs(err, sdkerrors.ErrInvalidCoins)
+					s.Require().Contains(err.Error(), "fee denom "+tc.invalidFee+" ")
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+		})
+	}
+}
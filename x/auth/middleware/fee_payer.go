@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+type feePayerTxHandler struct {
+	feegrantKeeper FeeAllowanceKeeper
+	next           tx.Handler
+}
+
+// FeePayerMiddleware defines a middleware that rejects in CheckTx and
+// DeliverTx the txs whose fees aren't paid by one of their signers, unless the
+// fee granter allows the fee payer, which must be a signer, to pay them. The
+// allowance is read with fk and charged to the gas meter of the tx, but not
+// updated, so that it is only used by DeductFeeMiddleware. Txs without a fee
+// granter, or whose fee granter is a signer, are passed through if the fee
+// payer is a signer. SimulateTx is passed through. A nil fk rejects all fee
+// grants from accounts which aren't signers.
+// CONTRACT: Tx must implement FeeTx and SigVerifiableTx interfaces
+func FeePayerMiddleware(fk FeeAllowanceKeeper) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return feePayerTxHandler{
+			feegrantKeeper: fk,
+			next:           txh,
+		}
+	}
+}
+
+var _ tx.Handler = feePayerTxHandler{}
+
+func (txh feePayerTxHandler) checkFeePayer(ctx context.Context, sdkTx sdk.Tx) error {
+	feeTx, ok := sdkTx.(sdk.FeeTx)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be a FeeTx")
+	}
+
+	sigTx, ok := sdkTx.(authsigning.SigVerifiableTx)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "invalid transaction type")
+	}
+
+	signers := sigTx.GetSigners()
+	isSigner := func(addr sdk.AccAddress) bool {
+		for _, signer := range signers {
+			if signer.Equals(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	feePayer := feeTx.FeePayer()
+	if !isSigner(feePayer) {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "fee payer %s is not a signer", feePayer)
+	}
+
+	feeGranter := feeTx.FeeGranter()
+	if feeGranter == nil || isSigner(feeGranter) {
+		return nil
+	}
+
+	if txh.feegrantKeeper == nil {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "fee granter %s is not a signer and fee grants are not enabled", feeGranter)
+	}
+
+	// reading the allowance consumes gas from the tx gas meter, while the gas
+	// of updating it is only consumed once, by DeductFeeMiddleware
+	err := txh.feegrantKeeper.CheckGrantedFees(sdk.UnwrapSDKContext(ctx), feeGranter, feePayer, feeTx.GetFee(), sdkTx.GetMsgs())
+	if err != nil {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "%s does not allow %s to pay fees: %s", feeGranter, feePayer, err)
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh feePayerTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkFeePayer(ctx, req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh feePayerTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkFeePayer(ctx, req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh feePayerTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,87 @@
+package middleware_test
+
+import (
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+)
+
+func (s *MWTestSuite) TestFeePayerMiddleware() {
+	ctx := s.SetupTest(false)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+	_, _, addr2 := testdata.KeyTestPubAddr()
+	_, _, addr3 := testdata.KeyTestPubAddr()
+
+	// addr2 allows addr1 to pay up to 100atom of fees
+	spendLimit := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
+	err := s.app.FeeGrantKeeper.GrantAllowance(ctx, addr2, addr1, &feegrant.BasicAllowance{SpendLimit: spendLimit})
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name       string
+		signers    []sdk.AccAddress
+		feePayer   sdk.AccAddress
+		feeGranter sdk.AccAddress
+		fee        int64
+		noFeegrant bool
+		expErr     string
+	}{
+		{"signer pays", []sdk.AccAddress{addr1}, nil, nil, 150, false, ""},
+		{"other signer pays", []sdk.AccAddress{addr1, addr3}, addr3, nil, 150, false, ""},
+		{"signer granter", []sdk.AccAddress{addr1, addr3}, nil, addr3, 150, false, ""},
+		{"granted fees", []sdk.AccAddress{addr1}, nil, addr2, 50, false, ""},
+		{"fees above the allowance", []sdk.AccAddress{addr1}, nil, addr2, 150, false, "does not allow"},
+		{"no allowance", []sdk.AccAddress{addr1}, nil, addr3, 50, false, "does not allow"},
+		{"fee grants not enabled", []sdk.AccAddress{addr1}, nil, addr2, 50, true, "not enabled"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(tc.signers...)))
+			txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("atom", tc.fee)))
+			txBuilder.SetFeePayer(tc.feePayer)
+			txBuilder.SetFeeGranter(tc.feeGranter)
+			req := tx.Request{Tx: txBuilder.GetTx()}
+
+			var fk middleware.FeeAllowanceKeeper = s.app.FeeGrantKeeper
+			if tc.noFeegrant {
+				fk = nil
+			}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.FeePayerMiddleware(fk))
+
+			gasCtx := ctx.WithGasMeter(sdk.NewGasMeter(1000000))
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(gasCtx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(gasCtx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expErr != "" {
+					s.Require().ErrorIs(err, sdkerrors.ErrUnauthorized)
+					s.Require().Contains(err.Error(), tc.expErr)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+
+			// reading the allowance is charged to the tx
+			checksAllowance := tc.feeGranter != nil && !tc.noFeegrant
+			for _, signer := range tc.signers {
+				checksAllowance = checksAllowance && !signer.Equals(tc.feeGranter)
+			}
+			s.Require().Equal(checksAllowance, gasCtx.GasMeter().GasConsumed() > 0)
+
+			// SimulateTx is passed through
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+
+	// the allowance isn't used
+	allowance, err := s.app.FeeGrantKeeper.GetAllowance(ctx, addr2, addr1)
+	s.Require().NoError(err)
+	s.Require().Equal(spendLimit, allowance.(*feegrant.BasicAllowance).SpendLimit)
+}
@@ -59,8 +59,15 @@ func (txh gasTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Resp
 	}
 
 	res, err := txh.next.SimulateTx(sdk.WrapSDKContext(sdkCtx), req)
+	res = populateGas(res, sdkCtx)
 
-	return populateGas(res, sdkCtx), err
+	// validate-only simulations don't estimate gas, see
+	// ValidateOnlySimulateMiddleware
+	if tx.IsValidateOnlySimulate(sdkCtx) {
+		res.GasUsed = 0
+	}
+
+	return res, err
 }
 
 // populateGas returns a new tx.Response with gas fields populated.
@@ -89,6 +89,51 @@ func (s *MWTestSuite) TestRecoverPanic() {
 	}, "Recovered from non-Out-of-Gas panic")
 }
 
+func (s *MWTestSuite) TestRecoverPanicWithHandler() {
+	testTx, txBytes, ctx, _ := s.setupGasTx()
+	errCustom := sdkerrors.Register("recovery_test", 2, "custom panic")
+	recoveryHandler := func(recoveryObj interface{}) error {
+		if str, ok := recoveryObj.(string); ok && str == "custom" {
+			return errCustom
+		}
+
+		return nil
+	}
+	panicTxHandler := func(obj interface{}) tx.Handler {
+		return customTxHandler{func(_ context.Context, _ tx.Request) (tx.Response, error) {
+			panic(obj)
+		}}
+	}
+
+	testcases := []struct {
+		name   string
+		next   tx.Handler
+		expErr error
+	}{
+		{"out of gas", outOfGasTxHandler, sdkerrors.ErrOutOfGas},
+		{"handled by recovery handler", panicTxHandler("custom"), errCustom},
+		{"not handled by recovery handler", panicTxHandler("other"), sdkerrors.ErrPanic},
+	}
+	for _, tc := range testcases {
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(tc.next, middleware.GasTxMiddleware, middleware.RecoveryMiddleware(recoveryHandler))
+			req := tx.Request{Tx: testTx, TxBytes: txBytes}
+
+			_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			s.Require().ErrorIs(err, tc.expErr)
+
+			_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().ErrorIs(err, tc.expErr)
+
+			// simulate mode uses an infinite gas meter, so it can't run out of gas
+			if tc.expErr != sdkerrors.ErrOutOfGas {
+				_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+				s.Require().ErrorIs(err, tc.expErr)
+			}
+		})
+	}
+}
+
 // customTxHandler is a test middleware that will run a custom function.
 type customTxHandler struct {
 	fn func(context.Context, tx.Request) (tx.Response, error)
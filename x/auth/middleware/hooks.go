@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// TxHooks defines callbacks run by the HooksMiddleware around DeliverTx.
+type TxHooks interface {
+	// BeforeTx is called before the inner middlewares' DeliverTx.
+	BeforeTx(ctx context.Context, tx sdk.Tx)
+	// AfterTx is called after the inner middlewares' DeliverTx, with the error
+	// it returned, if any.
+	AfterTx(ctx context.Context, tx sdk.Tx, err error)
+}
+
+type hooksTxHandler struct {
+	hooks TxHooks
+	next  tx.Handler
+}
+
+// HooksMiddleware defines a middleware that calls the given hooks before and
+// after DeliverTx. Panics in hooks are recovered and logged, so that they don't
+// affect tx processing. CheckTx and SimulateTx don't call the hooks.
+func HooksMiddleware(hooks TxHooks) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return hooksTxHandler{
+			hooks: hooks,
+			next:  txh,
+		}
+	}
+}
+
+var _ tx.Handler = hooksTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh hooksTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh hooksTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	runHook(ctx, "BeforeTx", func() { txh.hooks.BeforeTx(ctx, req.Tx) })
+
+	res, err := txh.next.DeliverTx(ctx, req)
+
+	runHook(ctx, "AfterTx", func() { txh.hooks.AfterTx(ctx, req.Tx, err) })
+
+	return res, err
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh hooksTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
+
+// runHook runs the given hook, recovering and logging any panic.
+func runHook(ctx context.Context, name string, hook func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			sdk.UnwrapSDKContext(ctx).Logger().Error("tx hook panicked", "hook", name, "recovered", r)
+		}
+	}()
+
+	hook()
+}
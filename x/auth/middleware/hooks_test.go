@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+// testTxHooks is a middleware.TxHooks recording its calls.
+type testTxHooks struct {
+	calls    []string
+	afterErr error
+	panics   bool
+}
+
+var _ middleware.TxHooks = &testTxHooks{}
+
+func (h *testTxHooks) BeforeTx(_ context.Context, _ sdk.Tx) {
+	h.calls = append(h.calls, "before")
+	if h.panics {
+		panic("before")
+	}
+}
+
+func (h *testTxHooks) AfterTx(_ context.Context, _ sdk.Tx, err error) {
+	h.calls = append(h.calls, "after")
+	h.afterErr = err
+	if h.panics {
+		panic("after")
+	}
+}
+
+func (s *MWTestSuite) TestHooksMiddleware() {
+	testTx, _, ctx, _ := s.setupGasTx()
+	req := tx.Request{Tx: testTx}
+
+	var hooks *testTxHooks
+	recordingTxHandler := customTxHandler{func(_ context.Context, _ tx.Request) (tx.Response, error) {
+		hooks.calls = append(hooks.calls, "deliver")
+		return tx.Response{Log: "inner"}, sdkerrors.ErrInvalidRequest
+	}}
+
+	testCases := []struct {
+		name   string
+		panics bool
+	}{
+		{"hooks called around DeliverTx", false},
+		{"panicking hooks are isolated", true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			hooks = &testTxHooks{panics: tc.panics}
+			txHandler := middleware.ComposeMiddlewares(recordingTxHandler, middleware.HooksMiddleware(hooks))
+
+			res, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+			s.Require().Equal("inner", res.Log)
+			s.Require().Equal([]string{"before", "deliver", "after"}, hooks.calls)
+			s.Require().ErrorIs(hooks.afterErr, sdkerrors.ErrInvalidRequest)
+
+			// hooks are not called on CheckTx and SimulateTx
+			hooks.calls = nil
+			_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			s.Require().Error(err)
+			_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().Error(err)
+			s.Require().Equal([]string{"deliver", "deliver"}, hooks.calls)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type idempotencyTxHandler struct {
+	idempotentErrors []*sdkerrors.Error
+	next             tx.Handler
+}
+
+// IdempotencyMiddleware defines a middleware that turns the DeliverTx errors
+// registered in idempotentErrors, such as "already applied" errors of
+// idempotent messages, into successful no-op responses, with the swallowed
+// error in the response log. Events and msg responses of the failed execution
+// are dropped, gas is still reported. Only errors which are, or wrap, one of
+// idempotentErrors are swallowed, CheckTx and SimulateTx errors are returned
+// unchanged.
+//
+// It should be placed above WithBranchedStore, so that the state writes of the
+// failed execution are discarded while fees and sequences are still updated.
+func IdempotencyMiddleware(idempotentErrors []*sdkerrors.Error) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return idempotencyTxHandler{
+			idempotentErrors: idempotentErrors,
+			next:             txh,
+		}
+	}
+}
+
+var _ tx.Handler = idempotencyTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh idempotencyTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh idempotencyTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	res, err := txh.next.DeliverTx(ctx, req)
+	if err == nil || !txh.isIdempotentError(err) {
+		return res, err
+	}
+
+	return tx.Response{
+		GasWanted: res.GasWanted,
+		GasUsed:   res.GasUsed,
+		Log:       fmt.Sprintf("idempotent no-op: %s", err),
+	}, nil
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh idempotencyTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
+
+func (txh idempotencyTxHandler) isIdempotentError(err error) bool {
+	for _, idempotentErr := range txh.idempotentErrors {
+		if sdkerrors.IsOf(err, idempotentErr) {
+			return true
+		}
+	}
+
+	return false
+}
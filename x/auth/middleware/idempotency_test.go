@@ -0,0 +1,61 @@
+package middleware_test
+
+import (
+	"context"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+var errAlreadyApplied = sdkerrors.Register("idempotency_test", 2, "already applied")
+
+func (s *MWTestSuite) TestIdempotencyMiddleware() {
+	testTx, _, ctx, _ := s.setupGasTx()
+	req := tx.Request{Tx: testTx}
+
+	testCases := []struct {
+		name  string
+		txErr error
+		expOK bool
+	}{
+		{"no error", nil, true},
+		{"idempotent error", errAlreadyApplied, true},
+		{"wrapped idempotent error", sdkerrors.Wrap(errAlreadyApplied, "proposal 1"), true},
+		{"other error", sdkerrors.ErrUnauthorized, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			errTxHandler := customTxHandler{func(_ context.Context, _ tx.Request) (tx.Response, error) {
+				return tx.Response{GasUsed: 1000, Events: []abci.Event{{Type: "executed"}}}, tc.txErr
+			}}
+			txHandler := middleware.ComposeMiddlewares(errTxHandler, middleware.IdempotencyMiddleware([]*sdkerrors.Error{errAlreadyApplied}))
+
+			res, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			switch {
+			case !tc.expOK:
+				s.Require().ErrorIs(err, tc.txErr)
+			case tc.txErr != nil:
+				s.Require().NoError(err)
+				s.Require().Equal(uint64(1000), res.GasUsed)
+				s.Require().Empty(res.Events)
+				s.Require().Contains(res.Log, "already applied")
+			default:
+				s.Require().NoError(err)
+				s.Require().Len(res.Events, 1)
+			}
+
+			// CheckTx and SimulateTx errors are returned unchanged
+			_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			s.Require().Equal(tc.txErr, err)
+			_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().Equal(tc.txErr, err)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+type loggerTxHandler struct {
+	logger log.Logger
+	next   tx.Handler
+}
+
+// LoggerMiddleware defines a middleware that sets on the sdk.Context passed to
+// the inner middlewares a logger derived from the given one, carrying the tx
+// hash and the tx's first signer. Keepers calling ctx.Logger() then log these
+// fields without extra plumbing. It applies to CheckTx and DeliverTx.
+func LoggerMiddleware(logger log.Logger) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return loggerTxHandler{
+			logger: logger,
+			next:   txh,
+		}
+	}
+}
+
+var _ tx.Handler = loggerTxHandler{}
+
+// withTxLogger returns the context with the enriched logger.
+func (txh loggerTxHandler) withTxLogger(ctx context.Context, req tx.Request) context.Context {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	txBytes := req.TxBytes
+	if len(txBytes) == 0 {
+		txBytes = sdkCtx.TxBytes()
+	}
+
+	keyvals := []interface{}{"tx_hash", fmt.Sprintf("%X", sha256.Sum256(txBytes))}
+	if sigTx, ok := req.Tx.(authsigning.SigVerifiableTx); ok {
+		if signers := sigTx.GetSigners(); len(signers) > 0 {
+			keyvals = append(keyvals, "signer", signers[0].String())
+		}
+	}
+
+	return sdk.WrapSDKContext(sdkCtx.WithLogger(txh.logger.With(keyvals...)))
+}
+
+// CheckTx implements tx.Handler.CheckTx.
+func (txh loggerTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(txh.withTxLogger(ctx, req), req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx.
+func (txh loggerTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.DeliverTx(txh.withTxLogger(ctx, req), req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx.
+func (txh loggerTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+// testLogger is a log.Logger recording the key/value pairs of the logged lines.
+type testLogger struct {
+	log.Logger
+
+	fields []interface{}
+	lines  *[][]interface{}
+}
+
+func (l testLogger) Info(_ string, keyVals ...interface{}) {
+	*l.lines = append(*l.lines, append(append([]interface{}{}, l.fields...), keyVals...))
+}
+
+func (l testLogger) With(keyVals ...interface{}) log.Logger {
+	l.fields = append(append([]interface{}{}, l.fields...), keyVals...)
+	return l
+}
+
+func (s *MWTestSuite) TestLoggerMiddleware() {
+	testTx, txBytes, ctx, _ := s.setupGasTx()
+	var lines [][]interface{}
+	ctx = ctx.WithLogger(testLogger{Logger: log.NewNopLogger(), lines: &lines})
+
+	loggingTxHandler := customTxHandler{func(ctx context.Context, _ tx.Request) (tx.Response, error) {
+		sdk.UnwrapSDKContext(ctx).Logger().Info("processing tx")
+		return tx.Response{}, nil
+	}}
+	txHandler := middleware.ComposeMiddlewares(loggingTxHandler, middleware.LoggerMiddleware(ctx.Logger()))
+	req := tx.Request{Tx: testTx, TxBytes: txBytes}
+
+	expFields := []interface{}{
+		"tx_hash", fmt.Sprintf("%X", sha256.Sum256(txBytes)),
+		"signer", testTx.GetSigners()[0].String(),
+	}
+
+	_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+	s.Require().NoError(err)
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+	// the logger isn't changed on SimulateTx
+	_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+
+	s.Require().Equal([][]interface{}{expFields, expFields, {}}, lines)
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type maxGasWantedTxHandler struct {
+	ceiling uint64
+	next    tx.Handler
+}
+
+// MaxGasWantedMiddleware defines a middleware that rejects in CheckTx and
+// DeliverTx the txs whose gas limit is greater than ceiling, with an
+// ErrInvalidGasLimit error reporting the requested and maximum gas. It bounds
+// the gas a single tx can reserve, regardless of the gas it actually
+// consumes. A ceiling of 0 disables the check. SimulateTx is passed through,
+// since simulations usually request a large gas limit to estimate the gas
+// used.
+// CONTRACT: Tx must implement FeeTx to use MaxGasWantedMiddleware
+func MaxGasWantedMiddleware(ceiling uint64) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return maxGasWantedTxHandler{
+			ceiling: ceiling,
+			next:    txh,
+		}
+	}
+}
+
+var _ tx.Handler = maxGasWantedTxHandler{}
+
+func (txh maxGasWantedTxHandler) checkGasWanted(sdkTx sdk.Tx) error {
+	if txh.ceiling == 0 {
+		return nil
+	}
+
+	feeTx, ok := sdkTx.(sdk.FeeTx)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be a FeeTx")
+	}
+
+	if gas := feeTx.GetGas(); gas > txh.ceiling {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidGasLimit, "tx requests %d gas, max is %d", gas, txh.ceiling)
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh maxGasWantedTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkGasWanted(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh maxGasWantedTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkGasWanted(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh maxGasWantedTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
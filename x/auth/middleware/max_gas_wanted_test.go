@@ -0,0 +1,55 @@
+package middleware_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestMaxGasWantedMiddleware() {
+	ctx := s.SetupTest(true)
+
+	testCases := []struct {
+		name     string
+		gasLimit uint64
+		ceiling  uint64
+		expErr   string
+	}{
+		{"below the ceiling", 100000, 200000, ""},
+		{"at the ceiling", 200000, 200000, ""},
+		{"above the ceiling", 200001, 200000, "tx requests 200001 gas, max is 200000"},
+		{"no ceiling", 1000000000, 0, ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			txBuilder.SetGasLimit(tc.gasLimit)
+			req := tx.Request{Tx: txBuilder.GetTx()}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.MaxGasWantedMiddleware(tc.ceiling))
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expErr != "" {
+					s.Require().ErrorIs(err, sdkerrors.ErrInvalidGasLimit)
+					s.Require().Contains(err.Error(), tc.expErr)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+
+			// SimulateTx is passed through
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+
+	// txs which aren't FeeTxs are rejected
+	_, _, err := middleware.ComposeMiddlewares(noopTxHandler, middleware.MaxGasWantedMiddleware(1)).
+		CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: txTest{}}, tx.RequestCheckTx{})
+	s.Require().ErrorIs(err, sdkerrors.ErrTxDecode)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+type maxSignersTxHandler struct {
+	maxSigners int
+	next       tx.Handler
+}
+
+// MaxSignersMiddleware defines a middleware that rejects in CheckTx and
+// DeliverTx the txs with more than maxSigners distinct signers, with an
+// ErrTooManySignatures error reporting the number of distinct signers and the
+// limit. It bounds the number of accounts a tx involves, unlike
+// ValidateSigCountMiddleware which bounds the number of signatures, including
+// the sub-keys of multisigs. A maxSigners of 0 disables the check. SimulateTx is
+// passed through.
+// CONTRACT: Tx must implement SigVerifiableTx interface
+func MaxSignersMiddleware(maxSigners int) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return maxSignersTxHandler{
+			maxSigners: maxSigners,
+			next:       txh,
+		}
+	}
+}
+
+var _ tx.Handler = maxSignersTxHandler{}
+
+func (txh maxSignersTxHandler) checkSigners(sdkTx sdk.Tx) error {
+	if txh.maxSigners == 0 {
+		return nil
+	}
+
+	sigTx, ok := sdkTx.(authsigning.SigVerifiableTx)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "invalid transaction type")
+	}
+
+	signers := map[string]bool{}
+	for _, signer := range sigTx.GetSigners() {
+		signers[signer.String()] = true
+	}
+
+	if len(signers) > txh.maxSigners {
+		return sdkerrors.Wrapf(sdkerrors.ErrTooManySignatures, "tx has %d distinct signers, max is %d", len(signers), txh.maxSigners)
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh maxSignersTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkSigners(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh maxSignersTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkSigners(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh maxSignersTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
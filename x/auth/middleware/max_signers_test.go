@@ -0,0 +1,55 @@
+package middleware_test
+
+import (
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestMaxSignersMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+	_, _, addr2 := testdata.KeyTestPubAddr()
+	_, _, addr3 := testdata.KeyTestPubAddr()
+
+	testCases := []struct {
+		name   string
+		msgs   []sdk.Msg
+		max    int
+		expErr string
+	}{
+		{"below the limit", []sdk.Msg{testdata.NewTestMsg(addr1)}, 2, ""},
+		{"at the limit", []sdk.Msg{testdata.NewTestMsg(addr1, addr2)}, 2, ""},
+		{"same signer in several msgs", []sdk.Msg{testdata.NewTestMsg(addr1, addr2), testdata.NewTestMsg(addr2, addr1)}, 2, ""},
+		{"above the limit", []sdk.Msg{testdata.NewTestMsg(addr1, addr2), testdata.NewTestMsg(addr3)}, 2, "tx has 3 distinct signers, max is 2"},
+		{"no limit", []sdk.Msg{testdata.NewTestMsg(addr1, addr2, addr3)}, 0, ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(tc.msgs...))
+			req := tx.Request{Tx: txBuilder.GetTx()}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.MaxSignersMiddleware(tc.max))
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expErr != "" {
+					s.Require().ErrorIs(err, sdkerrors.ErrTooManySignatures)
+					s.Require().Contains(err.Error(), tc.expErr)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+
+			// SimulateTx is passed through
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+}
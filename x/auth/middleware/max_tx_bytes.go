@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type maxTxBytesTxHandler struct {
+	maxBytes int
+	next     tx.Handler
+}
+
+// MaxTxBytesMiddleware defines a middleware that rejects txs whose encoded size
+// exceeds maxBytes. It doesn't need the decoded tx, so it can be placed outside
+// of the TxDecoderMiddleware to reject oversized txs before decoding them. A
+// maxBytes of 0 disables the check.
+func MaxTxBytesMiddleware(maxBytes int) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return maxTxBytesTxHandler{
+			maxBytes: maxBytes,
+			next:     txh,
+		}
+	}
+}
+
+var _ tx.Handler = maxTxBytesTxHandler{}
+
+func (txh maxTxBytesTxHandler) checkTxBytes(ctx context.Context, req tx.Request) error {
+	if txh.maxBytes <= 0 {
+		return nil
+	}
+
+	txBytes := req.TxBytes
+	if len(txBytes) == 0 {
+		txBytes = sdk.UnwrapSDKContext(ctx).TxBytes()
+	}
+
+	if len(txBytes) > txh.maxBytes {
+		return sdkerrors.Wrapf(sdkerrors.ErrTxTooLarge, "tx size is %d bytes, maximum is %d", len(txBytes), txh.maxBytes)
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx.
+func (txh maxTxBytesTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkTxBytes(ctx, req); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx.
+func (txh maxTxBytesTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkTxBytes(ctx, req); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx.
+func (txh maxTxBytesTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkTxBytes(ctx, req); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.SimulateTx(ctx, req)
+}
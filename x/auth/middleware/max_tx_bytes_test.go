@@ -0,0 +1,50 @@
+package middleware_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestMaxTxBytes() {
+	testTx, txBytes, ctx, _ := s.setupGasTx()
+
+	testCases := []struct {
+		name      string
+		maxBytes  int
+		ctxBytes  bool
+		expectErr bool
+	}{
+		{"no limit", 0, false, false},
+		{"limit above tx size", len(txBytes) + 1, false, false},
+		{"limit equal to tx size", len(txBytes), false, false},
+		{"limit below tx size", len(txBytes) - 1, false, true},
+		{"limit below tx size, bytes from context", len(txBytes) - 1, true, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.MaxTxBytesMiddleware(tc.maxBytes))
+
+			req := tx.Request{Tx: testTx, TxBytes: txBytes}
+			ctx := ctx
+			if tc.ctxBytes {
+				req = tx.Request{Tx: testTx}
+				ctx = ctx.WithTxBytes(txBytes)
+			}
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expectErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrTxTooLarge)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type consumeMemoGasTxHandler struct {
+	ak      AccountKeeper
+	perByte sdk.Gas
+	next    tx.Handler
+}
+
+// ConsumeMemoGasMiddleware defines a middleware that consumes perByte gas for
+// each byte of the tx memo, on top of the gas consumed for the whole tx by
+// ConsumeTxSizeGasMiddleware. If perByte is 0, the TxSizeCostPerByte param
+// of the auth module is used instead. It runs identically in CheckTx,
+// DeliverTx and SimulateTx, so that simulations estimate the memo gas
+// correctly.
+// CONTRACT: Tx must implement TxWithMemo interface
+func ConsumeMemoGasMiddleware(ak AccountKeeper, perByte sdk.Gas) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return consumeMemoGasTxHandler{
+			ak:      ak,
+			perByte: perByte,
+			next:    txh,
+		}
+	}
+}
+
+var _ tx.Handler = consumeMemoGasTxHandler{}
+
+func (txh consumeMemoGasTxHandler) consumeMemoGas(ctx context.Context, sdkTx sdk.Tx) error {
+	memoTx, ok := sdkTx.(sdk.TxWithMemo)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "invalid transaction type")
+	}
+
+	memoLength := sdk.Gas(len(memoTx.GetMemo()))
+	if memoLength == 0 {
+		return nil
+	}
+
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	perByte := txh.perByte
+	if perByte == 0 {
+		perByte = txh.ak.GetParams(sdkCtx).TxSizeCostPerByte
+	}
+	sdkCtx.GasMeter().ConsumeGas(perByte*memoLength, "memo")
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh consumeMemoGasTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.consumeMemoGas(ctx, req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh consumeMemoGasTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.consumeMemoGas(ctx, req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh consumeMemoGasTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.consumeMemoGas(ctx, req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.SimulateTx(ctx, req)
+}
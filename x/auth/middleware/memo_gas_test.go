@@ -0,0 +1,70 @@
+package middleware_test
+
+import (
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestConsumeMemoGasMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+	params := s.app.AccountKeeper.GetParams(ctx)
+
+	testCases := []struct {
+		name    string
+		memo    string
+		perByte sdk.Gas
+		expGas  sdk.Gas
+	}{
+		{"empty memo", "", 50, 0},
+		{"custom cost", strings.Repeat("a", 100), 50, 5000},
+		{"default cost", strings.Repeat("a", 100), 0, 100 * params.TxSizeCostPerByte},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(addr1)))
+			txBuilder.SetMemo(tc.memo)
+			req := tx.Request{Tx: txBuilder.GetTx()}
+
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ConsumeMemoGasMiddleware(s.app.AccountKeeper, tc.perByte))
+
+			// reading the params consumes gas as well
+			var paramsGas sdk.Gas
+			if tc.perByte == 0 {
+				before := ctx.GasMeter().GasConsumed()
+				s.app.AccountKeeper.GetParams(ctx)
+				paramsGas = ctx.GasMeter().GasConsumed() - before
+			}
+
+			// gas must be identical in all modes
+			before := ctx.GasMeter().GasConsumed()
+			_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			s.Require().NoError(err)
+			s.Require().Equal(tc.expGas+paramsGas, ctx.GasMeter().GasConsumed()-before)
+
+			before = ctx.GasMeter().GasConsumed()
+			_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.expGas+paramsGas, ctx.GasMeter().GasConsumed()-before)
+
+			before = ctx.GasMeter().GasConsumed()
+			_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.expGas+paramsGas, ctx.GasMeter().GasConsumed()-before)
+		})
+	}
+
+	// txs which don't implement TxWithMemo are rejected
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ConsumeMemoGasMiddleware(s.app.AccountKeeper, 50))
+	_, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: txTest{}})
+	s.Require().ErrorIs(err, sdkerrors.ErrTxDecode)
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/types/tx"
@@ -9,6 +11,20 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth/types"
 )
 
+// MiddlewareName is implemented by tx.Handlers which report a name, used by
+// DescribeChain to list the middlewares of a tx.Handler.
+type MiddlewareName interface {
+	Name() string
+}
+
+// composedTxHandler is the tx.Handler returned by ComposeMiddlewares. It keeps
+// track of the names of the composed middlewares, from outer to inner.
+type composedTxHandler struct {
+	tx.Handler
+
+	names []string
+}
+
 // ComposeMiddlewares compose multiple middlewares on top of a tx.Handler. The
 // middleware order in the variadic arguments is from outer to inner.
 //
@@ -22,12 +38,37 @@ import (
 // A.post
 // ```
 // is created by calling `ComposeMiddlewares(H, A, B)`.
+//
+// The middlewares of the returned tx.Handler can be listed with DescribeChain.
 func ComposeMiddlewares(txHandler tx.Handler, middlewares ...tx.Middleware) tx.Handler {
+	names := DescribeChain(txHandler)
 	for i := len(middlewares) - 1; i >= 0; i-- {
 		txHandler = middlewares[i](txHandler)
+		names = append([]string{handlerName(txHandler)}, names...)
+	}
+
+	return composedTxHandler{Handler: txHandler, names: names}
+}
+
+// DescribeChain lists the names of the middlewares of a tx.Handler created
+// with ComposeMiddlewares, from outer to inner, ending with the base tx.Handler.
+// A middleware's name is the one reported by its MiddlewareName
+// implementation, or its Go type otherwise. For any other tx.Handler, only the
+// name of the handler itself is returned.
+func DescribeChain(txHandler tx.Handler) []string {
+	if composed, ok := txHandler.(composedTxHandler); ok {
+		return append([]string(nil), composed.names...)
+	}
+
+	return []string{handlerName(txHandler)}
+}
+
+func handlerName(txHandler tx.Handler) string {
+	if named, ok := txHandler.(MiddlewareName); ok {
+		return named.Name()
 	}
 
-	return txHandler
+	return fmt.Sprintf("%T", txHandler)
 }
 
 type TxHandlerOptions struct {
@@ -48,6 +89,10 @@ type TxHandlerOptions struct {
 	FeegrantKeeper  FeegrantKeeper
 	SignModeHandler authsigning.SignModeHandler
 	SigGasConsumer  func(meter sdk.GasMeter, sig signing.SignatureV2, params types.Params) error
+
+	// SignerTracker, if set, records the signers of the delivered txs, see
+	// SignerTrackingMiddleware.
+	SignerTracker *SignerTracker
 }
 
 // NewDefaultTxHandler defines a TxHandler middleware stacks that should work
@@ -77,6 +122,10 @@ func NewDefaultTxHandler(options TxHandlerOptions) (tx.Handler, error) {
 	return ComposeMiddlewares(
 		NewRunMsgsTxHandler(options.MsgServiceRouter, options.LegacyRouter),
 		NewTxDecoderMiddleware(options.TxDecoder),
+		// Record the signers of delivered txs, including the ones which fail.
+		SignerTrackingMiddleware(options.SignerTracker),
+		// Flag simulations in sdk.Context, see IsSimulateTx.
+		SimulationFlagMiddleware,
 		// Set a new GasMeter on sdk.Context.
 		//
 		// Make sure the Gas middleware is outside of all other middlewares
@@ -108,6 +157,8 @@ func NewDefaultTxHandler(options TxHandlerOptions) (tx.Handler, error) {
 		SigGasConsumeMiddleware(options.AccountKeeper, sigGasConsumer),
 		SigVerificationMiddleware(options.AccountKeeper, options.SignModeHandler),
 		IncrementSequenceMiddleware(options.AccountKeeper),
+		// Skip the execution of the messages in validate-only simulations.
+		ValidateOnlySimulateMiddleware,
 		// Creates a new MultiStore branch, discards downstream writes if the downstream returns error.
 		// These kinds of middlewares should be put under this:
 		// - Could return error after messages executed succesfully.
@@ -1153,3 +1153,39 @@ func (s *MWTestSuite) TestTxHandlerReCheck() {
 	_, _, err = s.txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{})
 	s.Require().NotNil(err, "txhandler on recheck did not fail once feePayer no longer has sufficient funds")
 }
+
+// namedTxHandler is a test middleware reporting its name.
+type namedTxHandler struct {
+	tx.Handler
+}
+
+func (namedTxHandler) Name() string { return "named" }
+
+func TestDescribeChain(t *testing.T) {
+	namedMiddleware := func(txh tx.Handler) tx.Handler { return namedTxHandler{txh} }
+
+	txHandler := middleware.ComposeMiddlewares(
+		noopTxHandler,
+		middleware.GasTxMiddleware,
+		namedMiddleware,
+		middleware.ValidateMemoMiddleware(nil),
+	)
+	require.Equal(t, []string{
+		"middleware.gasTxHandler",
+		"named",
+		"middleware.validateMemoTxHandler",
+		"middleware_test.customTxHandler",
+	}, middleware.DescribeChain(txHandler))
+
+	// composing on top of a composed tx.Handler describes the whole chain
+	txHandler = middleware.ComposeMiddlewares(txHandler, middleware.RecoveryTxMiddleware)
+	require.Equal(t, []string{
+		"middleware.recoveryTxHandler",
+		"middleware.gasTxHandler",
+		"named",
+		"middleware.validateMemoTxHandler",
+		"middleware_test.customTxHandler",
+	}, middleware.DescribeChain(txHandler))
+
+	require.Equal(t, []string{"named"}, middleware.DescribeChain(namedTxHandler{noopTxHandler}))
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type minGasPriceTxHandler struct {
+	minPrices sdk.DecCoins
+	next      tx.Handler
+}
+
+// MinGasPriceMiddleware defines a middleware that rejects, in CheckTx only,
+// the txs whose gas price, computed as fee / gas limit for each fee denom, is
+// below the minimum price for that denom in minPrices. Txs paying fees in
+// several denoms are accepted if at least one of them meets its minimum price.
+// DeliverTx doesn't enforce it, since the block proposer already accepted the
+// tx. An empty minPrices disables the check.
+// CONTRACT: Tx must implement FeeTx to use MinGasPriceMiddleware
+func MinGasPriceMiddleware(minPrices sdk.DecCoins) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return minGasPriceTxHandler{
+			minPrices: minPrices,
+			next:      txh,
+		}
+	}
+}
+
+var _ tx.Handler = minGasPriceTxHandler{}
+
+func (txh minGasPriceTxHandler) checkGasPrice(sdkTx sdk.Tx) error {
+	if txh.minPrices.IsZero() {
+		return nil
+	}
+
+	feeTx, ok := sdkTx.(sdk.FeeTx)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be a FeeTx")
+	}
+
+	fee := feeTx.GetFee()
+	gas := feeTx.GetGas()
+	if gas == 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrInsufficientFee, "can't compute the gas price of a tx with no gas limit")
+	}
+
+	// fee >= minPrice * gas is checked instead of fee / gas >= minPrice to
+	// avoid rounding errors
+	gasLimit := sdk.NewIntFromUint64(gas)
+	for _, coin := range fee {
+		minPrice := txh.minPrices.AmountOf(coin.Denom)
+		if minPrice.IsPositive() && coin.Amount.ToDec().GTE(minPrice.MulInt(gasLimit)) {
+			return nil
+		}
+	}
+
+	providedPrices := sdk.NewDecCoinsFromCoins(fee...).QuoDec(gasLimit.ToDec())
+	return sdkerrors.Wrapf(sdkerrors.ErrInsufficientFee, "insufficient gas price; got: %s required: %s", providedPrices, txh.minPrices)
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh minGasPriceTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkGasPrice(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh minGasPriceTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh minGasPriceTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,75 @@
+package middleware_test
+
+import (
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestMinGasPriceMiddleware() {
+	ctx := s.SetupTest(true)
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+	minPrices := sdk.NewDecCoins(sdk.NewDecCoinFromDec("atom", sdk.MustNewDecFromStr("0.5")), sdk.NewDecCoinFromDec("stake", sdk.NewDec(2)))
+
+	testCases := []struct {
+		name      string
+		minPrices sdk.DecCoins
+		fee       sdk.Coins
+		gas       uint64
+		expErr    bool
+	}{
+		{"no minimum prices", nil, nil, 100, false},
+		{"price equal to minimum", minPrices, sdk.NewCoins(sdk.NewInt64Coin("atom", 50)), 100, false},
+		{"price above minimum", minPrices, sdk.NewCoins(sdk.NewInt64Coin("stake", 201)), 100, false},
+		{"price below minimum", minPrices, sdk.NewCoins(sdk.NewInt64Coin("atom", 49)), 100, true},
+		{"no fee", minPrices, nil, 100, true},
+		{"denom without minimum price", minPrices, sdk.NewCoins(sdk.NewInt64Coin("foo", 1000)), 100, true},
+		{"one of several denoms meets its minimum", minPrices, sdk.NewCoins(sdk.NewInt64Coin("atom", 1), sdk.NewInt64Coin("stake", 200)), 100, false},
+		{"none of several denoms meets its minimum", minPrices, sdk.NewCoins(sdk.NewInt64Coin("atom", 49), sdk.NewInt64Coin("stake", 199)), 100, true},
+		{"no gas limit", minPrices, sdk.NewCoins(sdk.NewInt64Coin("atom", 50)), 0, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(addr1)))
+			txBuilder.SetFeeAmount(tc.fee)
+			txBuilder.SetGasLimit(tc.gas)
+			privs, accNums, accSeqs := []cryptotypes.PrivKey{priv1}, []uint64{0}, []uint64{0}
+			testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+			s.Require().NoError(err)
+
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.MinGasPriceMiddleware(tc.minPrices))
+			req := tx.Request{Tx: testTx}
+			_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			if tc.expErr {
+				s.Require().ErrorIs(err, sdkerrors.ErrInsufficientFee)
+			} else {
+				s.Require().NoError(err)
+			}
+
+			// DeliverTx and SimulateTx don't enforce minimum gas prices
+			_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+			_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+
+	// the error includes the provided and required prices
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+	s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(addr1)))
+	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("atom", 25)))
+	txBuilder.SetGasLimit(100)
+	testTx, _, err := s.createTestTx(txBuilder, []cryptotypes.PrivKey{priv1}, []uint64{0}, []uint64{0}, ctx.ChainID())
+	s.Require().NoError(err)
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.MinGasPriceMiddleware(minPrices))
+	_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{})
+	s.Require().ErrorIs(err, sdkerrors.ErrInsufficientFee)
+	s.Require().Contains(err.Error(), "got: 0.250000000000000000atom required: 0.500000000000000000atom,2.000000000000000000stake")
+}
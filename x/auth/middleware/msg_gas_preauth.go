@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type msgGasPreauthTxHandler struct {
+	minGas func(msg sdk.Msg) uint64
+	next   tx.Handler
+}
+
+// MsgGasPreauthMiddleware defines a middleware that rejects in CheckTx and
+// DeliverTx the txs whose gas limit is below the sum of the minimum gas of
+// their messages, as returned by minGas, with an ErrInvalidGasLimit error
+// reporting the requested and required gas. minGas should return 0 for the
+// message types it doesn't recognize, so that only known expensive messages
+// require the tx to reserve gas beforehand, failing early rather than running
+// out of gas in the middle of their execution. SimulateTx is passed through,
+// since simulations are used to estimate the gas limit.
+// CONTRACT: Tx must implement FeeTx to use MsgGasPreauthMiddleware
+func MsgGasPreauthMiddleware(minGas func(msg sdk.Msg) uint64) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return msgGasPreauthTxHandler{
+			minGas: minGas,
+			next:   txh,
+		}
+	}
+}
+
+var _ tx.Handler = msgGasPreauthTxHandler{}
+
+func (txh msgGasPreauthTxHandler) checkGasPreauth(sdkTx sdk.Tx) error {
+	feeTx, ok := sdkTx.(sdk.FeeTx)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be a FeeTx")
+	}
+
+	var required uint64
+	for _, msg := range sdkTx.GetMsgs() {
+		minGas := txh.minGas(msg)
+		if required > math.MaxUint64-minGas {
+			return sdkerrors.Wrap(sdkerrors.ErrInvalidGasLimit, "minimum gas of messages overflows")
+		}
+		required += minGas
+	}
+
+	if gas := feeTx.GetGas(); gas < required {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidGasLimit, "tx requests %d gas, its messages require at least %d", gas, required)
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh msgGasPreauthTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkGasPreauth(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh msgGasPreauthTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkGasPreauth(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh msgGasPreauthTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
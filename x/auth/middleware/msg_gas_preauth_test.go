@@ -0,0 +1,74 @@
+package middleware_test
+
+import (
+	"math"
+
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestMsgGasPreauthMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+	testMsg, dogMsg := testdata.NewTestMsg(addr1), &testdata.MsgCreateDog{}
+
+	// only MsgCreateDog is recognized
+	minGas := func(msg sdk.Msg) uint64 {
+		if _, ok := msg.(*testdata.MsgCreateDog); ok {
+			return 50000
+		}
+		return 0
+	}
+
+	testCases := []struct {
+		name     string
+		msgs     []sdk.Msg
+		gasLimit uint64
+		minGas   func(msg sdk.Msg) uint64
+		expErr   string
+	}{
+		{"no recognized msgs", []sdk.Msg{testMsg}, 0, minGas, ""},
+		{"enough gas", []sdk.Msg{dogMsg, testMsg}, 60000, minGas, ""},
+		{"exactly enough gas", []sdk.Msg{dogMsg, dogMsg}, 100000, minGas, ""},
+		{"not enough gas", []sdk.Msg{dogMsg, testMsg, dogMsg}, 99999, minGas, "tx requests 99999 gas, its messages require at least 100000"},
+		{
+			"overflowing minimums", []sdk.Msg{dogMsg, dogMsg}, math.MaxUint64,
+			func(sdk.Msg) uint64 { return math.MaxUint64 }, "minimum gas of messages overflows",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(tc.msgs...))
+			txBuilder.SetGasLimit(tc.gasLimit)
+			req := tx.Request{Tx: txBuilder.GetTx()}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.MsgGasPreauthMiddleware(tc.minGas))
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expErr != "" {
+					s.Require().ErrorIs(err, sdkerrors.ErrInvalidGasLimit)
+					s.Require().Contains(err.Error(), tc.expErr)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+
+			// SimulateTx is passed through
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+
+	// txs which aren't FeeTxs are rejected
+	_, _, err := middleware.ComposeMiddlewares(noopTxHandler, middleware.MsgGasPreauthMiddleware(minGas)).
+		CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: txTest{}}, tx.RequestCheckTx{})
+	s.Require().ErrorIs(err, sdkerrors.ErrTxDecode)
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"context"
+	"math/bits"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// MsgGasStats are the cumulative gas statistics of a message type.
+type MsgGasStats struct {
+	// Count is the number of profiled messages of the type.
+	Count uint64
+	// GasUsed is the gas attributed to the messages of the type.
+	GasUsed uint64
+}
+
+// MsgGasProfiler accumulates the gas used by the txs delivered by
+// MessageGasProfilerMiddleware, per message type URL. Since the messages of a
+// tx are executed together, the gas of a tx is split between its messages in
+// proportion to their weight, see NewMsgGasProfiler, so the attribution is
+// coarse. The application decides when to retrieve the profile with Profile,
+// and when to clear it with Reset.
+type MsgGasProfiler struct {
+	mtx sync.Mutex
+
+	weight func(msg sdk.Msg) uint64
+	stats  map[string]MsgGasStats
+}
+
+// NewMsgGasProfiler returns a new empty MsgGasProfiler splitting the gas used
+// by a tx between its messages in proportion to weight. If weight is nil, or
+// if the weights of all the messages of a tx are 0, the gas is split evenly.
+func NewMsgGasProfiler(weight func(msg sdk.Msg) uint64) *MsgGasProfiler {
+	return &MsgGasProfiler{
+		weight: weight,
+		stats:  make(map[string]MsgGasStats),
+	}
+}
+
+// add attributes gasUsed to msgs.
+func (p *MsgGasProfiler) add(msgs []sdk.Msg, gasUsed uint64) {
+	if len(msgs) == 0 {
+		return
+	}
+
+	weights := make([]uint64, len(msgs))
+	var totalWeight uint64
+	if p.weight != nil {
+		for i, msg := range msgs {
+			weights[i] = p.weight(msg)
+			// saturate rather than overflow, weights are only indicative
+			if totalWeight+weights[i] < totalWeight {
+				weights[i] = ^uint64(0) - totalWeight
+			}
+			totalWeight += weights[i]
+		}
+	}
+	if totalWeight == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		totalWeight = uint64(len(msgs))
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	remaining := gasUsed
+	for i, msg := range msgs {
+		// the last message gets the rounding remainder, so that the whole gas
+		// of the tx is attributed
+		gas := remaining
+		if i != len(msgs)-1 {
+			hi, lo := bits.Mul64(gasUsed, weights[i])
+			gas, _ = bits.Div64(hi, lo, totalWeight)
+		}
+		remaining -= gas
+
+		typeURL := sdk.MsgTypeURL(msg)
+		stats := p.stats[typeURL]
+		stats.Count++
+		stats.GasUsed += gas
+		p.stats[typeURL] = stats
+	}
+}
+
+// Profile returns a copy of the stats accumulated since the profiler was
+// created or last reset, keyed by message type URL.
+func (p *MsgGasProfiler) Profile() map[string]MsgGasStats {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	profile := make(map[string]MsgGasStats, len(p.stats))
+	for typeURL, stats := range p.stats {
+		profile[typeURL] = stats
+	}
+
+	return profile
+}
+
+// Reset clears the accumulated stats.
+func (p *MsgGasProfiler) Reset() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.stats = make(map[string]MsgGasStats)
+}
+
+type msgGasProfilerTxHandler struct {
+	profiler *MsgGasProfiler
+	next     tx.Handler
+}
+
+// MessageGasProfilerMiddleware defines a middleware that records in profiler
+// the gas consumed by the inner handlers in DeliverTx, attributed to the
+// message types of the tx, whether the tx succeeds or not. It is a diagnostic
+// tool: responses and errors are returned unchanged, and CheckTx and
+// SimulateTx are not profiled. Txs running out of gas aren't profiled either,
+// since the out of gas panic unwinds through this middleware.
+// CONTRACT: GasTxMiddleware must be placed before this middleware, so that the
+// gas meter of the context is the one of the tx.
+func MessageGasProfilerMiddleware(profiler *MsgGasProfiler) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return msgGasProfilerTxHandler{
+			profiler: profiler,
+			next:     txh,
+		}
+	}
+}
+
+var _ tx.Handler = msgGasProfilerTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh msgGasProfilerTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh msgGasProfilerTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	gasMeter := sdk.UnwrapSDKContext(ctx).GasMeter()
+	gasBefore := gasMeter.GasConsumed()
+
+	res, err := txh.next.DeliverTx(ctx, req)
+	txh.profiler.add(req.Tx.GetMsgs(), gasMeter.GasConsumed()-gasBefore)
+
+	return res, err
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh msgGasProfilerTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,84 @@
+package middleware_test
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestMessageGasProfilerMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+	testMsg, dogMsg := testdata.NewTestMsg(addr1), &testdata.MsgCreateDog{}
+	testMsgURL, dogMsgURL := sdk.MsgTypeURL(testMsg), sdk.MsgTypeURL(dogMsg)
+
+	var txErr error
+	consumeGasTxHandler := customTxHandler{func(ctx context.Context, _ tx.Request) (tx.Response, error) {
+		sdk.UnwrapSDKContext(ctx).GasMeter().ConsumeGas(1000, "execution")
+		return tx.Response{GasUsed: 42}, txErr
+	}}
+
+	testCases := []struct {
+		name     string
+		weight   func(msg sdk.Msg) uint64
+		expStats map[string]middleware.MsgGasStats
+	}{
+		{
+			"even split",
+			nil,
+			map[string]middleware.MsgGasStats{testMsgURL: {Count: 2, GasUsed: 667}, dogMsgURL: {Count: 1, GasUsed: 333}},
+		},
+		{
+			"weighted split",
+			func(msg sdk.Msg) uint64 {
+				if _, ok := msg.(*testdata.MsgCreateDog); ok {
+					return 3
+				}
+				return 1
+			},
+			map[string]middleware.MsgGasStats{testMsgURL: {Count: 2, GasUsed: 400}, dogMsgURL: {Count: 1, GasUsed: 600}},
+		},
+		{
+			"zero weights",
+			func(sdk.Msg) uint64 { return 0 },
+			map[string]middleware.MsgGasStats{testMsgURL: {Count: 2, GasUsed: 667}, dogMsgURL: {Count: 1, GasUsed: 333}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			profiler := middleware.NewMsgGasProfiler(tc.weight)
+			txHandler := middleware.ComposeMiddlewares(consumeGasTxHandler, middleware.MessageGasProfilerMiddleware(profiler))
+			req := tx.Request{Tx: msgsTx{testMsg, dogMsg, testMsg}}
+
+			txErr = nil
+			res, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx.WithGasMeter(sdk.NewInfiniteGasMeter())), req)
+			s.Require().NoError(err)
+			s.Require().Equal(uint64(42), res.GasUsed)
+			s.Require().Equal(tc.expStats, profiler.Profile())
+
+			// failed txs are profiled too, and their error is returned unchanged
+			txErr = sdkerrors.ErrInvalidRequest
+			_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx.WithGasMeter(sdk.NewInfiniteGasMeter())), tx.Request{Tx: msgsTx{dogMsg}})
+			s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+			dogStats := tc.expStats[dogMsgURL]
+			s.Require().Equal(middleware.MsgGasStats{Count: dogStats.Count + 1, GasUsed: dogStats.GasUsed + 1000}, profiler.Profile()[dogMsgURL])
+
+			// CheckTx and SimulateTx aren't profiled
+			profiler.Reset()
+			s.Require().Empty(profiler.Profile())
+			txErr = nil
+			_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx.WithGasMeter(sdk.NewInfiniteGasMeter())), req, tx.RequestCheckTx{})
+			s.Require().NoError(err)
+			_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx.WithGasMeter(sdk.NewInfiniteGasMeter())), req)
+			s.Require().NoError(err)
+			s.Require().Empty(profiler.Profile())
+		})
+	}
+}
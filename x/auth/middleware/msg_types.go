@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type blockMsgTypesTxHandler struct {
+	// blocked is the set of msg type URLs which are rejected.
+	blocked map[string]bool
+	next    tx.Handler
+}
+
+// BlockMsgTypesMiddleware defines a middleware that rejects txs containing at
+// least one message whose type URL is in the blocked set. It runs on CheckTx as
+// well as DeliverTx, so that txs which didn't go through this node's mempool
+// are rejected too.
+func BlockMsgTypesMiddleware(blocked map[string]bool) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return blockMsgTypesTxHandler{
+			blocked: blocked,
+			next:    txh,
+		}
+	}
+}
+
+var _ tx.Handler = blockMsgTypesTxHandler{}
+
+func (txh blockMsgTypesTxHandler) checkMsgTypes(tx sdk.Tx) error {
+	for _, msg := range tx.GetMsgs() {
+		if typeURL := sdk.MsgTypeURL(msg); txh.blocked[typeURL] {
+			return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "message type %s is not allowed", typeURL)
+		}
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx.
+func (txh blockMsgTypesTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkMsgTypes(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx.
+func (txh blockMsgTypesTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkMsgTypes(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx.
+func (txh blockMsgTypesTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkMsgTypes(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestBlockMsgTypes() {
+	ctx := s.SetupTest(true) // setup
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+
+	// keys and addresses
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+
+	// msgs and signatures
+	testMsg := testdata.NewTestMsg(addr1)
+	dogMsg := &testdata.MsgCreateDog{Dog: &testdata.Dog{Name: "Spot"}}
+	s.Require().NoError(txBuilder.SetMsgs(testMsg, dogMsg))
+	txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+	txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+
+	privs, accNums, accSeqs := []cryptotypes.PrivKey{priv1}, []uint64{0}, []uint64{0}
+	testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name      string
+		blocked   map[string]bool
+		expectErr bool
+	}{
+		{"nil blocked set", nil, false},
+		{"unrelated type url", map[string]bool{"/unknown.Msg": true}, false},
+		{"blocked type url set to false", map[string]bool{sdk.MsgTypeURL(dogMsg): false}, false},
+		{"blocked type url", map[string]bool{sdk.MsgTypeURL(dogMsg): true}, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.BlockMsgTypesMiddleware(tc.blocked))
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx})
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expectErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrUnauthorized)
+					s.Require().Contains(err.Error(), sdk.MsgTypeURL(dogMsg))
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+		})
+	}
+}
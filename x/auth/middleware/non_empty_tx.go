@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type nonEmptyTxHandler struct {
+	next tx.Handler
+}
+
+// NonEmptyTxMiddleware defines a middleware that rejects in CheckTx and
+// DeliverTx the txs without any message with an ErrNoMessages error. It is
+// separate from ValidateBasicMiddleware so that applications which accept
+// empty txs can leave it out. SimulateTx is passed through.
+func NonEmptyTxMiddleware(txh tx.Handler) tx.Handler {
+	return nonEmptyTxHandler{
+		next: txh,
+	}
+}
+
+var _ tx.Handler = nonEmptyTxHandler{}
+
+func checkNonEmptyTx(sdkTx sdk.Tx) error {
+	if len(sdkTx.GetMsgs()) == 0 {
+		return sdkerrors.ErrNoMessages
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh nonEmptyTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := checkNonEmptyTx(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh nonEmptyTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := checkNonEmptyTx(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh nonEmptyTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestNonEmptyTxMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+
+	testCases := []struct {
+		name   string
+		tx     sdk.Tx
+		expErr bool
+	}{
+		{"one message", msgsTx{testdata.NewTestMsg(addr1)}, false},
+		{"several messages", msgsTx{testdata.NewTestMsg(addr1), &testdata.MsgCreateDog{}}, false},
+		{"no messages", txTest{}, true},
+		{"empty messages", msgsTx{}, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			req := tx.Request{Tx: tc.tx}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.NonEmptyTxMiddleware)
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrNoMessages)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+
+			// SimulateTx is passed through
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+}
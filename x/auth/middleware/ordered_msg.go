@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type orderedMsgTxHandler struct {
+	rule func(msgs []sdk.Msg) error
+	next tx.Handler
+}
+
+// OrderedMsgMiddleware defines a middleware that rejects in CheckTx and
+// DeliverTx the txs whose messages, passed to rule in their tx order, don't
+// satisfy rule, e.g. to require that a setup message precedes an action
+// message. It is meant for constraints between the messages of a tx, which
+// can't be checked by the ValidateBasic of a single message. The error
+// returned by rule is returned unchanged, so it should be a registered error
+// to get a meaningful ABCI code. SimulateTx is passed through.
+func OrderedMsgMiddleware(rule func(msgs []sdk.Msg) error) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return orderedMsgTxHandler{
+			rule: rule,
+			next: txh,
+		}
+	}
+}
+
+var _ tx.Handler = orderedMsgTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh orderedMsgTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.rule(req.Tx.GetMsgs()); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh orderedMsgTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.rule(req.Tx.GetMsgs()); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh orderedMsgTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
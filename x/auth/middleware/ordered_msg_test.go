@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestOrderedMsgMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+	setupMsg, actionMsg := &testdata.MsgCreateDog{}, testdata.NewTestMsg(addr1)
+
+	// MsgCreateDog must precede any TestMsg
+	rule := func(msgs []sdk.Msg) error {
+		setup := false
+		for i, msg := range msgs {
+			switch msg.(type) {
+			case *testdata.MsgCreateDog:
+				setup = true
+			case *testdata.TestMsg:
+				if !setup {
+					return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "message %d must follow a setup message", i)
+				}
+			}
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		name   string
+		tx     sdk.Tx
+		expErr bool
+	}{
+		{"setup then action", msgsTx{setupMsg, actionMsg}, false},
+		{"action then setup", msgsTx{actionMsg, setupMsg}, true},
+		{"action only", msgsTx{actionMsg}, true},
+		{"setup only", msgsTx{setupMsg}, false},
+		{"no messages", txTest{}, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			req := tx.Request{Tx: tc.tx}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.OrderedMsgMiddleware(rule))
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+					s.Require().Contains(err.Error(), "message 0 must follow a setup message")
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+
+			// SimulateTx is passed through
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"math"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -61,3 +62,66 @@ func GetTxPriority(fee sdk.Coins) int64 {
 
 	return priority
 }
+
+var _ tx.Handler = txGasPricePriorityHandler{}
+
+type txGasPricePriorityHandler struct {
+	next tx.Handler
+}
+
+// TxGasPricePriorityMiddleware implements tx handling middleware that
+// determines a transaction's priority from its gas price, i.e. the fee divided
+// by the gas limit. It sets the Priority in ResponseCheckTx only.
+func TxGasPricePriorityMiddleware(txh tx.Handler) tx.Handler {
+	return txGasPricePriorityHandler{next: txh}
+}
+
+// CheckTx implements tx.Handler.CheckTx. We set the Priority of the transaction
+// to be ordered in the Tendermint mempool based on its gas price.
+func (h txGasPricePriorityHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	feeTx, ok := req.Tx.(sdk.FeeTx)
+	if !ok {
+		return tx.Response{}, tx.ResponseCheckTx{}, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be a FeeTx")
+	}
+
+	feeCoins := feeTx.GetFee()
+	gas := feeTx.GetGas()
+
+	res, checkRes, err := h.next.CheckTx(ctx, req, checkReq)
+	checkRes.Priority = GetTxGasPricePriority(feeCoins, gas)
+
+	return res, checkRes, err
+}
+
+func (h txGasPricePriorityHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return h.next.DeliverTx(ctx, req)
+}
+
+func (h txGasPricePriorityHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return h.next.SimulateTx(ctx, req)
+}
+
+// GetTxGasPricePriority returns a tx priority based on the gas price of the
+// smallest denomination of the fee provided in a transaction, i.e. its amount
+// divided by the gas limit. Txs with a gas limit of 0 get the lowest priority.
+func GetTxGasPricePriority(fee sdk.Coins, gas uint64) int64 {
+	if gas == 0 {
+		return 0
+	}
+
+	var priority int64
+	for i, c := range fee {
+		gasPrice := c.Amount.Quo(sdk.NewIntFromUint64(gas))
+		p := int64(math.MaxInt64)
+		if gasPrice.IsInt64() {
+			p = gasPrice.Int64()
+		}
+
+		// a gas price can be 0, so we can't rely on priority == 0 here.
+		if i == 0 || p < priority {
+			priority = p
+		}
+	}
+
+	return priority
+}
@@ -36,3 +36,48 @@ func (s *MWTestSuite) TestPriority() {
 	s.Require().NoError(err, "Middleware should not have errored on too low fee for local gasPrice")
 	s.Require().Equal(atomCoin.Amount.Int64(), checkTxRes.Priority, "priority should be atom amount")
 }
+
+func (s *MWTestSuite) TestGasPricePriority() {
+	ctx := s.SetupTest(true) // setup
+
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.TxGasPricePriorityMiddleware)
+
+	// keys and addresses
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+
+	// msg and signatures
+	msg := testdata.NewTestMsg(addr1)
+	atomCoin := sdk.NewCoin("atom", sdk.NewInt(150000))
+	apeCoin := sdk.NewInt64Coin("ape", 1500000)
+	feeAmount := sdk.NewCoins(apeCoin, atomCoin)
+
+	testCases := []struct {
+		name        string
+		gasLimit    uint64
+		expPriority int64
+	}{
+		{"zero gas", 0, 0},
+		{"gas price of smallest fee", 1000, 150},
+		{"gas price rounded down", 100000, 1},
+		{"gas price rounded down to zero", 1000000, 0},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(msg))
+			txBuilder.SetFeeAmount(feeAmount)
+			txBuilder.SetGasLimit(tc.gasLimit)
+
+			privs, accNums, accSeqs := []cryptotypes.PrivKey{priv1}, []uint64{0}, []uint64{0}
+			testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+			s.Require().NoError(err)
+
+			_, checkTxRes, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{})
+			s.Require().NoError(err)
+			s.Require().Equal(tc.expPriority, checkTxRes.Priority)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// rateLimiter keeps track, per signer, of the times of the recent accepted
+// CheckTx calls.
+type rateLimiter struct {
+	mtx sync.Mutex
+
+	maxPerWindow int
+	window       time.Duration
+	// calls maps a signer address to the times of its calls within the window,
+	// from oldest to newest.
+	calls     map[string][]time.Time
+	lastSweep time.Time
+}
+
+// allow records a call from the given signer at the given time, and returns
+// false if the signer already reached the limit within the window.
+func (rl *rateLimiter) allow(signer string, now time.Time) bool {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	windowStart := now.Add(-rl.window)
+
+	// Regularly drop the signers without recent calls, so that the map doesn't
+	// grow unbounded.
+	if now.Sub(rl.lastSweep) > rl.window {
+		for s, times := range rl.calls {
+			if len(times) == 0 || !times[len(times)-1].After(windowStart) {
+				delete(rl.calls, s)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	times := rl.calls[signer]
+	i := 0
+	for i < len(times) && !times[i].After(windowStart) {
+		i++
+	}
+	times = times[i:]
+
+	if len(times) >= rl.maxPerWindow {
+		rl.calls[signer] = times
+		return false
+	}
+
+	rl.calls[signer] = append(times, now)
+
+	return true
+}
+
+type rateLimitTxHandler struct {
+	limiter *rateLimiter
+	next    tx.Handler
+}
+
+// RateLimitMiddleware defines a middleware that limits the number of CheckTx
+// calls a signer can make to maxPerWindow in any sliding window of the given
+// duration. Txs are attributed to their first signer. DeliverTx, SimulateTx and
+// ReCheckTx are not rate limited. A maxPerWindow of 0 disables rate limiting.
+//
+// The limiter state is kept in memory, and is local to each node.
+func RateLimitMiddleware(maxPerWindow int, window time.Duration) tx.Middleware {
+	limiter := &rateLimiter{
+		maxPerWindow: maxPerWindow,
+		window:       window,
+		calls:        make(map[string][]time.Time),
+	}
+
+	return func(txh tx.Handler) tx.Handler {
+		return rateLimitTxHandler{
+			limiter: limiter,
+			next:    txh,
+		}
+	}
+}
+
+var _ tx.Handler = rateLimitTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx.
+func (txh rateLimitTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if checkReq.Type == abci.CheckTxType_Recheck || txh.limiter.maxPerWindow <= 0 {
+		return txh.next.CheckTx(ctx, req, checkReq)
+	}
+
+	sigTx, ok := req.Tx.(authsigning.SigVerifiableTx)
+	if !ok {
+		return tx.Response{}, tx.ResponseCheckTx{}, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "invalid tx type")
+	}
+
+	signers := sigTx.GetSigners()
+	if len(signers) == 0 {
+		return txh.next.CheckTx(ctx, req, checkReq)
+	}
+
+	if !txh.limiter.allow(signers[0].String(), time.Now()) {
+		return tx.Response{}, tx.ResponseCheckTx{}, sdkerrors.Wrapf(
+			sdkerrors.ErrTooManyRequests, "signer %s exceeded %d txs per %s", signers[0], txh.limiter.maxPerWindow, txh.limiter.window,
+		)
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx.
+func (txh rateLimitTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx.
+func (txh rateLimitTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+	xauthsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+func (s *MWTestSuite) TestRateLimit() {
+	ctx := s.SetupTest(true) // setup
+
+	// keys and addresses
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+	priv2, _, addr2 := testdata.KeyTestPubAddr()
+
+	createTx := func(priv cryptotypes.PrivKey, addr sdk.AccAddress) xauthsigning.Tx {
+		txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+		s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(addr)))
+		txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+		txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+		testTx, _, err := s.createTestTx(txBuilder, []cryptotypes.PrivKey{priv}, []uint64{0}, []uint64{0}, ctx.ChainID())
+		s.Require().NoError(err)
+
+		return testTx
+	}
+	tx1, tx2 := createTx(priv1, addr1), createTx(priv2, addr2)
+
+	checkTx := func(txHandler tx.Handler, testTx sdk.Tx, checkType abci.CheckTxType) error {
+		_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: testTx}, tx.RequestCheckTx{Type: checkType})
+		return err
+	}
+
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.RateLimitMiddleware(2, time.Hour))
+	s.Require().NoError(checkTx(txHandler, tx1, abci.CheckTxType_New))
+	s.Require().NoError(checkTx(txHandler, tx1, abci.CheckTxType_New))
+	s.Require().ErrorIs(checkTx(txHandler, tx1, abci.CheckTxType_New), sdkerrors.ErrTooManyRequests)
+
+	// other signers, ReCheckTx, DeliverTx and SimulateTx are not limited
+	s.Require().NoError(checkTx(txHandler, tx2, abci.CheckTxType_New))
+	s.Require().NoError(checkTx(txHandler, tx1, abci.CheckTxType_Recheck))
+	_, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: tx1})
+	s.Require().NoError(err)
+	_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), tx.Request{Tx: tx1})
+	s.Require().NoError(err)
+
+	// calls are allowed again once out of the window
+	window := 50 * time.Millisecond
+	txHandler = middleware.ComposeMiddlewares(noopTxHandler, middleware.RateLimitMiddleware(1, window))
+	s.Require().NoError(checkTx(txHandler, tx1, abci.CheckTxType_New))
+	s.Require().ErrorIs(checkTx(txHandler, tx1, abci.CheckTxType_New), sdkerrors.ErrTooManyRequests)
+	time.Sleep(2 * window)
+	s.Require().NoError(checkTx(txHandler, tx1, abci.CheckTxType_New))
+
+	// a limit of 0 disables rate limiting
+	txHandler = middleware.ComposeMiddlewares(noopTxHandler, middleware.RateLimitMiddleware(0, time.Hour))
+	for i := 0; i < 3; i++ {
+		s.Require().NoError(checkTx(txHandler, tx1, abci.CheckTxType_New))
+	}
+}
@@ -9,8 +9,14 @@ import (
 	"github.com/cosmos/cosmos-sdk/types/tx"
 )
 
+// RecoveryHandler converts a value recovered from a panic into an error. It
+// should return nil if it doesn't handle the given value, in which case the
+// default recovery handling applies.
+type RecoveryHandler func(recoveryObj interface{}) error
+
 type recoveryTxHandler struct {
-	next tx.Handler
+	next    tx.Handler
+	handler RecoveryHandler
 }
 
 // RecoveryTxMiddleware defines a middleware that catches all panics that
@@ -21,6 +27,18 @@ func RecoveryTxMiddleware(txh tx.Handler) tx.Handler {
 	return recoveryTxHandler{next: txh}
 }
 
+// RecoveryMiddleware defines a middleware that catches all panics that happen
+// in inner middlewares, like RecoveryTxMiddleware, and lets the given handler
+// convert the recovered value into an error. Out-of-gas panics are always
+// converted into sdkerrors.ErrOutOfGas.
+//
+// Be careful, it won't catch any panics happening outside!
+func RecoveryMiddleware(handler RecoveryHandler) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return recoveryTxHandler{next: txh, handler: handler}
+	}
+}
+
 var _ tx.Handler = recoveryTxHandler{}
 
 // CheckTx implements tx.Handler.CheckTx method.
@@ -29,7 +47,7 @@ func (txh recoveryTxHandler) CheckTx(ctx context.Context, req tx.Request, checkR
 	// Panic recovery.
 	defer func() {
 		if r := recover(); r != nil {
-			err = handleRecovery(r, sdkCtx)
+			err = txh.handleRecovery(r, sdkCtx)
 		}
 	}()
 
@@ -42,7 +60,7 @@ func (txh recoveryTxHandler) DeliverTx(ctx context.Context, req tx.Request) (res
 	// Panic recovery.
 	defer func() {
 		if r := recover(); r != nil {
-			err = handleRecovery(r, sdkCtx)
+			err = txh.handleRecovery(r, sdkCtx)
 		}
 	}()
 
@@ -55,24 +73,28 @@ func (txh recoveryTxHandler) SimulateTx(ctx context.Context, req tx.Request) (re
 	// Panic recovery.
 	defer func() {
 		if r := recover(); r != nil {
-			err = handleRecovery(r, sdkCtx)
+			err = txh.handleRecovery(r, sdkCtx)
 		}
 	}()
 
 	return txh.next.SimulateTx(ctx, req)
 }
 
-func handleRecovery(r interface{}, sdkCtx sdk.Context) error {
-	switch r := r.(type) {
-	case sdk.ErrorOutOfGas:
+func (txh recoveryTxHandler) handleRecovery(r interface{}, sdkCtx sdk.Context) error {
+	if r, ok := r.(sdk.ErrorOutOfGas); ok {
 		return sdkerrors.Wrapf(sdkerrors.ErrOutOfGas,
 			"out of gas in location: %v; gasWanted: %d, gasUsed: %d",
 			r.Descriptor, sdkCtx.GasMeter().Limit(), sdkCtx.GasMeter().GasConsumed(),
 		)
+	}
 
-	default:
-		return sdkerrors.ErrPanic.Wrapf(
-			"recovered: %v\nstack:\n%v", r, string(debug.Stack()),
-		)
+	if txh.handler != nil {
+		if err := txh.handler(r); err != nil {
+			return err
+		}
 	}
+
+	return sdkerrors.ErrPanic.Wrapf(
+		"recovered: %v\nstack:\n%v", r, string(debug.Stack()),
+	)
 }
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// txHashCache holds the hashes of the last delivered txs, evicting the oldest
+// hash once it holds its maximum number of hashes.
+type txHashCache struct {
+	mtx sync.Mutex
+
+	// hashes is a ring buffer of the cached hashes, next being the position
+	// of the next hash to insert.
+	hashes [][sha256.Size]byte
+	next   int
+	full   bool
+	seen   map[[sha256.Size]byte]bool
+}
+
+func newTxHashCache(maxEntries int) *txHashCache {
+	return &txHashCache{
+		hashes: make([][sha256.Size]byte, maxEntries),
+		seen:   make(map[[sha256.Size]byte]bool, maxEntries),
+	}
+}
+
+// has returns true if hash is cached.
+func (c *txHashCache) has(hash [sha256.Size]byte) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.seen[hash]
+}
+
+// add adds hash to the cache, unless it is already cached.
+func (c *txHashCache) add(hash [sha256.Size]byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.seen[hash] {
+		return
+	}
+
+	if c.full {
+		delete(c.seen, c.hashes[c.next])
+	}
+	c.hashes[c.next] = hash
+	c.seen[hash] = true
+	c.next = (c.next + 1) % len(c.hashes)
+	if c.next == 0 {
+		c.full = true
+	}
+}
+
+type replayProtectionTxHandler struct {
+	cache *txHashCache
+	next  tx.Handler
+}
+
+// ReplayProtectionMiddleware defines a middleware that rejects in CheckTx,
+// including ReCheckTx, the txs whose bytes, as returned by
+// sdk.Context.TxBytes, are identical to the bytes of one of the last
+// maxEntries txs processed by DeliverTx. This keeps the accidental double
+// submission of identical txs out of the mempool, as defense in depth or for
+// chains without sequence-based accounts. DeliverTx only records the txs and
+// never rejects them, and SimulateTx is not affected. If maxEntries is not
+// positive, no tx is rejected.
+//
+// The cache is held in memory, so it is empty after a restart and differs
+// between nodes. This is why it only filters the mempool: rejecting txs in
+// DeliverTx based on it could make the results of blocks diverge between
+// nodes.
+func ReplayProtectionMiddleware(maxEntries int) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		if maxEntries <= 0 {
+			return txh
+		}
+
+		return replayProtectionTxHandler{
+			cache: newTxHashCache(maxEntries),
+			next:  txh,
+		}
+	}
+}
+
+var _ tx.Handler = replayProtectionTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx.
+func (txh replayProtectionTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	txBytes := sdk.UnwrapSDKContext(ctx).TxBytes()
+	if len(txBytes) != 0 {
+		if hash := sha256.Sum256(txBytes); txh.cache.has(hash) {
+			return tx.Response{}, tx.ResponseCheckTx{}, sdkerrors.Wrapf(sdkerrors.ErrTxInMempoolCache, "tx %X was already delivered", hash)
+		}
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx.
+func (txh replayProtectionTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if txBytes := sdk.UnwrapSDKContext(ctx).TxBytes(); len(txBytes) != 0 {
+		txh.cache.add(sha256.Sum256(txBytes))
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx.
+func (txh replayProtectionTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
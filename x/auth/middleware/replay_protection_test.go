@@ -0,0 +1,53 @@
+package middleware_test
+
+import (
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestReplayProtectionMiddleware() {
+	ctx := s.SetupTest(false)
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ReplayProtectionMiddleware(2))
+	req := tx.Request{Tx: txTest{}}
+
+	deliver := func(txBytes string) error {
+		_, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx.WithTxBytes([]byte(txBytes))), req)
+		return err
+	}
+	check := func(txBytes string, checkType abci.CheckTxType) error {
+		_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx.WithTxBytes([]byte(txBytes))), req, tx.RequestCheckTx{Type: checkType})
+		return err
+	}
+
+	s.Require().NoError(check("tx1", abci.CheckTxType_New))
+	s.Require().NoError(deliver("tx1"))
+	s.Require().NoError(deliver("tx2"))
+	s.Require().ErrorIs(check("tx1", abci.CheckTxType_New), sdkerrors.ErrTxInMempoolCache)
+	s.Require().ErrorIs(check("tx2", abci.CheckTxType_Recheck), sdkerrors.ErrTxInMempoolCache)
+	s.Require().NoError(check("tx3", abci.CheckTxType_New))
+
+	// DeliverTx and SimulateTx are never rejected, so that block results
+	// don't depend on the cache
+	s.Require().NoError(deliver("tx1"))
+	_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx.WithTxBytes([]byte("tx1"))), req)
+	s.Require().NoError(err)
+
+	// the oldest hash is evicted once the cache is full
+	s.Require().NoError(deliver("tx3"))
+	s.Require().NoError(check("tx1", abci.CheckTxType_New))
+	s.Require().ErrorIs(check("tx2", abci.CheckTxType_New), sdkerrors.ErrTxInMempoolCache)
+	s.Require().ErrorIs(check("tx3", abci.CheckTxType_New), sdkerrors.ErrTxInMempoolCache)
+
+	// txs without bytes aren't tracked
+	s.Require().NoError(deliver(""))
+	s.Require().NoError(check("", abci.CheckTxType_New))
+
+	// a non-positive size disables the middleware
+	txHandler = middleware.ComposeMiddlewares(noopTxHandler, middleware.ReplayProtectionMiddleware(0))
+	s.Require().NoError(deliver("tx1"))
+	s.Require().NoError(check("tx1", abci.CheckTxType_New))
+}
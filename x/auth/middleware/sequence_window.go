@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+type sequenceWindowTxHandler struct {
+	ak     AccountKeeper
+	window uint64
+	next   tx.Handler
+}
+
+// SequenceWindowMiddleware defines a middleware that rejects in CheckTx the
+// txs with a signature whose sequence is more than window ahead of the
+// current sequence of the signer account, since such txs can't be executed
+// before many other txs and would only bloat the mempool. DeliverTx and
+// SimulateTx are passed through, as sequences are validated by the signature
+// verification middleware there. A window of 0 disables the check.
+// CONTRACT: Tx must implement SigVerifiableTx interface
+func SequenceWindowMiddleware(ak AccountKeeper, window uint64) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return sequenceWindowTxHandler{
+			ak:     ak,
+			window: window,
+			next:   txh,
+		}
+	}
+}
+
+var _ tx.Handler = sequenceWindowTxHandler{}
+
+func (txh sequenceWindowTxHandler) checkSequenceWindow(ctx context.Context, sdkTx sdk.Tx) error {
+	if txh.window == 0 {
+		return nil
+	}
+
+	sigTx, ok := sdkTx.(authsigning.SigVerifiableTx)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "invalid transaction type")
+	}
+
+	sigs, err := sigTx.GetSignaturesV2()
+	if err != nil {
+		return err
+	}
+
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	signerAddrs := sigTx.GetSigners()
+	for i, sig := range sigs {
+		if i >= len(signerAddrs) {
+			// the number of signatures is checked by the signature
+			// verification middleware
+			break
+		}
+
+		acc, err := GetSignerAcc(sdkCtx, txh.ak, signerAddrs[i])
+		if err != nil {
+			return err
+		}
+
+		if sig.Sequence > acc.GetSequence() && sig.Sequence-acc.GetSequence() > txh.window {
+			return sdkerrors.Wrapf(
+				sdkerrors.ErrWrongSequence,
+				"account sequence %d is more than %d ahead of the current sequence %d", sig.Sequence, txh.window, acc.GetSequence(),
+			)
+		}
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh sequenceWindowTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkSequenceWindow(ctx, req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh sequenceWindowTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh sequenceWindowTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
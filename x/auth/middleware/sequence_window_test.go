@@ -0,0 +1,64 @@
+package middleware_test
+
+import (
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestSequenceWindowMiddleware() {
+	ctx := s.SetupTest(true)
+	accounts := s.createTestAccounts(ctx, 1, sdk.NewCoins(sdk.NewInt64Coin("atom", 1000)))
+	acc := accounts[0].acc
+	s.Require().NoError(acc.SetSequence(5))
+	s.app.AccountKeeper.SetAccount(ctx, acc)
+
+	newTx := func(seq uint64) tx.Request {
+		txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+		s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(acc.GetAddress())))
+		txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+		txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+		privs, accNums, accSeqs := []cryptotypes.PrivKey{accounts[0].priv}, []uint64{accounts[0].accNum}, []uint64{seq}
+		testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+		s.Require().NoError(err)
+		return tx.Request{Tx: testTx}
+	}
+
+	testCases := []struct {
+		name   string
+		window uint64
+		seq    uint64
+		expErr bool
+	}{
+		{"past sequence", 3, 1, false},
+		{"current sequence", 3, 5, false},
+		{"within the window", 3, 8, false},
+		{"beyond the window", 3, 9, true},
+		{"disabled", 0, 100, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.SequenceWindowMiddleware(s.app.AccountKeeper, tc.window))
+			req := newTx(tc.seq)
+
+			_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			if tc.expErr {
+				s.Require().ErrorIs(err, sdkerrors.ErrWrongSequence)
+			} else {
+				s.Require().NoError(err)
+			}
+
+			// DeliverTx and SimulateTx are passed through
+			_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+			_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+type signerAllowlistTxHandler struct {
+	allowed map[string]bool
+	next    tx.Handler
+}
+
+// SignerAllowlistMiddleware defines a middleware that rejects in CheckTx and
+// DeliverTx the txs with a signer whose bech32 address isn't in allowed, with
+// an error naming the first such signer, e.g. for the permissioned phase of a
+// chain. SimulateTx is passed through. An empty allowed set accepts any signer,
+// so that a missing configuration can't halt the chain.
+// CONTRACT: Tx must implement SigVerifiableTx interface
+func SignerAllowlistMiddleware(allowed map[string]bool) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return signerAllowlistTxHandler{
+			allowed: allowed,
+			next:    txh,
+		}
+	}
+}
+
+var _ tx.Handler = signerAllowlistTxHandler{}
+
+func (txh signerAllowlistTxHandler) checkSigners(sdkTx sdk.Tx) error {
+	if len(txh.allowed) == 0 {
+		return nil
+	}
+
+	sigTx, ok := sdkTx.(authsigning.SigVerifiableTx)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "invalid transaction type")
+	}
+
+	for _, signer := range sigTx.GetSigners() {
+		if !txh.allowed[signer.String()] {
+			return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "signer %s is not allowed", signer)
+		}
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh signerAllowlistTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := txh.checkSigners(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh signerAllowlistTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if err := txh.checkSigners(req.Tx); err != nil {
+		return tx.Response{}, err
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh signerAllowlistTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestSignerAllowlistMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+	_, _, addr2 := testdata.KeyTestPubAddr()
+
+	testCases := []struct {
+		name      string
+		allowed   map[string]bool
+		signers   []sdk.AccAddress
+		notSigner sdk.AccAddress
+	}{
+		{"empty allowlist accepts any signer", nil, []sdk.AccAddress{addr1}, nil},
+		{"allowed signer", map[string]bool{addr1.String(): true}, []sdk.AccAddress{addr1}, nil},
+		{"allowed signers", map[string]bool{addr1.String(): true, addr2.String(): true}, []sdk.AccAddress{addr1, addr2}, nil},
+		{"signer not allowed", map[string]bool{addr1.String(): true}, []sdk.AccAddress{addr2}, addr2},
+		{"some signers not allowed", map[string]bool{addr1.String(): true}, []sdk.AccAddress{addr1, addr2}, addr2},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(tc.signers...)))
+			req := tx.Request{Tx: txBuilder.GetTx()}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.SignerAllowlistMiddleware(tc.allowed))
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.notSigner != nil {
+					s.Require().ErrorIs(err, sdkerrors.ErrUnauthorized)
+					s.Require().Contains(err.Error(), tc.notSigner.String())
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+
+			// SimulateTx is passed through
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+}
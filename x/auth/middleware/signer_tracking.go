@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+// SignerTracker accumulates the distinct signers of the txs delivered in a
+// block. It is filled by SignerTrackingMiddleware, and the application
+// decides when to retrieve the signers with Flush, typically in EndBlock.
+type SignerTracker struct {
+	mtx sync.Mutex
+
+	height  int64
+	signers []sdk.AccAddress
+	seen    map[string]bool
+}
+
+// NewSignerTracker returns a new empty SignerTracker.
+func NewSignerTracker() *SignerTracker {
+	return &SignerTracker{seen: make(map[string]bool)}
+}
+
+// add records the signers of a tx delivered at the given height. Signers
+// recorded at a previous height which weren't flushed are discarded, so that
+// they don't bleed into the set of another block.
+func (t *SignerTracker) add(height int64, signers []sdk.AccAddress) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if height != t.height {
+		t.height = height
+		t.reset()
+	}
+
+	for _, signer := range signers {
+		if t.seen[string(signer)] {
+			continue
+		}
+		t.seen[string(signer)] = true
+		t.signers = append(t.signers, signer)
+	}
+}
+
+func (t *SignerTracker) reset() {
+	t.signers = nil
+	t.seen = make(map[string]bool)
+}
+
+// Flush returns the distinct signers recorded since the last flush for the
+// block of the last delivered tx, in the order in which they were first seen,
+// and resets the tracker.
+func (t *SignerTracker) Flush() []sdk.AccAddress {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	signers := t.signers
+	t.reset()
+
+	return signers
+}
+
+type signerTrackingTxHandler struct {
+	tracker *SignerTracker
+	next    tx.Handler
+}
+
+// SignerTrackingMiddleware defines a middleware that records in tracker the
+// signers of each tx processed by DeliverTx, whether it succeeds or not, since
+// it's included in the block either way. CheckTx and SimulateTx are not
+// tracked. The middleware only collects signers, see SignerTracker.Flush to
+// retrieve them. It must be placed inside of the TxDecoderMiddleware, and a
+// nil tracker passes all txs through.
+func SignerTrackingMiddleware(tracker *SignerTracker) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return signerTrackingTxHandler{
+			tracker: tracker,
+			next:    txh,
+		}
+	}
+}
+
+var _ tx.Handler = signerTrackingTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx.
+func (txh signerTrackingTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx.
+func (txh signerTrackingTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if txh.tracker != nil {
+		// The tx might not be decoded yet if this middleware is placed outside
+		// of the TxDecoderMiddleware.
+		if sigTx, ok := req.Tx.(authsigning.SigVerifiableTx); ok {
+			txh.tracker.add(sdk.UnwrapSDKContext(ctx).BlockHeight(), sigTx.GetSigners())
+		}
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx.
+func (txh signerTrackingTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,98 @@
+package middleware_test
+
+import (
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestSignerTrackingMiddleware() {
+	ctx := s.SetupTest(true).WithBlockHeight(1)
+	priv1, _, addr1 := testdata.KeyTestPubAddr()
+	priv2, _, addr2 := testdata.KeyTestPubAddr()
+
+	newTx := func(signers ...sdk.AccAddress) tx.Request {
+		txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+		msgs := make([]sdk.Msg, len(signers))
+		privs := make([]cryptotypes.PrivKey, len(signers))
+		for i, signer := range signers {
+			msgs[i] = testdata.NewTestMsg(signer)
+			privs[i] = priv1
+			if signer.Equals(addr2) {
+				privs[i] = priv2
+			}
+		}
+		s.Require().NoError(txBuilder.SetMsgs(msgs...))
+		txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+		txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+		testTx, txBytes, err := s.createTestTx(txBuilder, privs, make([]uint64, len(privs)), make([]uint64, len(privs)), ctx.ChainID())
+		s.Require().NoError(err)
+		return tx.Request{Tx: testTx, TxBytes: txBytes}
+	}
+
+	tracker := middleware.NewSignerTracker()
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.SignerTrackingMiddleware(tracker))
+
+	_, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), newTx(addr2))
+	s.Require().NoError(err)
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), newTx(addr1, addr2))
+	s.Require().NoError(err)
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), newTx(addr1))
+	s.Require().NoError(err)
+
+	// CheckTx and SimulateTx aren't tracked
+	_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), newTx(addr1), tx.RequestCheckTx{})
+	s.Require().NoError(err)
+	_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), newTx(addr1))
+	s.Require().NoError(err)
+
+	s.Require().Equal([]sdk.AccAddress{addr2, addr1}, tracker.Flush())
+	s.Require().Empty(tracker.Flush())
+
+	// signers which weren't flushed don't bleed into the next block
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx.WithBlockHeight(2)), newTx(addr1))
+	s.Require().NoError(err)
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx.WithBlockHeight(3)), newTx(addr2))
+	s.Require().NoError(err)
+	s.Require().Equal([]sdk.AccAddress{addr2}, tracker.Flush())
+
+	// a nil tracker passes txs through
+	txHandler = middleware.ComposeMiddlewares(noopTxHandler, middleware.SignerTrackingMiddleware(nil))
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), newTx(addr1))
+	s.Require().NoError(err)
+
+	// the signers of the txs delivered by BaseApp are flushed in EndBlock
+	app := baseapp.NewBaseApp("test", log.NewNopLogger(), dbm.NewMemDB())
+	app.SetTxHandler(middleware.ComposeMiddlewares(
+		noopTxHandler,
+		middleware.NewTxDecoderMiddleware(s.clientCtx.TxConfig.TxDecoder()),
+		middleware.SignerTrackingMiddleware(tracker),
+	))
+	var flushed []sdk.AccAddress
+	app.SetEndBlocker(func(sdk.Context, abci.RequestEndBlock) abci.ResponseEndBlock {
+		flushed = tracker.Flush()
+		return abci.ResponseEndBlock{}
+	})
+	s.Require().NoError(app.LoadLatestVersion())
+	app.InitChain(abci.RequestInitChain{})
+
+	for height, signers := range [][]sdk.AccAddress{{addr1}, {addr2, addr1}} {
+		app.BeginBlock(abci.RequestBeginBlock{Header: tmproto.Header{Height: int64(height) + 1}})
+		for _, signer := range signers {
+			res := app.DeliverTx(abci.RequestDeliverTx{Tx: newTx(signer).TxBytes})
+			s.Require().True(res.IsOK(), res.Log)
+		}
+		app.EndBlock(abci.RequestEndBlock{Height: int64(height) + 1})
+		app.Commit()
+
+		s.Require().Equal(signers, flushed)
+	}
+}
@@ -160,6 +160,7 @@ type validateSigCountTxHandler struct {
 // ValidateSigCountMiddleware takes in Params and returns errors if there are too many signatures in the tx for the given params
 // otherwise it calls next middleware
 // Use this middleware to set parameterized limit on number of signatures in tx
+// Keys nested in multisig keys are counted recursively, see CountSubKeys.
 // CONTRACT: Tx must implement SigVerifiableTx interface
 func ValidateSigCountMiddleware(ak AccountKeeper) tx.Middleware {
 	return func(txh tx.Handler) tx.Handler {
@@ -14,10 +14,12 @@ import (
 	"github.com/cosmos/cosmos-sdk/simapp"
 	"github.com/cosmos/cosmos-sdk/testutil/testdata"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
 	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 	"github.com/cosmos/cosmos-sdk/x/auth/types"
 	abci "github.com/tendermint/tendermint/abci/types"
 )
@@ -409,3 +411,60 @@ func (s *MWTestSuite) TestIncrementSequenceMiddleware() {
 		s.Require().Equal(tc.expectedSeq, s.app.AccountKeeper.GetAccount(ctx, addr).GetSequence())
 	}
 }
+
+// pubKeysTx is a test tx overriding the public keys of its signers.
+type pubKeysTx struct {
+	authsigning.Tx
+	pubKeys []cryptotypes.PubKey
+}
+
+func (t pubKeysTx) GetPubKeys() ([]cryptotypes.PubKey, error) { return t.pubKeys, nil }
+
+func (s *MWTestSuite) TestValidateSigCountNestedMultisig() {
+	testTx, _, ctx, _ := s.setupGasTx()
+	txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.ValidateSigCountMiddleware(s.app.AccountKeeper))
+	sigLimit := s.app.AccountKeeper.GetParams(ctx).TxSigLimit
+
+	genPubKeys := func(n uint64) []cryptotypes.PubKey {
+		var ret []cryptotypes.PubKey
+		for i := uint64(0); i < n; i++ {
+			ret = append(ret, secp256k1.GenPrivKey().PubKey())
+		}
+		return ret
+	}
+	// nestedMultisig returns a 1-of-2 multisig key whose first key is a
+	// multisig of n keys.
+	nestedMultisig := func(n uint64) cryptotypes.PubKey {
+		inner := kmultisig.NewLegacyAminoPubKey(1, genPubKeys(n))
+		return kmultisig.NewLegacyAminoPubKey(1, []cryptotypes.PubKey{inner, secp256k1.GenPrivKey().PubKey()})
+	}
+
+	testCases := []struct {
+		name      string
+		pubKeys   []cryptotypes.PubKey
+		expectErr bool
+	}{
+		{"top-level keys within limit", genPubKeys(sigLimit), false},
+		{"top-level keys above limit", genPubKeys(sigLimit + 1), true},
+		{"nested multisig within limit", []cryptotypes.PubKey{nestedMultisig(sigLimit - 1)}, false},
+		{"nested multisig above limit", []cryptotypes.PubKey{nestedMultisig(sigLimit)}, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			req := tx.Request{Tx: pubKeysTx{testTx, tc.pubKeys}}
+
+			_, _, checkErr := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			_, deliverErr := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			for _, err := range []error{checkErr, deliverErr} {
+				if tc.expectErr {
+					s.Require().ErrorIs(err, sdkerrors.ErrTooManySignatures)
+				} else {
+					s.Require().NoError(err)
+				}
+			}
+		})
+	}
+}
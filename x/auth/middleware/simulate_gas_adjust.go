@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type simulateGasAdjustTxHandler struct {
+	multiplier sdk.Dec
+	next       tx.Handler
+}
+
+// SimulateGasAdjustMiddleware defines a middleware that multiplies the gas
+// used reported by SimulateTx by multiplier, rounding up, so that the
+// estimates returned by the simulate endpoint are already padded. CheckTx and
+// DeliverTx are passed through. It returns an error if multiplier is less than
+// 1.
+func SimulateGasAdjustMiddleware(multiplier sdk.Dec) (tx.Middleware, error) {
+	if multiplier.IsNil() || multiplier.LT(sdk.OneDec()) {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "simulate gas multiplier must be at least 1, got %s", multiplier)
+	}
+
+	return func(txh tx.Handler) tx.Handler {
+		return simulateGasAdjustTxHandler{
+			multiplier: multiplier,
+			next:       txh,
+		}
+	}, nil
+}
+
+var _ tx.Handler = simulateGasAdjustTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh simulateGasAdjustTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh simulateGasAdjustTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh simulateGasAdjustTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	res, err := txh.next.SimulateTx(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	adjusted := sdk.NewDecFromInt(sdk.NewIntFromUint64(res.GasUsed)).Mul(txh.multiplier).Ceil().TruncateInt()
+	if adjusted.IsUint64() {
+		res.GasUsed = adjusted.Uint64()
+	} else {
+		res.GasUsed = math.MaxUint64
+	}
+
+	return res, nil
+}
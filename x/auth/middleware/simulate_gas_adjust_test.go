@@ -0,0 +1,70 @@
+package middleware_test
+
+import (
+	"context"
+	"math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestSimulateGasAdjustMiddleware() {
+	testTx, _, ctx, _ := s.setupGasTx()
+	req := tx.Request{Tx: testTx}
+
+	testCases := []struct {
+		name       string
+		multiplier sdk.Dec
+		gasUsed    uint64
+		expGasUsed uint64
+	}{
+		{"multiplier of 1", sdk.OneDec(), 1000, 1000},
+		{"exact multiple", sdk.MustNewDecFromStr("1.5"), 1000, 1500},
+		{"rounds up", sdk.MustNewDecFromStr("1.5"), 1001, 1502},
+		{"zero gas", sdk.NewDec(2), 0, 0},
+		{"overflow", sdk.NewDec(2), math.MaxUint64, math.MaxUint64},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			gasTxHandler := customTxHandler{func(_ context.Context, _ tx.Request) (tx.Response, error) {
+				return tx.Response{GasUsed: tc.gasUsed}, nil
+			}}
+			mw, err := middleware.SimulateGasAdjustMiddleware(tc.multiplier)
+			s.Require().NoError(err)
+			txHandler := middleware.ComposeMiddlewares(gasTxHandler, mw)
+
+			res, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.expGasUsed, res.GasUsed)
+
+			// CheckTx and DeliverTx are passed through
+			res, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			s.Require().NoError(err)
+			s.Require().Equal(tc.gasUsed, res.GasUsed)
+			res, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.gasUsed, res.GasUsed)
+		})
+	}
+
+	// errors are passed through
+	failingTxHandler := customTxHandler{func(_ context.Context, _ tx.Request) (tx.Response, error) {
+		return tx.Response{GasUsed: 10}, sdkerrors.ErrOutOfGas
+	}}
+	mw, err := middleware.SimulateGasAdjustMiddleware(sdk.NewDec(2))
+	s.Require().NoError(err)
+	res, err := middleware.ComposeMiddlewares(failingTxHandler, mw).SimulateTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().ErrorIs(err, sdkerrors.ErrOutOfGas)
+	s.Require().Equal(uint64(10), res.GasUsed)
+
+	// multipliers must be at least 1
+	_, err = middleware.SimulateGasAdjustMiddleware(sdk.MustNewDecFromStr("0.9"))
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+	_, err = middleware.SimulateGasAdjustMiddleware(sdk.Dec{})
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type simulateGasCeilingTxHandler struct {
+	maxGas uint64
+	next   tx.Handler
+}
+
+// SimulateGasCeilingMiddleware defines a middleware that rejects simulations
+// reporting more than maxGas gas used, which usually indicates a buggy client,
+// so that the simulate endpoint doesn't return wildly inflated estimates.
+// CheckTx and DeliverTx are passed through. A maxGas of 0 means no limit.
+func SimulateGasCeilingMiddleware(maxGas uint64) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return simulateGasCeilingTxHandler{
+			maxGas: maxGas,
+			next:   txh,
+		}
+	}
+}
+
+var _ tx.Handler = simulateGasCeilingTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh simulateGasCeilingTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh simulateGasCeilingTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh simulateGasCeilingTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	res, err := txh.next.SimulateTx(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	if txh.maxGas != 0 && res.GasUsed > txh.maxGas {
+		return tx.Response{}, sdkerrors.Wrapf(sdkerrors.ErrOutOfGas,
+			"simulated gas used %d exceeds the simulation limit of %d", res.GasUsed, txh.maxGas,
+		)
+	}
+
+	return res, nil
+}
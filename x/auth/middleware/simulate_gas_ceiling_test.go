@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestSimulateGasCeilingMiddleware() {
+	testTx, _, ctx, _ := s.setupGasTx()
+	req := tx.Request{Tx: testTx}
+
+	testCases := []struct {
+		name    string
+		maxGas  uint64
+		gasUsed uint64
+		expErr  bool
+	}{
+		{"below the limit", 1000, 999, false},
+		{"at the limit", 1000, 1000, false},
+		{"above the limit", 1000, 1001, true},
+		{"unlimited", 0, 1000000, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			gasTxHandler := customTxHandler{func(_ context.Context, _ tx.Request) (tx.Response, error) {
+				return tx.Response{GasUsed: tc.gasUsed}, nil
+			}}
+			txHandler := middleware.ComposeMiddlewares(gasTxHandler, middleware.SimulateGasCeilingMiddleware(tc.maxGas))
+
+			res, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			if tc.expErr {
+				s.Require().ErrorIs(err, sdkerrors.ErrOutOfGas)
+			} else {
+				s.Require().NoError(err)
+				s.Require().Equal(tc.gasUsed, res.GasUsed)
+			}
+
+			// CheckTx and DeliverTx are passed through
+			res, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			s.Require().NoError(err)
+			s.Require().Equal(tc.gasUsed, res.GasUsed)
+			res, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.gasUsed, res.GasUsed)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type simulateTimeoutTxHandler struct {
+	timeout time.Duration
+	next    tx.Handler
+}
+
+// SimulateTimeoutMiddleware defines a middleware that bounds SimulateTx by the
+// given timeout. The inner middlewares receive a context with the derived
+// deadline, and an ErrInvalidRequest wrapping context.DeadlineExceeded is
+// returned if the deadline is exceeded. CheckTx and DeliverTx are not affected.
+// A zero timeout disables the check.
+func SimulateTimeoutMiddleware(timeout time.Duration) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return simulateTimeoutTxHandler{
+			timeout: timeout,
+			next:    txh,
+		}
+	}
+}
+
+var _ tx.Handler = simulateTimeoutTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh simulateTimeoutTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh simulateTimeoutTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh simulateTimeoutTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if txh.timeout == 0 {
+		return txh.next.SimulateTx(ctx, req)
+	}
+
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	timeoutCtx, cancel := context.WithTimeout(sdkCtx.Context(), txh.timeout)
+	defer cancel()
+
+	res, err := txh.next.SimulateTx(sdk.WrapSDKContext(sdkCtx.WithContext(timeoutCtx)), req)
+	if timeoutCtx.Err() == context.DeadlineExceeded {
+		return tx.Response{}, simulateTimeoutError{timeout: txh.timeout}
+	}
+
+	return res, err
+}
+
+// simulateTimeoutError is the error returned when a simulation exceeds its
+// timeout. It is an ErrInvalidRequest, which gives its ABCI code, and also
+// matches context.DeadlineExceeded with errors.Is.
+type simulateTimeoutError struct {
+	timeout time.Duration
+}
+
+func (e simulateTimeoutError) Error() string {
+	return fmt.Sprintf("simulation exceeded timeout of %s: %s: %s", e.timeout, context.DeadlineExceeded, sdkerrors.ErrInvalidRequest)
+}
+
+func (e simulateTimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+func (e simulateTimeoutError) Cause() error {
+	return sdkerrors.ErrInvalidRequest
+}
+
+func (e simulateTimeoutError) Unwrap() error {
+	return sdkerrors.ErrInvalidRequest
+}
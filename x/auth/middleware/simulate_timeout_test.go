@@ -0,0 +1,63 @@
+package middleware_test
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestSimulateTimeout() {
+	testTx, _, ctx, _ := s.setupGasTx()
+	req := tx.Request{Tx: testTx}
+
+	// waitTxHandler waits until the context is done, if it has a deadline.
+	waitTxHandler := customTxHandler{func(ctx context.Context, _ tx.Request) (tx.Response, error) {
+		sdkCtx := sdk.UnwrapSDKContext(ctx)
+		if _, ok := sdkCtx.Context().Deadline(); !ok {
+			return tx.Response{}, nil
+		}
+		<-sdkCtx.Context().Done()
+
+		return tx.Response{}, nil
+	}}
+
+	testCases := []struct {
+		name      string
+		timeout   time.Duration
+		next      tx.Handler
+		expectErr bool
+	}{
+		{"no timeout", 0, waitTxHandler, false},
+		{"simulation within timeout", time.Minute, noopTxHandler, false},
+		{"simulation exceeding timeout", time.Millisecond, waitTxHandler, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txHandler := middleware.ComposeMiddlewares(tc.next, middleware.SimulateTimeoutMiddleware(tc.timeout))
+
+			_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			if tc.expectErr {
+				s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+				s.Require().ErrorIs(err, context.DeadlineExceeded)
+				codespace, code, _ := sdkerrors.ABCIInfo(err, false)
+				s.Require().Equal(sdkerrors.ErrInvalidRequest.Codespace(), codespace)
+				s.Require().Equal(sdkerrors.ErrInvalidRequest.ABCICode(), code)
+			} else {
+				s.Require().NoError(err)
+			}
+
+			// CheckTx and DeliverTx are not bounded by the timeout
+			_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			s.Require().NoError(err)
+			_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+}
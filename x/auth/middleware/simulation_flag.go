@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type simulateTxKey struct{}
+
+// IsSimulateTx returns true if the context is the context of a SimulateTx
+// call, as flagged by SimulationFlagMiddleware. Keepers can use it to skip
+// work which is only needed when the tx is actually executed.
+func IsSimulateTx(ctx sdk.Context) bool {
+	simulate, _ := ctx.Value(simulateTxKey{}).(bool)
+	return simulate
+}
+
+type simulationFlagTxHandler struct {
+	next tx.Handler
+}
+
+// SimulationFlagMiddleware defines a middleware that flags the context of
+// SimulateTx calls, so that IsSimulateTx returns true for it in the inner
+// middlewares and in msg handlers. The flag is explicitly cleared in CheckTx
+// and DeliverTx, so that it's never set there even if the incoming context was
+// derived from a simulation context.
+func SimulationFlagMiddleware(txh tx.Handler) tx.Handler {
+	return simulationFlagTxHandler{next: txh}
+}
+
+var _ tx.Handler = simulationFlagTxHandler{}
+
+func withSimulateTx(ctx context.Context, simulate bool) context.Context {
+	return sdk.WrapSDKContext(sdk.UnwrapSDKContext(ctx).WithValue(simulateTxKey{}, simulate))
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh simulationFlagTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(withSimulateTx(ctx, false), req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh simulationFlagTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.DeliverTx(withSimulateTx(ctx, false), req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh simulationFlagTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(withSimulateTx(ctx, true), req)
+}
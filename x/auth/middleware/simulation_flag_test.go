@@ -0,0 +1,45 @@
+package middleware_test
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestSimulationFlagMiddleware() {
+	ctx := s.SetupTest(true)
+	req := tx.Request{Tx: txTest{}}
+
+	var simulate bool
+	flagTxHandler := customTxHandler{func(ctx context.Context, _ tx.Request) (tx.Response, error) {
+		simulate = middleware.IsSimulateTx(sdk.UnwrapSDKContext(ctx))
+		return tx.Response{}, nil
+	}}
+	txHandler := middleware.ComposeMiddlewares(flagTxHandler, middleware.SimulationFlagMiddleware)
+
+	s.Require().False(middleware.IsSimulateTx(ctx))
+
+	_, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+	s.Require().True(simulate)
+
+	_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+	s.Require().NoError(err)
+	s.Require().False(simulate)
+
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+	s.Require().False(simulate)
+
+	// the flag is cleared even if the incoming context had it set
+	nested := middleware.ComposeMiddlewares(flagTxHandler, middleware.SimulationFlagMiddleware)
+	outer := customTxHandler{func(ctx context.Context, req tx.Request) (tx.Response, error) {
+		return nested.DeliverTx(ctx, req)
+	}}
+	txHandler = middleware.ComposeMiddlewares(outer, middleware.SimulationFlagMiddleware)
+	_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+	s.Require().False(simulate)
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+type singleSignerTxHandler struct {
+	checkDeliverTx bool
+	next           tx.Handler
+}
+
+// SingleSignerMiddleware defines a middleware that rejects in CheckTx the txs
+// with more than one distinct signer, with an error reporting the number of
+// distinct signers. If checkDeliverTx is true, such txs are rejected in
+// DeliverTx too, otherwise they are only kept out of the mempool, and are
+// still executed if a proposer includes them in a block.
+// SimulateTx is passed through.
+// CONTRACT: Tx must implement SigVerifiableTx interface
+func SingleSignerMiddleware(checkDeliverTx bool) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return singleSignerTxHandler{
+			checkDeliverTx: checkDeliverTx,
+			next:           txh,
+		}
+	}
+}
+
+var _ tx.Handler = singleSignerTxHandler{}
+
+func checkSingleSigner(sdkTx sdk.Tx) error {
+	sigTx, ok := sdkTx.(authsigning.SigVerifiableTx)
+	if !ok {
+		return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "invalid transaction type")
+	}
+
+	signers := map[string]bool{}
+	for _, signer := range sigTx.GetSigners() {
+		signers[signer.String()] = true
+	}
+
+	if len(signers) > 1 {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "tx has %d distinct signers, expected a single signer", len(signers))
+	}
+
+	return nil
+}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh singleSignerTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if err := checkSingleSigner(req.Tx); err != nil {
+		return tx.Response{}, tx.ResponseCheckTx{}, err
+	}
+
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh singleSignerTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if txh.checkDeliverTx {
+		if err := checkSingleSigner(req.Tx); err != nil {
+			return tx.Response{}, err
+		}
+	}
+
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh singleSignerTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
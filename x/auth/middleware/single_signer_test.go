@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestSingleSignerMiddleware() {
+	ctx := s.SetupTest(true)
+	_, _, addr1 := testdata.KeyTestPubAddr()
+	_, _, addr2 := testdata.KeyTestPubAddr()
+
+	testCases := []struct {
+		name           string
+		msgs           []sdk.Msg
+		checkDeliverTx bool
+		expCheckErr    bool
+		expDeliverErr  bool
+	}{
+		{"single signer", []sdk.Msg{testdata.NewTestMsg(addr1)}, true, false, false},
+		{"same signer in several msgs", []sdk.Msg{testdata.NewTestMsg(addr1), testdata.NewTestMsg(addr1)}, true, false, false},
+		{"several signers", []sdk.Msg{testdata.NewTestMsg(addr1, addr2)}, true, true, true},
+		{"several signers in several msgs", []sdk.Msg{testdata.NewTestMsg(addr1), testdata.NewTestMsg(addr2)}, true, true, true},
+		{"several signers, CheckTx only", []sdk.Msg{testdata.NewTestMsg(addr1, addr2)}, false, true, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+			s.Require().NoError(txBuilder.SetMsgs(tc.msgs...))
+			req := tx.Request{Tx: txBuilder.GetTx()}
+			txHandler := middleware.ComposeMiddlewares(noopTxHandler, middleware.SingleSignerMiddleware(tc.checkDeliverTx))
+
+			_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+			if tc.expCheckErr {
+				s.Require().ErrorIs(err, sdkerrors.ErrUnauthorized)
+				s.Require().Contains(err.Error(), "2 distinct signers")
+			} else {
+				s.Require().NoError(err)
+			}
+
+			_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+			if tc.expDeliverErr {
+				s.Require().ErrorIs(err, sdkerrors.ErrUnauthorized)
+			} else {
+				s.Require().NoError(err)
+			}
+
+			// SimulateTx is passed through
+			_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+			s.Require().NoError(err)
+		})
+	}
+}
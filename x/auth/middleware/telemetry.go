@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/armon/go-metrics"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type telemetryTxHandler struct {
+	next tx.Handler
+}
+
+// TelemetryMiddleware defines a middleware that emits, for each of CheckTx,
+// DeliverTx and SimulateTx, the tx processing duration and a success/failure
+// counter, both labeled by the type URL of the first message in the tx. It's a
+// no-op when telemetry is disabled, and never alters the inner middlewares'
+// responses or errors.
+func TelemetryMiddleware(txh tx.Handler) tx.Handler {
+	return telemetryTxHandler{next: txh}
+}
+
+var _ tx.Handler = telemetryTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh telemetryTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	if !telemetry.IsTelemetryEnabled() {
+		return txh.next.CheckTx(ctx, req, checkReq)
+	}
+
+	start := time.Now()
+	res, resCheckTx, err := txh.next.CheckTx(ctx, req, checkReq)
+	emitTxMetrics("check_tx", start, req.Tx, err)
+
+	return res, resCheckTx, err
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh telemetryTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if !telemetry.IsTelemetryEnabled() {
+		return txh.next.DeliverTx(ctx, req)
+	}
+
+	start := time.Now()
+	res, err := txh.next.DeliverTx(ctx, req)
+	emitTxMetrics("deliver_tx", start, req.Tx, err)
+
+	return res, err
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh telemetryTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if !telemetry.IsTelemetryEnabled() {
+		return txh.next.SimulateTx(ctx, req)
+	}
+
+	start := time.Now()
+	res, err := txh.next.SimulateTx(ctx, req)
+	emitTxMetrics("simulate_tx", start, req.Tx, err)
+
+	return res, err
+}
+
+// emitTxMetrics emits the duration and outcome metrics of a tx processed in the
+// given phase.
+func emitTxMetrics(phase string, start time.Time, sdkTx sdk.Tx, err error) {
+	// The tx might not be decoded yet if this middleware is placed outside of
+	// the TxDecoderMiddleware.
+	msgType := "unknown"
+	if sdkTx != nil {
+		if msgs := sdkTx.GetMsgs(); len(msgs) > 0 {
+			msgType = sdk.MsgTypeURL(msgs[0])
+		}
+	}
+
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+
+	labels := []metrics.Label{telemetry.NewLabel("msg_type", msgType)}
+	telemetry.MeasureSinceWithLabels([]string{"tx", phase}, start, labels)
+	telemetry.IncrCounterWithLabels([]string{"tx", phase, outcome}, 1, labels)
+}
@@ -0,0 +1,50 @@
+package middleware_test
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestTelemetryMiddleware() {
+	testTx, _, ctx, _ := s.setupGasTx()
+	failingTxHandler := customTxHandler{func(_ context.Context, _ tx.Request) (tx.Response, error) {
+		return tx.Response{Log: "failed"}, sdkerrors.ErrInvalidRequest
+	}}
+	req := tx.Request{Tx: testTx}
+
+	// telemetry disabled, responses and errors are passed through untouched
+	txHandler := middleware.ComposeMiddlewares(failingTxHandler, middleware.TelemetryMiddleware)
+	res, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+	s.Require().Equal("failed", res.Log)
+
+	m, err := telemetry.New(telemetry.Config{Enabled: true, ServiceName: "test"})
+	s.Require().NoError(err)
+	s.Require().True(telemetry.IsTelemetryEnabled())
+
+	res, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+	s.Require().Equal("failed", res.Log)
+
+	txHandler = middleware.ComposeMiddlewares(noopTxHandler, middleware.TelemetryMiddleware)
+	_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+	s.Require().NoError(err)
+	_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+
+	gr, err := m.Gather(telemetry.FormatText)
+	s.Require().NoError(err)
+	for _, name := range []string{
+		"test.tx.deliver_tx", "test.tx.deliver_tx.failure",
+		"test.tx.check_tx", "test.tx.check_tx.success",
+		"test.tx.simulate_tx", "test.tx.simulate_tx.success",
+		sdk.MsgTypeURL(testTx.GetMsgs()[0]),
+	} {
+		s.Require().Contains(string(gr.Metrics), name)
+	}
+}
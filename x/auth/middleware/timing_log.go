@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// DefaultTimingLogKey is the log key used by TimingLogMiddleware when none is
+// provided.
+const DefaultTimingLogKey = "exec_ms"
+
+type timingLogTxHandler struct {
+	key  string
+	next tx.Handler
+}
+
+// TimingLogMiddleware defines a middleware that appends the duration of the
+// inner DeliverTx handlers, in milliseconds, to the response log as a
+// "<key>=<duration>" token, preserving any existing log. Failed txs are left
+// untouched so that the log doesn't hide the actual error. An empty key
+// defaults to DefaultTimingLogKey.
+//
+// The log isn't part of the consensus-critical DeliverTx results, so the
+// non-deterministic duration doesn't affect the app hash or the results hash.
+func TimingLogMiddleware(key string) tx.Middleware {
+	if key == "" {
+		key = DefaultTimingLogKey
+	}
+
+	return func(txh tx.Handler) tx.Handler {
+		return timingLogTxHandler{
+			key:  key,
+			next: txh,
+		}
+	}
+}
+
+var _ tx.Handler = timingLogTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh timingLogTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh timingLogTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	start := time.Now()
+	res, err := txh.next.DeliverTx(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	token := fmt.Sprintf("%s=%.3f", txh.key, float64(time.Since(start).Microseconds())/1000)
+	if res.Log == "" {
+		res.Log = token
+	} else {
+		res.Log = res.Log + " " + token
+	}
+
+	return res, nil
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh timingLogTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
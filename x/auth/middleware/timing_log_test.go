@@ -0,0 +1,53 @@
+package middleware_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestTimingLogMiddleware() {
+	testTx, _, ctx, _ := s.setupGasTx()
+	req := tx.Request{Tx: testTx}
+	slowTxHandler := customTxHandler{func(_ context.Context, _ tx.Request) (tx.Response, error) {
+		time.Sleep(2 * time.Millisecond)
+		return tx.Response{Log: "[]"}, nil
+	}}
+
+	txHandler := middleware.ComposeMiddlewares(slowTxHandler, middleware.TimingLogMiddleware(""))
+	res, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+	s.Require().True(strings.HasPrefix(res.Log, "[] exec_ms="), res.Log)
+	ms, err := strconv.ParseFloat(strings.TrimPrefix(res.Log, "[] exec_ms="), 64)
+	s.Require().NoError(err)
+	s.Require().GreaterOrEqual(ms, 2.0)
+
+	// the key is configurable and empty logs don't get a separator
+	txHandler = middleware.ComposeMiddlewares(noopTxHandler, middleware.TimingLogMiddleware("duration"))
+	res, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+	s.Require().True(strings.HasPrefix(res.Log, "duration="), res.Log)
+
+	// CheckTx and SimulateTx are passed through
+	res, _, err = txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+	s.Require().NoError(err)
+	s.Require().Empty(res.Log)
+	res, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+	s.Require().Empty(res.Log)
+
+	// failed txs are left untouched
+	failingTxHandler := customTxHandler{func(_ context.Context, _ tx.Request) (tx.Response, error) {
+		return tx.Response{Log: "failed"}, sdkerrors.ErrInvalidRequest
+	}}
+	txHandler = middleware.ComposeMiddlewares(failingTxHandler, middleware.TimingLogMiddleware(""))
+	res, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+	s.Require().Equal("failed", res.Log)
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type tracingTxHandler struct {
+	tracer trace.Tracer
+	next   tx.Handler
+}
+
+// TracingMiddleware defines a middleware that wraps each of CheckTx, DeliverTx
+// and SimulateTx in an OpenTelemetry span, tagged with the tx's message type
+// URLs and byte size. The span is set on the context passed to the inner
+// middlewares, including on sdk.Context's Context(), so that keepers can
+// create child spans.
+func TracingMiddleware(tracer trace.Tracer) tx.Middleware {
+	return func(txh tx.Handler) tx.Handler {
+		return tracingTxHandler{
+			tracer: tracer,
+			next:   txh,
+		}
+	}
+}
+
+var _ tx.Handler = tracingTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh tracingTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	ctx, span := txh.startSpan(ctx, "CheckTx", req)
+	defer span.End()
+
+	res, resCheckTx, err := txh.next.CheckTx(ctx, req, checkReq)
+	recordSpanError(span, err)
+
+	return res, resCheckTx, err
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh tracingTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	ctx, span := txh.startSpan(ctx, "DeliverTx", req)
+	defer span.End()
+
+	res, err := txh.next.DeliverTx(ctx, req)
+	recordSpanError(span, err)
+
+	return res, err
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh tracingTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	ctx, span := txh.startSpan(ctx, "SimulateTx", req)
+	defer span.End()
+
+	res, err := txh.next.SimulateTx(ctx, req)
+	recordSpanError(span, err)
+
+	return res, err
+}
+
+// startSpan starts a span for the given tx.Handler method, and returns the
+// context to pass to the next tx.Handler.
+func (txh tracingTxHandler) startSpan(ctx context.Context, method string, req tx.Request) (context.Context, trace.Span) {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	// The tx might not be decoded yet if this middleware is placed outside of
+	// the TxDecoderMiddleware.
+	var msgTypes []string
+	if req.Tx != nil {
+		for _, msg := range req.Tx.GetMsgs() {
+			msgTypes = append(msgTypes, sdk.MsgTypeURL(msg))
+		}
+	}
+
+	spanCtx, span := txh.tracer.Start(sdkCtx.Context(), "tx."+method, trace.WithAttributes(
+		attribute.StringSlice("tx.msg_types", msgTypes),
+		attribute.Int("tx.size", len(req.TxBytes)),
+	))
+
+	return sdk.WrapSDKContext(sdkCtx.WithContext(spanCtx)), span
+}
+
+// recordSpanError sets the span status from the error returned by the next
+// tx.Handler.
+func recordSpanError(span trace.Span, err error) {
+	if err == nil {
+		span.SetStatus(codes.Ok, "")
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,106 @@
+package middleware_test
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+// testSpan is a trace.Span recording the calls made by the tracing middleware.
+type testSpan struct {
+	trace.Span
+
+	name       string
+	attributes []attribute.KeyValue
+	statusCode codes.Code
+	err        error
+	ended      bool
+}
+
+func (s *testSpan) SetStatus(code codes.Code, _ string)           { s.statusCode = code }
+func (s *testSpan) RecordError(err error, _ ...trace.EventOption) { s.err = err }
+func (s *testSpan) End(_ ...trace.SpanEndOption)                  { s.ended = true }
+func (s *testSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.attributes = append(s.attributes, kv...)
+}
+func (s *testSpan) SpanContext() trace.SpanContext            { return trace.SpanContext{} }
+func (s *testSpan) IsRecording() bool                         { return true }
+func (s *testSpan) AddEvent(_ string, _ ...trace.EventOption) {}
+func (s *testSpan) SetName(name string)                       { s.name = name }
+func (s *testSpan) TracerProvider() trace.TracerProvider      { return trace.NewNoopTracerProvider() }
+
+// attribute returns the value of the span attribute with the given key.
+func (s *testSpan) attribute(key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range s.attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+// testTracer is a trace.Tracer keeping track of the started spans.
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	span := &testSpan{name: name, attributes: cfg.Attributes()}
+	t.spans = append(t.spans, span)
+
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func (s *MWTestSuite) TestTracingMiddleware() {
+	testTx, txBytes, ctx, _ := s.setupGasTx()
+	tracer := &testTracer{}
+
+	var innerSpan trace.Span
+	spanCheckTxHandler := customTxHandler{func(ctx context.Context, _ tx.Request) (tx.Response, error) {
+		// the span is available on both the context.Context and sdk.Context
+		innerSpan = trace.SpanFromContext(sdk.UnwrapSDKContext(ctx).Context())
+		s.Require().Equal(innerSpan, trace.SpanFromContext(ctx))
+
+		return tx.Response{}, sdkerrors.ErrInvalidRequest
+	}}
+	txHandler := middleware.ComposeMiddlewares(spanCheckTxHandler, middleware.TracingMiddleware(tracer))
+	req := tx.Request{Tx: testTx, TxBytes: txBytes}
+
+	_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx), req, tx.RequestCheckTx{})
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+
+	txHandler = middleware.ComposeMiddlewares(noopTxHandler, middleware.TracingMiddleware(tracer))
+	_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+
+	s.Require().Len(tracer.spans, 3)
+	s.Require().Equal(tracer.spans[1], innerSpan)
+	for i, expName := range []string{"tx.CheckTx", "tx.DeliverTx", "tx.SimulateTx"} {
+		span := tracer.spans[i]
+		s.Require().Equal(expName, span.name)
+		s.Require().True(span.ended)
+
+		msgTypes, ok := span.attribute("tx.msg_types")
+		s.Require().True(ok)
+		s.Require().Equal([]string{sdk.MsgTypeURL(testTx.GetMsgs()[0])}, msgTypes.AsStringSlice())
+		size, ok := span.attribute("tx.size")
+		s.Require().True(ok)
+		s.Require().Equal(int64(len(txBytes)), size.AsInt64())
+	}
+
+	s.Require().Equal(codes.Error, tracer.spans[1].statusCode)
+	s.Require().ErrorIs(tracer.spans[1].err, sdkerrors.ErrInvalidRequest)
+	s.Require().Equal(codes.Ok, tracer.spans[2].statusCode)
+	s.Require().NoError(tracer.spans[2].err)
+}
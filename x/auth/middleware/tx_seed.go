@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type txSeedKey struct{}
+
+// TxSeed returns the seed of the tx being delivered, as set by
+// TxSeedMiddleware, or nil outside of DeliverTx. Modules can use it to seed
+// deterministic pseudo-random number generators, e.g. to order items fairly
+// within a message.
+func TxSeed(ctx sdk.Context) []byte {
+	seed, _ := ctx.Value(txSeedKey{}).([]byte)
+	return seed
+}
+
+type txSeedTxHandler struct {
+	next tx.Handler
+}
+
+// TxSeedMiddleware defines a middleware that sets in the context of DeliverTx
+// a seed derived as sha256(blockHash || txBytes), from the block header hash
+// and the tx bytes as returned by sdk.Context.TxBytes, which can be read with
+// TxSeed. The seed is the same on all nodes, so randomness derived from it
+// doesn't break consensus. It isn't set in CheckTx and SimulateTx, where the
+// block hash isn't known yet.
+//
+// The seed is not cryptographically secure randomness: it is known to anyone
+// who knows the tx and the block hash, and the proposer can influence it by
+// choosing which txs to include in a block.
+func TxSeedMiddleware(txh tx.Handler) tx.Handler {
+	return txSeedTxHandler{next: txh}
+}
+
+var _ tx.Handler = txSeedTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh txSeedTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh txSeedTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	hash := sha256.New()
+	hash.Write(sdkCtx.HeaderHash())
+	hash.Write(sdkCtx.TxBytes())
+	sdkCtx = sdkCtx.WithValue(txSeedKey{}, hash.Sum(nil))
+
+	return txh.next.DeliverTx(sdk.WrapSDKContext(sdkCtx), req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh txSeedTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.SimulateTx(ctx, req)
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/sha256"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestTxSeedMiddleware() {
+	ctx := s.SetupTest(false).WithHeaderHash([]byte("block hash"))
+	req := tx.Request{Tx: txTest{}}
+
+	var seed []byte
+	seedTxHandler := customTxHandler{func(ctx context.Context, _ tx.Request) (tx.Response, error) {
+		seed = middleware.TxSeed(sdk.UnwrapSDKContext(ctx))
+		return tx.Response{}, nil
+	}}
+	txHandler := middleware.ComposeMiddlewares(seedTxHandler, middleware.TxSeedMiddleware)
+
+	deliverSeed := func(ctx sdk.Context) []byte {
+		_, err := txHandler.DeliverTx(sdk.WrapSDKContext(ctx), req)
+		s.Require().NoError(err)
+		return seed
+	}
+
+	expected := sha256.Sum256([]byte("block hashtx1"))
+	seed1 := deliverSeed(ctx.WithTxBytes([]byte("tx1")))
+	s.Require().Equal(expected[:], seed1)
+
+	// the seed is deterministic
+	s.Require().Equal(seed1, deliverSeed(ctx.WithTxBytes([]byte("tx1"))))
+
+	// and depends on both the tx and the block
+	s.Require().NotEqual(seed1, deliverSeed(ctx.WithTxBytes([]byte("tx2"))))
+	s.Require().NotEqual(seed1, deliverSeed(ctx.WithHeaderHash([]byte("other block")).WithTxBytes([]byte("tx1"))))
+
+	// no seed is set in CheckTx and SimulateTx
+	_, _, err := txHandler.CheckTx(sdk.WrapSDKContext(ctx.WithTxBytes([]byte("tx1"))), req, tx.RequestCheckTx{})
+	s.Require().NoError(err)
+	s.Require().Nil(seed)
+	_, err = txHandler.SimulateTx(sdk.WrapSDKContext(ctx.WithTxBytes([]byte("tx1"))), req)
+	s.Require().NoError(err)
+	s.Require().Nil(seed)
+	s.Require().Nil(middleware.TxSeed(ctx))
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type validateOnlySimulateTxHandler struct {
+	next tx.Handler
+}
+
+// ValidateOnlySimulateMiddleware defines a middleware that short-circuits
+// SimulateTx calls made with a context created with tx.WithValidateOnlySimulate,
+// returning an empty successful response without calling the inner
+// middlewares. It must be placed below the validation middlewares (validate
+// basic, signature verification...) and above the execution of the messages,
+// so that such simulations only tell whether the tx would pass validation,
+// which is cheaper than a full simulation. GasTxMiddleware then reports zero
+// gas used for these simulations.
+//
+// Simulations made with any other context, as well as CheckTx and DeliverTx,
+// are passed through. The Simulate gRPC service opts in per request with the
+// grpctypes.GRPCValidateOnlySimulateHeader header.
+func ValidateOnlySimulateMiddleware(txh tx.Handler) tx.Handler {
+	return validateOnlySimulateTxHandler{next: txh}
+}
+
+var _ tx.Handler = validateOnlySimulateTxHandler{}
+
+// CheckTx implements tx.Handler.CheckTx method.
+func (txh validateOnlySimulateTxHandler) CheckTx(ctx context.Context, req tx.Request, checkReq tx.RequestCheckTx) (tx.Response, tx.ResponseCheckTx, error) {
+	return txh.next.CheckTx(ctx, req, checkReq)
+}
+
+// DeliverTx implements tx.Handler.DeliverTx method.
+func (txh validateOnlySimulateTxHandler) DeliverTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	return txh.next.DeliverTx(ctx, req)
+}
+
+// SimulateTx implements tx.Handler.SimulateTx method.
+func (txh validateOnlySimulateTxHandler) SimulateTx(ctx context.Context, req tx.Request) (tx.Response, error) {
+	if tx.IsValidateOnlySimulate(sdk.UnwrapSDKContext(ctx)) {
+		return tx.Response{}, nil
+	}
+
+	return txh.next.SimulateTx(ctx, req)
+}
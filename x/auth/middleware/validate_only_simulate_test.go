@@ -0,0 +1,90 @@
+package middleware_test
+
+import (
+	"context"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/middleware"
+)
+
+func (s *MWTestSuite) TestValidateOnlySimulateMiddleware() {
+	testTx, _, ctx, _ := s.setupGasTx()
+	req := tx.Request{Tx: testTx}
+
+	executed := false
+	execTxHandler := customTxHandler{func(ctx context.Context, _ tx.Request) (tx.Response, error) {
+		executed = true
+		sdk.UnwrapSDKContext(ctx).GasMeter().ConsumeGas(1000, "execution")
+		return tx.Response{}, nil
+	}}
+	txHandler := middleware.ComposeMiddlewares(execTxHandler, middleware.GasTxMiddleware, middleware.ValidateOnlySimulateMiddleware)
+
+	// simulations are unchanged by default
+	res, err := txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+	s.Require().NoError(err)
+	s.Require().True(executed)
+	s.Require().Equal(uint64(1000), res.GasUsed)
+
+	// validate-only simulations skip execution and report zero gas
+	executed = false
+	validateOnlyCtx := tx.WithValidateOnlySimulate(ctx)
+	s.Require().True(tx.IsValidateOnlySimulate(validateOnlyCtx))
+	s.Require().False(tx.IsValidateOnlySimulate(ctx))
+	res, err = txHandler.SimulateTx(sdk.WrapSDKContext(validateOnlyCtx), req)
+	s.Require().NoError(err)
+	s.Require().False(executed)
+	s.Require().Equal(uint64(0), res.GasUsed)
+
+	// CheckTx and DeliverTx always execute the tx
+	_, _, err = txHandler.CheckTx(sdk.WrapSDKContext(validateOnlyCtx), req, tx.RequestCheckTx{})
+	s.Require().NoError(err)
+	s.Require().True(executed)
+	executed = false
+	_, err = txHandler.DeliverTx(sdk.WrapSDKContext(validateOnlyCtx), req)
+	s.Require().NoError(err)
+	s.Require().True(executed)
+}
+
+func (s *MWTestSuite) TestValidateOnlySimulateDefaultTxHandler() {
+	ctx := s.SetupTest(false).WithBlockHeight(1)
+	accounts := s.createTestAccounts(ctx, 1, sdk.NewCoins(sdk.NewInt64Coin("atom", 1000)))
+
+	// simulations increment the sequence in the store, so each of them is run
+	// on a separate branch
+	simulate := func(ctx sdk.Context, validateOnly bool, req tx.Request) (tx.Response, error) {
+		ctx, _ = ctx.CacheContext()
+		if validateOnly {
+			ctx = tx.WithValidateOnlySimulate(ctx)
+		}
+		return s.txHandler.SimulateTx(sdk.WrapSDKContext(ctx), req)
+	}
+
+	newTx := func(seq uint64) tx.Request {
+		txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+		s.Require().NoError(txBuilder.SetMsgs(testdata.NewTestMsg(accounts[0].acc.GetAddress())))
+		txBuilder.SetFeeAmount(testdata.NewTestFeeAmount())
+		txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+		privs, accNums, accSeqs := []cryptotypes.PrivKey{accounts[0].priv}, []uint64{accounts[0].accNum}, []uint64{seq}
+		testTx, _, err := s.createTestTx(txBuilder, privs, accNums, accSeqs, ctx.ChainID())
+		s.Require().NoError(err)
+		return tx.Request{Tx: testTx}
+	}
+
+	res, err := simulate(ctx, true, newTx(0))
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(0), res.GasUsed)
+	s.Require().Empty(res.MsgResponses)
+
+	res, err = simulate(ctx, false, newTx(0))
+	s.Require().NoError(err)
+	s.Require().NotZero(res.GasUsed)
+	s.Require().Len(res.MsgResponses, 1)
+
+	// validation still runs in validate-only simulations
+	_, err = simulate(ctx, true, newTx(1))
+	s.Require().ErrorIs(err, sdkerrors.ErrWrongSequence)
+}
@@ -5,17 +5,20 @@ import (
 	"fmt"
 	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"strconv"
 	"strings"
 
 	gogogrpc "github.com/gogo/protobuf/grpc"
 	"github.com/golang/protobuf/proto" // nolint: staticcheck
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
 	pagination "github.com/cosmos/cosmos-sdk/types/query"
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 )
@@ -25,18 +28,37 @@ type baseAppSimulateFn func(txBytes []byte) (sdk.GasInfo, *sdk.Result, error)
 
 // txServer is the server for the protobuf Tx service.
 type txServer struct {
-	clientCtx         client.Context
-	simulate          baseAppSimulateFn
-	interfaceRegistry codectypes.InterfaceRegistry
+	clientCtx            client.Context
+	simulate             baseAppSimulateFn
+	simulateValidateOnly baseAppSimulateFn
+	interfaceRegistry    codectypes.InterfaceRegistry
+}
+
+// TxServerOption configures the Tx service server.
+type TxServerOption func(*txServer)
+
+// WithValidateOnlySimulate sets the function, typically
+// Baseapp#SimulateValidateOnly, used by Simulate requests with the
+// grpctypes.GRPCValidateOnlySimulateHeader header set to "true". Without it,
+// such requests are rejected.
+func WithValidateOnlySimulate(simulateValidateOnly baseAppSimulateFn) TxServerOption {
+	return func(s *txServer) {
+		s.simulateValidateOnly = simulateValidateOnly
+	}
 }
 
 // NewTxServer creates a new Tx service server.
-func NewTxServer(clientCtx client.Context, simulate baseAppSimulateFn, interfaceRegistry codectypes.InterfaceRegistry) txtypes.ServiceServer {
-	return txServer{
+func NewTxServer(clientCtx client.Context, simulate baseAppSimulateFn, interfaceRegistry codectypes.InterfaceRegistry, opts ...TxServerOption) txtypes.ServiceServer {
+	s := txServer{
 		clientCtx:         clientCtx,
 		simulate:          simulate,
 		interfaceRegistry: interfaceRegistry,
 	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	return s
 }
 
 var _ txtypes.ServiceServer = txServer{}
@@ -116,7 +138,19 @@ func (s txServer) Simulate(ctx context.Context, req *txtypes.SimulateRequest) (*
 		return nil, status.Errorf(codes.InvalidArgument, "empty txBytes is not allowed")
 	}
 
-	gasInfo, result, err := s.simulate(txBytes)
+	simulate := s.simulate
+	validateOnly, err := isValidateOnlySimulate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if validateOnly {
+		if s.simulateValidateOnly == nil {
+			return nil, status.Error(codes.Unimplemented, "validate-only simulations are not supported")
+		}
+		simulate = s.simulateValidateOnly
+	}
+
+	gasInfo, result, err := simulate(txBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -127,6 +161,28 @@ func (s txServer) Simulate(ctx context.Context, req *txtypes.SimulateRequest) (*
 	}, nil
 }
 
+// isValidateOnlySimulate returns whether the Simulate request opted in to
+// validate-only simulation with the grpctypes.GRPCValidateOnlySimulateHeader
+// header.
+func isValidateOnlySimulate(ctx context.Context) (bool, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false, nil
+	}
+
+	values := md.Get(grpctypes.GRPCValidateOnlySimulateHeader)
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	validateOnly, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "invalid %s header %q", grpctypes.GRPCValidateOnlySimulateHeader, values[0])
+	}
+
+	return validateOnly, nil
+}
+
 // GetTx implements the ServiceServer.GetTx RPC method.
 func (s txServer) GetTx(ctx context.Context, req *txtypes.GetTxRequest) (*txtypes.GetTxResponse, error) {
 	if req == nil {
@@ -248,10 +304,11 @@ func RegisterTxService(
 	clientCtx client.Context,
 	simulateFn baseAppSimulateFn,
 	interfaceRegistry codectypes.InterfaceRegistry,
+	opts ...TxServerOption,
 ) {
 	txtypes.RegisterServiceServer(
 		qrt,
-		NewTxServer(clientCtx, simulateFn, interfaceRegistry),
+		NewTxServer(clientCtx, simulateFn, interfaceRegistry, opts...),
 	)
 }
 
@@ -9,10 +9,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	clienttx "github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
@@ -23,6 +26,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/testutil/testdata"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
 	"github.com/cosmos/cosmos-sdk/types/query"
 	"github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
@@ -160,6 +164,68 @@ func (s IntegrationTestSuite) TestSimulateTx_GRPC() {
 	}
 }
 
+func (s IntegrationTestSuite) TestSimulateTx_ValidateOnly_GRPC() {
+	val := s.network.Validators[0]
+	txBytes, err := val.ClientCtx.TxConfig.TxEncoder()(s.mkTxBuilder().GetTx())
+	s.Require().NoError(err)
+	// simulations don't verify signatures, so the fees can be changed after
+	// signing
+	invalidTxBuilder := s.mkTxBuilder()
+	invalidTxBuilder.SetFeeAmount(sdk.Coins{sdk.NewCoin(s.cfg.BondDenom, s.cfg.AccountTokens.MulRaw(2))})
+	invalidTxBytes, err := val.ClientCtx.TxConfig.TxEncoder()(invalidTxBuilder.GetTx())
+	s.Require().NoError(err)
+
+	// the header is only forwarded by the gRPC server
+	conn, err := grpc.Dial(
+		val.AppConfig.GRPC.Address,
+		grpc.WithInsecure(), // Or else we get "no transport security set"
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(codec.NewProtoCodec(val.ClientCtx.InterfaceRegistry).GRPCCodec())),
+	)
+	s.Require().NoError(err)
+	defer conn.Close()
+	client := tx.NewServiceClient(conn)
+
+	testCases := []struct {
+		name      string
+		header    string
+		txBytes   []byte
+		expErr    bool
+		expErrMsg string
+		expGas    bool
+	}{
+		{"full simulation", "", txBytes, false, "", true},
+		{"explicit full simulation", "false", txBytes, false, "", true},
+		{"validate-only simulation", "true", txBytes, false, "", false},
+		{"validate-only simulation of an invalid tx", "true", invalidTxBytes, true, "insufficient funds", false},
+		{"invalid header", "foo", txBytes, true, "invalid x-cosmos-validate-only-simulate header", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		s.Run(tc.name, func() {
+			ctx := context.Background()
+			if tc.header != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCValidateOnlySimulateHeader, tc.header)
+			}
+
+			res, err := client.Simulate(ctx, &tx.SimulateRequest{TxBytes: tc.txBytes})
+			if tc.expErr {
+				s.Require().Error(err)
+				s.Require().Contains(err.Error(), tc.expErrMsg)
+			} else if tc.expGas {
+				s.Require().NoError(err)
+				s.Require().Len(res.GetResult().MsgResponses, 1)
+				s.Require().True(res.GetGasInfo().GetGasUsed() > 0)
+			} else {
+				// the messages aren't executed and no gas is reported
+				s.Require().NoError(err)
+				s.Require().Empty(res.GetResult().MsgResponses)
+				s.Require().Zero(res.GetGasInfo().GetGasUsed())
+			}
+		})
+	}
+}
+
 func (s IntegrationTestSuite) TestSimulateTx_GRPCGateway() {
 	val := s.network.Validators[0]
 	txBuilder := s.mkTxBuilder()
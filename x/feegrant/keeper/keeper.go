@@ -253,6 +253,20 @@ func (k Keeper) UseGrantedFees(ctx sdk.Context, granter, grantee sdk.AccAddress,
 	return k.UpdateAllowance(ctx, granter, grantee, grant)
 }
 
+// CheckGrantedFees returns an error unless the allowance given by the granter
+// to the grantee accepts the given fee. Contrary to UseGrantedFees, the
+// allowance isn't updated nor revoked, and no event is emitted.
+func (k Keeper) CheckGrantedFees(ctx sdk.Context, granter, grantee sdk.AccAddress, fee sdk.Coins, msgs []sdk.Msg) error {
+	grant, err := k.GetAllowance(ctx, granter, grantee)
+	if err != nil {
+		return err
+	}
+
+	// the allowance is modified in memory only
+	_, err = grant.Accept(ctx, fee, msgs)
+	return err
+}
+
 func emitUseGrantEvent(ctx sdk.Context, granter, grantee string) {
 	ctx.EventManager().EmitEvent(
 		sdk.NewEvent(
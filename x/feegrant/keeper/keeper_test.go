@@ -214,19 +214,29 @@ func (suite *KeeperTestSuite) TestUseGrantedFee() {
 		})
 	}
 
+	// checking the fees doesn't update the allowance
+	err := suite.keeper.GrantAllowance(suite.sdkCtx, suite.addrs[0], suite.addrs[1], future)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.keeper.CheckGrantedFees(suite.sdkCtx, suite.addrs[0], suite.addrs[1], suite.atom, []sdk.Msg{}))
+	suite.Require().Error(suite.keeper.CheckGrantedFees(suite.sdkCtx, suite.addrs[0], suite.addrs[1], hugeAtom, []sdk.Msg{}))
+	loaded, err := suite.keeper.GetAllowance(suite.sdkCtx, suite.addrs[0], suite.addrs[1])
+	suite.Require().NoError(err)
+	suite.Equal(future, loaded)
+
 	basicAllowance := &feegrant.BasicAllowance{
 		SpendLimit: eth,
 		Expiration: &blockTime,
 	}
 
 	// create basic fee allowance
-	err := suite.keeper.GrantAllowance(suite.sdkCtx, suite.addrs[0], suite.addrs[2], basicAllowance)
+	err = suite.keeper.GrantAllowance(suite.sdkCtx, suite.addrs[0], suite.addrs[2], basicAllowance)
 	suite.Require().NoError(err)
 
 	// waiting for future blocks, allowance to be pruned.
 	ctx := suite.sdkCtx.WithBlockTime(oneYear)
 
 	// expect error: feegrant expired
+	suite.Require().Error(suite.keeper.CheckGrantedFees(ctx, suite.addrs[0], suite.addrs[2], eth, []sdk.Msg{}))
 	err = suite.keeper.UseGrantedFees(ctx, suite.addrs[0], suite.addrs[2], eth, []sdk.Msg{})
 	suite.Error(err)
 	suite.Contains(err.Error(), "fee allowance expired")